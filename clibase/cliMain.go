@@ -28,7 +28,7 @@ func ScenariosCLI(version string, vmFlags CLIRunConfig) {
 		{
 			Name:  "run",
 			Usage: "complete a task on the list",
-			Flags: vmFlags.GetFlags(),
+			Flags: append(append(append(vmFlags.GetFlags(), commonRunFlags()...), recordFlags()...), summaryFlags()...),
 			Action: func(cCtx *cli.Context) error {
 				args := cCtx.Args()
 				if args.Len() != 1 {
@@ -36,7 +36,39 @@ func ScenariosCLI(version string, vmFlags CLIRunConfig) {
 				}
 				path := cCtx.Args().First()
 
-				return RunScenariosAtPath(path, vmFlags.ParseFlags(cCtx))
+				options := vmFlags.ParseFlags(cCtx)
+				err := applyCommonRunFlags(cCtx, &options)
+				if err != nil {
+					return err
+				}
+				options.RunOptions.Record = cCtx.Bool(recordFlagName)
+				options.UpdateGolden = cCtx.Bool(updateGoldenFlagName)
+				err = applySummaryFlags(cCtx, &options)
+				if err != nil {
+					return err
+				}
+
+				return RunScenariosAtPath(path, options)
+			},
+		},
+		{
+			Name:  "watch",
+			Usage: "run all scenarios in a folder, then re-run the ones affected by each file change",
+			Flags: append(append(vmFlags.GetFlags(), commonRunFlags()...), watchFlags()...),
+			Action: func(cCtx *cli.Context) error {
+				args := cCtx.Args()
+				if args.Len() != 1 {
+					return errors.New("one path argument required to watch scenarios")
+				}
+				path := cCtx.Args().First()
+
+				options := vmFlags.ParseFlags(cCtx)
+				err := applyCommonRunFlags(cCtx, &options)
+				if err != nil {
+					return err
+				}
+
+				return WatchScenariosAtPath(path, options, cCtx.Duration(intervalFlagName))
 			},
 		},
 		{
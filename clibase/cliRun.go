@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	scenexec "github.com/kalyan3104/k-chain-scenario-go/scenario/executor"
 	scenio "github.com/kalyan3104/k-chain-scenario-go/scenario/io"
@@ -20,13 +22,13 @@ func RunScenariosAtPath(path string, options CLIRunOptions) error {
 		return err
 	}
 
-	executor := scenexec.NewScenarioExecutor(options.VMBuilder)
-	controller := &scenio.ScenarioController{
-		Executor: executor,
-		Parser: scenjparse.NewParser(
-			scenio.NewDefaultFileResolver(),
-			options.VMBuilder.GetVMType()),
+	reporter, closeReport, err := openReporter(options)
+	if err != nil {
+		return err
 	}
+	options.RunOptions.Reporter = reporter
+
+	controller := newScenarioController(options)
 
 	switch {
 	case fi.IsDir():
@@ -34,14 +36,46 @@ func RunScenariosAtPath(path string, options CLIRunOptions) error {
 			path,
 			"",
 			".scen.json",
-			[]string{},
+			options.ExcludePatterns,
 			options.RunOptions)
 	case strings.HasSuffix(path, ".scen.json"):
+		startTime := time.Now()
 		err = controller.RunSingleJSONScenario(path, options.RunOptions)
+		if options.RunOptions.Coverage != nil {
+			coverageReport, coverageErr := options.RunOptions.Coverage.Report(filepath.Dir(path))
+			if err == nil {
+				err = coverageErr
+			}
+			fmt.Print(coverageReport)
+		}
+		if reporter != nil {
+			reportErr := reporter.ReportScenario(scenio.NewScenarioReport(path, time.Since(startTime), err))
+			if err == nil {
+				err = reportErr
+			}
+		}
 	default:
 		err = errors.New("only directories and scenario files accepted as path")
 	}
 
+	generalTestPath := path
+	if !fi.IsDir() {
+		generalTestPath = filepath.Dir(path)
+	}
+	gasProfileErr := writeGasProfile(options, generalTestPath)
+	if err == nil {
+		err = gasProfileErr
+	}
+	gasBaselineErr := finishGasBaseline(options)
+	if err == nil {
+		err = gasBaselineErr
+	}
+
+	reportErr := closeReport()
+	if err == nil {
+		err = reportErr
+	}
+
 	// print result
 	if err == nil {
 		fmt.Println("SUCCESS")
@@ -51,3 +85,93 @@ func RunScenariosAtPath(path string, options CLIRunOptions) error {
 
 	return err
 }
+
+// newScenarioController sets up a controller with executors configured from the CLI options.
+func newScenarioController(options CLIRunOptions) *scenio.ScenarioController {
+	newExecutor := func() scenio.ScenarioRunner {
+		executor := scenexec.NewScenarioExecutor(options.VMBuilder)
+		executor.ApplyOptions(options.ExecutorOptions, options.RunOptions)
+		return executor
+	}
+	return &scenio.ScenarioController{
+		Executor: newExecutor(),
+		Parser: scenjparse.NewParser(
+			scenio.NewDefaultFileResolver(),
+			options.VMBuilder.GetVMType()),
+		NewExecutor: newExecutor,
+	}
+}
+
+// writeGasProfile exports the gas profile, if one was requested, to the configured file or to stdout.
+func writeGasProfile(options CLIRunOptions, generalTestPath string) error {
+	if options.GasProfile == nil {
+		return nil
+	}
+	if len(options.GasProfileFile) == 0 {
+		return options.GasProfile.Write(os.Stdout, options.GasProfileFormat, generalTestPath)
+	}
+
+	profileFile, err := os.Create(options.GasProfileFile)
+	if err != nil {
+		return err
+	}
+	err = options.GasProfile.Write(profileFile, options.GasProfileFormat, generalTestPath)
+	closeErr := profileFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// finishGasBaseline saves the gas baseline, if it was being recorded,
+// or lists the gas regressions found in warning mode.
+func finishGasBaseline(options CLIRunOptions) error {
+	if options.GasBaseline == nil {
+		return nil
+	}
+	if options.GasBaseline.Update {
+		return options.GasBaseline.Save()
+	}
+
+	regressions := options.GasBaseline.Regressions()
+	if len(regressions) > 0 {
+		fmt.Printf("Gas regressions, above the %.2f%% allowed:\n", options.GasBaseline.MaxIncreasePercent)
+		for _, regression := range regressions {
+			fmt.Printf("  %s\n", regression.String())
+		}
+	}
+	return nil
+}
+
+// openReporter creates the reporter selected in the options, if any.
+// The returned function finishes the report and closes the report file.
+func openReporter(options CLIRunOptions) (scenio.Reporter, func() error, error) {
+	if len(options.ReportFormat) == 0 {
+		return nil, func() error { return nil }, nil
+	}
+
+	if len(options.ReportFile) == 0 {
+		return nil, nil, errors.New("no file given for the test report")
+	}
+	reportFile, err := os.Create(options.ReportFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reporter, err := scenio.NewReporter(options.ReportFormat, reportFile)
+	if err != nil {
+		_ = reportFile.Close()
+		return nil, nil, err
+	}
+
+	closeReport := func() error {
+		err := reporter.Finish()
+		closeErr := reportFile.Close()
+		if err == nil {
+			err = closeErr
+		}
+		return err
+	}
+
+	return reporter, closeReport, nil
+}
@@ -0,0 +1,137 @@
+package scenclibase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	fr "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/fileresolver"
+	scenio "github.com/kalyan3104/k-chain-scenario-go/scenario/io"
+)
+
+const scenarioFileSuffix = ".scen.json"
+
+// WatchScenariosAtPath runs all scenarios in a directory, then keeps polling for file changes.
+// On every change, it only re-runs the scenarios that depend on the changed files,
+// directly or via external steps.
+// It only returns if the directory cannot be read.
+func WatchScenariosAtPath(path string, options CLIRunOptions, interval time.Duration) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errors.New("only directories can be watched")
+	}
+
+	controller := newScenarioController(options)
+	watcher := newScenarioWatcher(path, controller.Parser.ExprInterpreter.FileResolver)
+	for {
+		scenarioPaths, err := watcher.poll()
+		if err != nil {
+			return err
+		}
+
+		if len(scenarioPaths) > 0 {
+			err = controller.RunJSONScenarioFiles(path, scenarioPaths, options.ExcludePatterns, options.RunOptions)
+			if err == nil {
+				fmt.Println("SUCCESS")
+			} else {
+				fmt.Printf("ERROR: %s\n", err.Error())
+			}
+			fmt.Printf("Watching %s for changes ...\n", path)
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// scenarioWatcher detects file changes by polling modification times.
+type scenarioWatcher struct {
+	dirPath  string
+	graph    *scenio.DependencyGraph
+	modTimes map[string]time.Time
+}
+
+func newScenarioWatcher(dirPath string, fileResolver fr.FileResolver) *scenarioWatcher {
+	return &scenarioWatcher{
+		dirPath:  dirPath,
+		graph:    scenio.NewDependencyGraph(fileResolver),
+		modTimes: make(map[string]time.Time),
+	}
+}
+
+// poll yields the scenarios affected by the changes since the previous call.
+// The first call yields all scenarios.
+func (w *scenarioWatcher) poll() ([]string, error) {
+	scenarioPaths, err := scenio.FindScenarioFiles(w.dirPath, scenarioFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	watchedPaths := make([]string, 0, len(scenarioPaths))
+	newScenarios := make(map[string]bool)
+	for _, scenarioPath := range scenarioPaths {
+		absPath, err := filepath.Abs(scenarioPath)
+		if err != nil {
+			return nil, err
+		}
+		if !w.graph.Contains(absPath) {
+			// parse errors are reported when running the scenario
+			_ = w.graph.Update(absPath)
+			newScenarios[absPath] = true
+		}
+		watchedPaths = append(watchedPaths, absPath)
+	}
+	watchedPaths = append(watchedPaths, w.graph.Files()...)
+
+	currentModTimes := readModTimes(watchedPaths)
+	changedFiles := make(map[string]bool)
+	for filePath, modTime := range currentModTimes {
+		previousModTime, existed := w.modTimes[filePath]
+		if !existed || !previousModTime.Equal(modTime) {
+			changedFiles[filePath] = true
+		}
+	}
+	for filePath := range w.modTimes {
+		if _, exists := currentModTimes[filePath]; !exists {
+			changedFiles[filePath] = true
+		}
+	}
+	w.modTimes = currentModTimes
+
+	for filePath := range changedFiles {
+		if w.graph.Contains(filePath) && !newScenarios[filePath] {
+			_ = w.graph.Update(filePath)
+		}
+	}
+	// files newly referenced by the updated scenarios are not changes themselves
+	for filePath, modTime := range readModTimes(w.graph.Files()) {
+		if _, known := w.modTimes[filePath]; !known {
+			w.modTimes[filePath] = modTime
+		}
+	}
+
+	var affectedPaths []string
+	for _, scenarioPath := range scenarioPaths {
+		if w.graph.DependsOn(scenarioPath, changedFiles) {
+			affectedPaths = append(affectedPaths, scenarioPath)
+		}
+	}
+	return affectedPaths, nil
+}
+
+// readModTimes yields the modification times of the given files. Missing files are left out.
+func readModTimes(filePaths []string) map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, filePath := range filePaths {
+		fi, err := os.Stat(filePath)
+		if err != nil {
+			continue
+		}
+		modTimes[filePath] = fi.ModTime()
+	}
+	return modTimes
+}
@@ -11,6 +11,24 @@ import (
 type CLIRunOptions struct {
 	RunOptions *scenio.RunScenarioOptions
 	VMBuilder  scenexec.VMBuilder
+
+	// ReportFormat selects a machine-readable test report (see scenio.NewReporter). Empty means no report.
+	ReportFormat string
+
+	// ReportFile is where the test report gets written. Required if ReportFormat is set.
+	ReportFile string
+
+	// ExecutorOptions configure every executor of the run, see scenexec.ScenarioExecutor.ApplyOptions.
+	scenexec.ExecutorOptions
+
+	// GasProfileFormat is scenio.GasProfileJSON or scenio.GasProfileFolded.
+	GasProfileFormat string
+
+	// GasProfileFile is where the gas profile gets written. Empty means stdout.
+	GasProfileFile string
+
+	// ExcludePatterns are globs of scenario files to skip, relative to the directory being run.
+	ExcludePatterns []string
 }
 
 // CLIRunConfig prepares and interprets CLI flags required to run scenarios at a path.
@@ -0,0 +1,237 @@
+package scenclibase
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	scenexec "github.com/kalyan3104/k-chain-scenario-go/scenario/executor"
+	scenio "github.com/kalyan3104/k-chain-scenario-go/scenario/io"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+const jobsFlagName = "jobs"
+const reportFlagName = "report"
+const reportFileFlagName = "report-file"
+const allMismatchesFlagName = "all-mismatches"
+const diffFlagName = "diff"
+const includeFlagName = "include"
+const excludeFlagName = "exclude"
+const runFlagName = "run"
+const tagsFlagName = "tags"
+const skipTagsFlagName = "skip-tags"
+const intervalFlagName = "interval"
+const recordFlagName = "record"
+const updateGoldenFlagName = "update-golden"
+const coverageFlagName = "coverage"
+const gasProfileFlagName = "gas-profile"
+const gasProfileFileFlagName = "gas-profile-file"
+const gasBaselineFlagName = "gas-baseline"
+const gasBaselineToleranceFlagName = "gas-baseline-tolerance"
+const gasBaselineWarnFlagName = "gas-baseline-warn"
+const updateGasBaselineFlagName = "update-gas-baseline"
+
+// commonRunFlags are the run command flags that do not depend on the VM.
+// They are added on top of the flags provided by the CLIRunConfig.
+func commonRunFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  jobsFlagName,
+			Usage: "number of scenarios to run in parallel, when running a directory",
+			Value: 1,
+		},
+		&cli.StringFlag{
+			Name:  reportFlagName,
+			Usage: "write a machine-readable test report, in one of the formats: json, junit; requires --report-file",
+		},
+		&cli.StringFlag{
+			Name:  reportFileFlagName,
+			Usage: "file where the test report is written",
+		},
+		&cli.BoolFlag{
+			Name:  allMismatchesFlagName,
+			Usage: "do not stop at the first mismatch in a checkState or tx result, list all differences instead",
+		},
+		&cli.StringFlag{
+			Name:  diffFlagName,
+			Usage: "display a diff of the accounts failing a checkState, in one of the styles: color, plain",
+		},
+		&cli.StringSliceFlag{
+			Name:  includeFlagName,
+			Usage: "only run the scenario files matching this glob, relative to the directory; can be repeated",
+		},
+		&cli.StringSliceFlag{
+			Name:  excludeFlagName,
+			Usage: "skip the scenario files matching this glob, relative to the directory; can be repeated",
+		},
+		&cli.StringFlag{
+			Name:  runFlagName,
+			Usage: "only run the scenarios whose name matches this regular expression",
+		},
+		&cli.StringSliceFlag{
+			Name:  tagsFlagName,
+			Usage: "only run the scenarios having at least one of these tags",
+		},
+		&cli.StringSliceFlag{
+			Name:  skipTagsFlagName,
+			Usage: "skip the scenarios having any of these tags",
+		},
+	}
+}
+
+// recordFlags are the run command flags that rewrite scenario or golden files.
+// They are not offered in watch mode, since the rewritten files would trigger new runs.
+func recordFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  recordFlagName,
+			Usage: "fill in missing, \"*\" or failing expectations from the actual results and rewrite the scenario files",
+		},
+		&cli.BoolFlag{
+			Name:  updateGoldenFlagName,
+			Usage: "regenerate the golden files of checkState steps from the state of the world",
+		},
+		&cli.BoolFlag{
+			Name:  updateGasBaselineFlagName,
+			Usage: "record the gas used by every tx step in the --gas-baseline file, instead of comparing against it",
+		},
+	}
+}
+
+// summaryFlags are the run command flags that report on all the scenarios run.
+// They are not offered in watch mode, where the same scenarios get run again and again.
+func summaryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  coverageFlagName,
+			Usage: "print which endpoints of each contract were called, and which were never called, if their ABI is found",
+		},
+		&cli.StringFlag{
+			Name:  gasProfileFlagName,
+			Usage: "trace gas and write the gas used per scenario, tx step and contract function, in one of the formats: json, folded",
+		},
+		&cli.StringFlag{
+			Name:  gasProfileFileFlagName,
+			Usage: "file where the gas profile is written; the profile goes to stdout if not specified",
+		},
+		&cli.StringFlag{
+			Name:  gasBaselineFlagName,
+			Usage: "JSON file with the gas used by every tx step; tx steps using more gas than that fail",
+		},
+		&cli.Float64Flag{
+			Name:  gasBaselineToleranceFlagName,
+			Usage: "percentage by which a tx step can exceed its --gas-baseline gas before failing",
+			Value: 5,
+		},
+		&cli.BoolFlag{
+			Name:  gasBaselineWarnFlagName,
+			Usage: "only list the tx steps exceeding their --gas-baseline gas, instead of failing them",
+		},
+	}
+}
+
+// applySummaryFlags interprets the flags defined in summaryFlags.
+func applySummaryFlags(cCtx *cli.Context, options *CLIRunOptions) error {
+	if cCtx.Bool(coverageFlagName) {
+		options.RunOptions.Coverage = scenio.NewCoverageCollector()
+	}
+
+	gasProfileFormat := cCtx.String(gasProfileFlagName)
+	if len(gasProfileFormat) > 0 {
+		if !scenio.IsGasProfileFormat(gasProfileFormat) {
+			return fmt.Errorf("invalid --%s format: %s", gasProfileFlagName, gasProfileFormat)
+		}
+		options.GasProfile = scenio.NewGasProfile()
+		options.GasProfileFormat = gasProfileFormat
+		options.GasProfileFile = cCtx.String(gasProfileFileFlagName)
+		options.RunOptions.ForceTraceGas = true
+	}
+
+	gasBaselinePath := cCtx.String(gasBaselineFlagName)
+	if len(gasBaselinePath) > 0 {
+		gasBaseline, err := scenio.LoadGasBaseline(gasBaselinePath)
+		if err != nil {
+			return err
+		}
+		gasBaseline.Update = cCtx.Bool(updateGasBaselineFlagName)
+		gasBaseline.MaxIncreasePercent = cCtx.Float64(gasBaselineToleranceFlagName)
+		gasBaseline.WarnOnly = cCtx.Bool(gasBaselineWarnFlagName)
+		options.GasBaseline = gasBaseline
+	} else if cCtx.Bool(updateGasBaselineFlagName) {
+		return fmt.Errorf("--%s requires --%s", updateGasBaselineFlagName, gasBaselineFlagName)
+	}
+
+	return nil
+}
+
+// watchFlags are the flags specific to the watch command.
+func watchFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:  intervalFlagName,
+			Usage: "how often to check for file changes",
+			Value: time.Second,
+		},
+	}
+}
+
+// applyCommonRunFlags interprets the flags defined in commonRunFlags.
+func applyCommonRunFlags(cCtx *cli.Context, options *CLIRunOptions) error {
+	if options.RunOptions == nil {
+		options.RunOptions = scenio.DefaultRunScenarioOptions()
+	}
+	options.RunOptions.Jobs = cCtx.Int(jobsFlagName)
+	options.ReportFormat = cCtx.String(reportFlagName)
+	options.ReportFile = cCtx.String(reportFileFlagName)
+	if len(options.ReportFormat) > 0 && len(options.ReportFile) == 0 {
+		// stdout already carries the human-readable results, the report would get mixed in with them
+		return fmt.Errorf("--%s requires --%s", reportFlagName, reportFileFlagName)
+	}
+	options.CollectAllMismatches = cCtx.Bool(allMismatchesFlagName)
+	switch diffStyle := cCtx.String(diffFlagName); diffStyle {
+	case "":
+		options.AccountDiff = scenexec.NoAccountDiff
+	case "plain":
+		options.AccountDiff = scenexec.PlainAccountDiff
+	case "color":
+		options.AccountDiff = scenexec.ColorAccountDiff
+	default:
+		return fmt.Errorf("invalid --%s style: %s", diffFlagName, diffStyle)
+	}
+	options.ExcludePatterns = cCtx.StringSlice(excludeFlagName)
+	if err := checkFilePatterns(excludeFlagName, options.ExcludePatterns); err != nil {
+		return err
+	}
+
+	filter := &scenio.ScenarioFilter{
+		IncludePatterns: cCtx.StringSlice(includeFlagName),
+		Tags:            cCtx.StringSlice(tagsFlagName),
+		SkipTags:        cCtx.StringSlice(skipTagsFlagName),
+	}
+	if nameExpr := cCtx.String(runFlagName); len(nameExpr) > 0 {
+		nameRegex, err := regexp.Compile(nameExpr)
+		if err != nil {
+			return fmt.Errorf("invalid --%s expression: %w", runFlagName, err)
+		}
+		filter.NameRegex = nameRegex
+	}
+	if err := checkFilePatterns(includeFlagName, filter.IncludePatterns); err != nil {
+		return err
+	}
+	options.RunOptions.Filter = filter
+
+	return nil
+}
+
+// checkFilePatterns rejects malformed globs before any scenario gets run.
+func checkFilePatterns(flagName string, patterns []string) error {
+	for _, pattern := range patterns {
+		// the only error filepath.Match returns is filepath.ErrBadPattern, which does not depend on the path
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --%s pattern %q: %w", flagName, pattern, err)
+		}
+	}
+	return nil
+}
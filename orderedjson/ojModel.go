@@ -21,10 +21,25 @@ type OJsonKeyValuePair struct {
 type OJsonMap struct {
 	KeySet    map[string]bool
 	OrderedKV []*OJsonKeyValuePair
+
+	// Position is where the map starts in the parsed input. Zero for maps not created by the parser.
+	Position OJsonPosition
+}
+
+// OJsonPosition is a location in the JSON source, both line and column starting at 1.
+// The column counts characters (runes), not bytes.
+type OJsonPosition struct {
+	Line   int
+	Column int
 }
 
 // OJsonList is a JSON list.
-type OJsonList []OJsonObject
+type OJsonList struct {
+	Items []OJsonObject
+
+	// Position is where the list starts in the parsed input. Zero for lists not created by the parser.
+	Position OJsonPosition
+}
 
 // OJsonString is a JSON string value.
 type OJsonString struct {
@@ -34,6 +49,14 @@ type OJsonString struct {
 // OJsonBool is a JSON bool value.
 type OJsonBool bool
 
+// OJsonNumber is a JSON number value, kept in its original textual form.
+type OJsonNumber struct {
+	Value string
+}
+
+// OJsonNull is the JSON null value.
+type OJsonNull struct{}
+
 // NewMap is a create new ordered "map" instance.
 func NewMap() *OJsonMap {
 	KeySet := make(map[string]bool)
@@ -73,5 +96,5 @@ func (j *OJsonMap) KeyValuePairsSortedByKey() []*OJsonKeyValuePair {
 
 // AsList converts a JSON list to a slice of objects.
 func (j *OJsonList) AsList() []OJsonObject {
-	return *j
+	return j.Items
 }
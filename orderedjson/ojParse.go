@@ -3,9 +3,13 @@ package orderedjson
 import (
 	"bytes"
 	"errors"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
+var numberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
 type jsonParserState interface {
 }
 
@@ -27,7 +31,7 @@ type jsonStateMapKeyValue struct {
 }
 
 type jsonParserStateList struct {
-	list OJsonList
+	list *OJsonList
 }
 
 func isWhitespace(c byte) bool {
@@ -39,8 +43,17 @@ func ParseOrderedJSON(input []byte) (OJsonObject, error) {
 	stateStack := &jsonParserStateStack{}
 	stateStack.push(&jsonParserStateAnyObjPlaceholder{})
 	var pendingResult OJsonObject
+	line, column := 1, 0
 
 	for i, c := range input {
+		if i > 0 && input[i-1] == '\n' {
+			line++
+			column = 0
+		}
+		if utf8.RuneStart(c) {
+			column++
+		}
+
 		done := false
 		for !done {
 			done = true
@@ -63,10 +76,13 @@ func ParseOrderedJSON(input []byte) (OJsonObject, error) {
 					// leading whitespace, ignore
 				} else if c == '{' {
 					// replace with map state
-					stateStack.replaceTop(&jsonParserStateMap{currentMap: NewMap()})
+					currentMap := NewMap()
+					currentMap.Position = OJsonPosition{Line: line, Column: column}
+					stateStack.replaceTop(&jsonParserStateMap{currentMap: currentMap})
 				} else if c == '[' {
 					// replace with list state
-					stateStack.replaceTop(&jsonParserStateList{})
+					currentList := &OJsonList{Position: OJsonPosition{Line: line, Column: column}}
+					stateStack.replaceTop(&jsonParserStateList{list: currentList})
 				} else if c == ']' || c == '}' || c == ',' {
 					return nil, errors.New("misplaced character")
 				} else {
@@ -106,16 +122,16 @@ func ParseOrderedJSON(input []byte) (OJsonObject, error) {
 				}
 			case *jsonParserStateList:
 				if pendingResult != nil {
-					specificState.list = append(specificState.list, pendingResult)
+					specificState.list.Items = append(specificState.list.Items, pendingResult)
 					pendingResult = nil
 				}
 				if isWhitespace(c) {
 					// ignore
 				} else {
 					if c == ']' {
-						pendingResult = &specificState.list
+						pendingResult = specificState.list
 						stateStack.pop()
-					} else if len(specificState.list) == 0 {
+					} else if len(specificState.list.Items) == 0 {
 						// new empty list
 						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
 						done = false
@@ -191,6 +207,18 @@ func ParseOrderedJSON(input []byte) (OJsonObject, error) {
 		}
 	}
 
+	// a top-level number or bool only ends with the input
+	if stateStack.size() == 1 {
+		if singleValue, isSingleValue := stateStack.peek().(*jsonParserStateSingleValue); isSingleValue {
+			stateStack.pop()
+			var err error
+			pendingResult, err = singleValue.finalize()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if stateStack.size() != 0 {
 		return nil, errors.New("state stack should be empty at the end")
 	}
@@ -212,6 +240,12 @@ func (s *jsonParserStateSingleValue) finalize() (OJsonObject, error) {
 		result := OJsonBool(false)
 		return &result, nil
 	}
+	if str == "null" {
+		return &OJsonNull{}, nil
+	}
+	if numberPattern.MatchString(str) {
+		return &OJsonNumber{Value: str}, nil
+	}
 	return nil, errors.New("Invalid value: " + str)
 }
 
@@ -0,0 +1,26 @@
+package orderedjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePositions(t *testing.T) {
+	input := "{\n" +
+		"    \"é\": [\"ü\", [],\n" +
+		"        {}]\n" +
+		"}"
+
+	root, err := ParseOrderedJSON([]byte(input))
+	require.Nil(t, err)
+	rootMap := root.(*OJsonMap)
+	require.Equal(t, OJsonPosition{Line: 1, Column: 1}, rootMap.Position)
+
+	// columns count characters, so the multi-byte key does not shift them
+	list := rootMap.OrderedKV[0].Value.(*OJsonList)
+	require.Equal(t, OJsonPosition{Line: 2, Column: 10}, list.Position)
+	require.Len(t, list.Items, 3)
+	require.Equal(t, OJsonPosition{Line: 2, Column: 16}, list.Items[1].(*OJsonList).Position)
+	require.Equal(t, OJsonPosition{Line: 3, Column: 9}, list.Items[2].(*OJsonMap).Position)
+}
@@ -71,3 +71,11 @@ func (j *OJsonString) writeJSON(sb *strings.Builder, _ int) {
 func (j *OJsonBool) writeJSON(sb *strings.Builder, _ int) {
 	sb.WriteString(fmt.Sprintf("%v", bool(*j)))
 }
+
+func (j *OJsonNumber) writeJSON(sb *strings.Builder, _ int) {
+	sb.WriteString(j.Value)
+}
+
+func (j *OJsonNull) writeJSON(sb *strings.Builder, _ int) {
+	sb.WriteString("null")
+}
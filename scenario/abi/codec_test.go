@@ -0,0 +1,327 @@
+package scenabi
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `{
+	"name": "Example",
+	"constructor": {
+		"inputs": [{"name": "initial", "type": "BigUint"}],
+		"outputs": []
+	},
+	"endpoints": [
+		{
+			"name": "setItem",
+			"inputs": [
+				{"name": "item", "type": "Item"},
+				{"name": "limit", "type": "optional<u32>", "multi_arg": true}
+			],
+			"outputs": [{"type": "variadic<u8>", "multi_arg": true}]
+		}
+	],
+	"events": [
+		{
+			"identifier": "itemSet",
+			"inputs": [
+				{"name": "caller", "type": "Address", "indexed": true},
+				{"name": "status", "type": "Status"},
+				{"name": "item", "type": "Item"}
+			]
+		}
+	],
+	"storage": {
+		"item": "SingleValueMapper<Item>",
+		"items": "VecMapper<Item>",
+		"statuses": "Status"
+	},
+	"types": {
+		"Item": {
+			"type": "struct",
+			"fields": [
+				{"name": "a", "type": "u32"},
+				{"name": "b", "type": "ManagedBuffer"},
+				{"name": "tags", "type": "List<u16>"}
+			]
+		},
+		"Status": {
+			"type": "enum",
+			"variants": [
+				{"name": "Inactive", "discriminant": 0},
+				{"name": "Active", "discriminant": 1}
+			]
+		},
+		"Action": {
+			"type": "enum",
+			"variants": [
+				{"name": "Nothing", "discriminant": 0},
+				{
+					"name": "Send",
+					"discriminant": 1,
+					"fields": [
+						{"name": "to", "type": "Address"},
+						{"name": "amount", "type": "BigUint"}
+					]
+				}
+			]
+		}
+	}
+}`
+
+func testCodec(t *testing.T) (*Encoder, *Decoder) {
+	abi, err := ParseABI([]byte(testABI))
+	require.Nil(t, err)
+	encoder := &Encoder{ABI: abi, Interpreter: &ei.ExprInterpreter{}}
+	decoder := &Decoder{
+		ABI: abi,
+		FormatAddress: func(address []byte) string {
+			return "address:" + strings.TrimRight(string(address), "_")
+		},
+	}
+	return encoder, decoder
+}
+
+func parseValue(t *testing.T, valueJSON string) oj.OJsonObject {
+	value, err := oj.ParseOrderedJSON([]byte(valueJSON))
+	require.Nil(t, err)
+	return value
+}
+
+func TestEncodeDecode(t *testing.T) {
+	encoder, decoder := testCodec(t)
+	ownerHex := hex.EncodeToString([]byte("owner" + strings.Repeat("_", 27)))
+
+	testCases := []struct {
+		typeName string
+		value    string
+		encoded  string
+		decoded  string
+	}{
+		{"u32", `5`, "05", `5`},
+		{"u32", `"0"`, "", `0`},
+		{"u8", `255`, "ff", `255`},
+		{"i16", `-2`, "fe", `-2`},
+		{"i16", `"-2"`, "fe", `-2`},
+		{"BigUint", `"rewa:1"`, "0de0b6b3a7640000", `1000000000000000000`},
+		{"BigInt", `-1`, "ff", `-1`},
+		{"bool", `true`, "01", `true`},
+		{"bool", `false`, "", `false`},
+		{"ManagedBuffer", `"str:abc"`, "616263", `"str:abc"`},
+		{"ManagedBuffer", `"0x00ff"`, "00ff", `"0x00ff"`},
+		{"Address", `"address:owner"`, ownerHex, `"address:owner"`},
+		{"Option<u32>", `null`, "", `null`},
+		{"Option<u32>", `7`, "0100000007", `7`},
+		{"Option<Status>", `"Inactive"`, "0100", `"Inactive"`},
+		{"List<u16>", `[1, 2]`, "00010002", `[1, 2]`},
+		{"List<u8>", `"str:ab"`, "6162", `[97, 98]`},
+		{"List<ManagedBuffer>", `["str:a", ""]`, "000000016100000000", `["str:a", ""]`},
+		{"Option<List<u8>>", `[1]`, "010000000101", `[1]`},
+		{"array2<u8>", `"0x0102"`, "0102", `[1, 2]`},
+		{"tuple<u8,bool>", `[1, true]`, "0101", `[1, true]`},
+		{"Item", `{"a": 5, "b": "str:x", "tags": [3]}`, "00000005" + "0000000178" + "00000001" + "0003",
+			`{"a": 5, "b": "str:x", "tags": [3]}`},
+		{"Status", `"Active"`, "01", `"Active"`},
+		{"Status", `"Inactive"`, "", `"Inactive"`},
+		{"Action", `"Nothing"`, "00", `"Nothing"`},
+		{"Action", `{"Send": {"to": "address:owner", "amount": 10}}`, "01" + ownerHex + "000000010a",
+			`{"Send": {"to": "address:owner", "amount": 10}}`},
+	}
+
+	for _, testCase := range testCases {
+		encoded, err := encoder.EncodeTopLevel(testCase.typeName, parseValue(t, testCase.value))
+		require.Nil(t, err, testCase.typeName+" "+testCase.value)
+		require.Equal(t, testCase.encoded, hex.EncodeToString(encoded), testCase.typeName+" "+testCase.value)
+
+		decoded, err := decoder.DecodeTopLevel(testCase.typeName, encoded)
+		require.Nil(t, err, testCase.typeName+" "+testCase.value)
+		require.Equal(t, testCase.decoded, SingleLineJSON(decoded), testCase.typeName+" "+testCase.value)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	encoder, _ := testCodec(t)
+
+	testCases := []struct {
+		typeName string
+		value    string
+		err      string
+	}{
+		{"u8", `256`, "value 256 does not fit in u8"},
+		{"u32", `-1`, "negative value -1 for u32"},
+		{"u32", `1.5`, "1.5 is not an integer"},
+		{"u32", `true`, "expected a number for u32"},
+		{"Address", `"str:short"`, "expected 32 bytes for Address, got 5"},
+		{"Item", `{"a": 5, "b": "str:x"}`, "missing field tags in Item"},
+		{"Item", `{"a": 5, "b": "str:x", "tags": [], "c": 1}`, "unknown field c in Item"},
+		{"Item", `{"a": 5, "b": "str:x", "tags": [1, 65536]}`, "field tags: item 1: value 65536 does not fit in u16"},
+		{"Status", `"Paused"`, "unknown variant Paused of Status"},
+		{"Action", `"Send"`, "missing field to in Action"},
+		{"tuple<u8,bool>", `[1]`, "expected 2 items for tuple<u8,bool>, got 1"},
+		{"Unknown", `1`, "unknown ABI type: Unknown"},
+		{"List<variadic<u8>>", `[1]`, "item 0: variadic<u8> is only allowed as an endpoint argument or result"},
+		{"List<u8", `[1]`, "invalid ABI type: List<u8"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := encoder.EncodeTopLevel(testCase.typeName, parseValue(t, testCase.value))
+		require.EqualError(t, err, testCase.err, testCase.typeName+" "+testCase.value)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, decoder := testCodec(t)
+
+	_, err := decoder.DecodeTopLevel("u16", []byte{1, 2, 3})
+	require.EqualError(t, err, "cannot decode 0x010203 as u16: too many bytes for u16")
+
+	_, err = decoder.DecodeTopLevel("Item", []byte{0, 0, 0, 5, 0, 0})
+	require.EqualError(t, err, "cannot decode 0x000000050000 as Item: field b: not enough bytes")
+
+	_, err = decoder.DecodeTopLevel("tuple<u8,u8>", []byte{1, 2, 3})
+	require.EqualError(t, err, "cannot decode 0x010203 as tuple<u8,u8>: 1 unexpected bytes at the end")
+
+	_, err = decoder.DecodeTopLevel("Status", []byte{2})
+	require.EqualError(t, err, "cannot decode 0x02 as Status: unknown discriminant 2 for Status")
+}
+
+func TestParamTypes(t *testing.T) {
+	abi, err := ParseABI([]byte(testABI))
+	require.Nil(t, err)
+	inputs := abi.EndpointFor(scenmodel.ScCall, "setItem").Inputs
+	outputs := abi.EndpointFor(scenmodel.ScCall, "setItem").Outputs
+
+	types, err := ParamTypes(inputs, 1)
+	require.Nil(t, err)
+	require.Equal(t, []string{"Item"}, types)
+
+	types, err = ParamTypes(inputs, 2)
+	require.Nil(t, err)
+	require.Equal(t, []string{"Item", "u32"}, types)
+
+	_, err = ParamTypes(inputs, 3)
+	require.EqualError(t, err, "too many values, expected 2")
+
+	_, err = ParamTypes(inputs, 0)
+	require.EqualError(t, err, "missing value for item (Item)")
+
+	// further values may follow the leading ones
+	types, err = LeadingParamTypes(inputs, 0)
+	require.Nil(t, err)
+	require.Empty(t, types)
+
+	_, err = LeadingParamTypes(inputs, 3)
+	require.EqualError(t, err, "too many values, expected 2")
+
+	types, err = ParamTypes(outputs, 3)
+	require.Nil(t, err)
+	require.Equal(t, []string{"u8", "u8", "u8"}, types)
+
+	require.Equal(t, abi.Constructor, abi.EndpointFor(scenmodel.ScDeploy, ""))
+	require.Equal(t, abi.Constructor, abi.EndpointFor(scenmodel.ScUpgrade, ""))
+	require.Nil(t, abi.EndpointFor(scenmodel.ScCall, "missing"))
+}
+
+func TestDecodeTopLevelTree(t *testing.T) {
+	_, decoder := testCodec(t)
+	interpreter := &ei.ExprInterpreter{}
+
+	testCases := []struct {
+		typeName string
+		encoded  string
+		tree     string
+	}{
+		{"u32", "05", `"5"`},
+		{"i16", "fe", `"-2"`},
+		{"i16", "7f", `"127"`},
+		{"i16", "0080", `"0x0080"`},
+		{"bool", "01", `"true"`},
+		{"bool", "", `"false"`},
+		{"ManagedBuffer", "616263", `"str:abc"`},
+		{"Option<u32>", "", `""`},
+		{"Option<u32>", "0100000007", `["u8:1", "u32:7"]`},
+		{"List<u16>", "00010002", `["u16:1", "u16:2"]`},
+		{"Status", "01", `{"Active": "1"}`},
+		{"Status", "", `{"Inactive": "0"}`},
+		{"Item", "00000005" + "0000000178" + "00000001" + "0003",
+			`[{"a": "u32:5"}, {"b": "nested:str:x"}, {"tags": ["u32:1", "u16:3"]}]`},
+		{"Action", "00", `{"Nothing": "u8:0"}`},
+		{"Action", "01" + hex.EncodeToString([]byte("owner"+strings.Repeat("_", 27))) + "000000010a",
+			`[{"Send": "u8:1"}, {"to": "address:owner"}, {"amount": "biguint:10"}]`},
+	}
+
+	for _, testCase := range testCases {
+		encoded, err := hex.DecodeString(testCase.encoded)
+		require.Nil(t, err)
+		tree, err := decoder.DecodeTopLevelTree(testCase.typeName, encoded)
+		require.Nil(t, err, testCase.typeName+" "+testCase.encoded)
+		require.Equal(t, testCase.tree, SingleLineJSON(tree), testCase.typeName+" "+testCase.encoded)
+
+		// the tree evaluates back to the value
+		interpreted, err := interpreter.InterpretSubTree(tree)
+		require.Nil(t, err, testCase.typeName+" "+testCase.encoded)
+		require.Equal(t, testCase.encoded, hex.EncodeToString(interpreted), testCase.typeName+" "+testCase.encoded)
+	}
+}
+
+func TestStorageValueType(t *testing.T) {
+	abi, err := ParseABI([]byte(testABI))
+	require.Nil(t, err)
+
+	testCases := []struct {
+		key       string
+		valueType string
+		matched   string
+	}{
+		{"item", "Item", "item"},
+		{"items.len", "u32", "items.len"},
+		{"items.item\x00\x00\x00\x01", "Item", "items.item"},
+		{"statuses" + "owner", "Status", "statuses"},
+	}
+	for _, testCase := range testCases {
+		key := []byte(testCase.key)
+		valueType, matched, found := StorageValueType(abi, key)
+		require.True(t, found, testCase.key)
+		require.Equal(t, testCase.valueType, valueType, testCase.key)
+		require.Equal(t, testCase.matched, string(matched), testCase.key)
+	}
+
+	for _, key := range []string{"other", "ite", "statuse"} {
+		_, _, found := StorageValueType(abi, []byte(key))
+		require.False(t, found, key)
+	}
+}
+
+func TestDecodeEventTrees(t *testing.T) {
+	_, decoder := testCodec(t)
+	owner := []byte("owner" + strings.Repeat("_", 27))
+	item, err := hex.DecodeString("00000005" + "0000000178" + "00000000")
+	require.Nil(t, err)
+
+	topicTrees, dataTrees, err := decoder.DecodeEventTrees([][]byte{[]byte("itemSet"), owner}, [][]byte{{1}, item})
+	require.Nil(t, err)
+	topicList := oj.OJsonList{Items: topicTrees}
+	require.Equal(t, `["str:itemSet", {"caller": "address:owner"}]`, SingleLineJSON(&topicList))
+	dataList := oj.OJsonList{Items: dataTrees}
+	require.Equal(t, `[{"status": {"Active": "1"}}, {"item": [{"a": "u32:5"}, {"b": "nested:str:x"}, {"tags": ["u32:0"]}]}]`,
+		SingleLineJSON(&dataList))
+
+	// all data nested in a single item
+	_, dataTrees, err = decoder.DecodeEventTrees([][]byte{[]byte("itemSet"), owner}, [][]byte{append([]byte{1}, item...)})
+	require.Nil(t, err)
+	dataList = oj.OJsonList{Items: dataTrees}
+	require.Equal(t, `[[{"status": {"Active": "u8:1"}}, {"item": [{"a": "u32:5"}, {"b": "nested:str:x"}, {"tags": ["u32:0"]}]}]]`,
+		SingleLineJSON(&dataList))
+
+	_, _, err = decoder.DecodeEventTrees([][]byte{[]byte("itemSet")}, nil)
+	require.EqualError(t, err, "event itemSet has 1 indexed inputs, the log has 0 topics after the identifier")
+
+	_, _, err = decoder.DecodeEventTrees([][]byte{[]byte("other")}, nil)
+	require.EqualError(t, err, "no event other in ABI")
+}
@@ -0,0 +1,291 @@
+package scenabi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
+)
+
+var errNotEnoughBytes = errors.New("not enough bytes")
+
+// Decoder deserializes values according to a contract ABI,
+// into the typed JSON values that the Encoder accepts, to display them.
+type Decoder struct {
+	ABI *scenmodel.ABI
+
+	// FormatAddress shows addresses as scenario expressions, e.g. "sc:adder". Addresses are shown as hex if nil.
+	FormatAddress func(address []byte) string
+}
+
+// DecodeTopLevel deserializes a whole argument or result.
+func (dec *Decoder) DecodeTopLevel(typeName string, data []byte) (oj.OJsonObject, error) {
+	t, err := parseTypeName(typeName)
+	if err != nil {
+		return nil, err
+	}
+	value, err := dec.decodeTopLevel(t, data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode 0x%s as %s: %w", hex.EncodeToString(data), typeName, err)
+	}
+	return value, nil
+}
+
+func (dec *Decoder) decodeTopLevel(t *abiType, data []byte) (oj.OJsonObject, error) {
+	switch {
+	case isNumberType(t):
+		if width, isFixedWidth := fixedWidthNumbers[t.name]; isFixedWidth && len(data) > width {
+			return nil, fmt.Errorf("too many bytes for %s", t)
+		}
+		return decodeNumber(t, data), nil
+	case t.name == "bool":
+		if len(data) > 1 || (len(data) == 1 && data[0] != 1) {
+			return nil, errors.New("invalid bool")
+		}
+		result := oj.OJsonBool(len(data) == 1)
+		return &result, nil
+	case bytesTypes[t.name]:
+		return dec.bytesValue(t, data), nil
+	case t.name == "Option" && len(t.args) == 1:
+		if len(data) == 0 {
+			return &oj.OJsonNull{}, nil
+		}
+		return dec.decodeAll(t, data)
+	case isListType(t):
+		items := oj.OJsonList{}
+		for len(data) > 0 {
+			item, rest, err := dec.decodeNested(t.args[0], data)
+			if err != nil {
+				return nil, err
+			}
+			items.Items = append(items.Items, item)
+			data = rest
+		}
+		return &items, nil
+	}
+
+	if _, isCustom := dec.ABI.Types[t.name]; isCustom && len(t.args) == 0 {
+		description, err := t.customType(dec.ABI)
+		if err != nil {
+			return nil, err
+		}
+		if isSimpleEnum(description) {
+			return variantByDiscriminant(t, description, big.NewInt(0).SetBytes(data))
+		}
+	}
+
+	return dec.decodeAll(t, data)
+}
+
+// decodeAll decodes a nested value that needs to take up all the data.
+func (dec *Decoder) decodeAll(t *abiType, data []byte) (oj.OJsonObject, error) {
+	value, rest, err := dec.decodeNested(t, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%d unexpected bytes at the end", len(rest))
+	}
+	return value, nil
+}
+
+func (dec *Decoder) decodeNested(t *abiType, data []byte) (oj.OJsonObject, []byte, error) {
+	if width, isFixedWidth := fixedWidthNumbers[t.name]; isFixedWidth {
+		if len(data) < width {
+			return nil, nil, errNotEnoughBytes
+		}
+		return decodeNumber(t, data[:width]), data[width:], nil
+	}
+	if length, isFixedLength := fixedLengthTypes[t.name]; isFixedLength {
+		if len(data) < length {
+			return nil, nil, errNotEnoughBytes
+		}
+		return dec.bytesValue(t, data[:length]), data[length:], nil
+	}
+
+	switch {
+	case t.name == "BigUint" || t.name == "BigInt" || bytesTypes[t.name]:
+		content, rest, err := lengthPrefixedContent(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := dec.decodeTopLevel(t, content)
+		return value, rest, err
+	case t.name == "bool":
+		if len(data) == 0 {
+			return nil, nil, errNotEnoughBytes
+		}
+		if data[0] > 1 {
+			return nil, nil, errors.New("invalid bool")
+		}
+		result := oj.OJsonBool(data[0] == 1)
+		return &result, data[1:], nil
+	case t.name == "Option" && len(t.args) == 1:
+		if len(data) == 0 {
+			return nil, nil, errNotEnoughBytes
+		}
+		switch data[0] {
+		case 0:
+			return &oj.OJsonNull{}, data[1:], nil
+		case 1:
+			return dec.decodeNested(t.args[0], data[1:])
+		default:
+			return nil, nil, errors.New("invalid Option")
+		}
+	case isListType(t):
+		if len(data) < 4 {
+			return nil, nil, errNotEnoughBytes
+		}
+		count := int(binary.BigEndian.Uint32(data[:4]))
+		if count > len(data)-4 {
+			// every item takes up at least one byte
+			return nil, nil, errNotEnoughBytes
+		}
+		return dec.decodeItems(repeatType(t.args[0], count), data[4:])
+	case t.isTuple():
+		return dec.decodeItems(t.args, data)
+	}
+	if length, isArray := t.arrayLength(); isArray {
+		return dec.decodeItems(repeatType(t.args[0], length), data)
+	}
+
+	description, err := t.customType(dec.ABI)
+	if err != nil {
+		return nil, nil, err
+	}
+	if description.Type == scenmodel.ABIStructType {
+		return dec.decodeFields(description.Fields, data)
+	}
+	if len(data) == 0 {
+		return nil, nil, errNotEnoughBytes
+	}
+	variantName, err := variantByDiscriminant(t, description, big.NewInt(int64(data[0])))
+	if err != nil {
+		return nil, nil, err
+	}
+	variant, _, _ := findVariant(t, description, variantName)
+	if len(variant.Fields) == 0 {
+		return variantName, data[1:], nil
+	}
+	fields, rest, err := dec.decodeFields(variant.Fields, data[1:])
+	if err != nil {
+		return nil, nil, err
+	}
+	result := oj.NewMap()
+	result.Put(variant.Name, fields)
+	return result, rest, nil
+}
+
+func (dec *Decoder) decodeItems(itemTypes []*abiType, data []byte) (oj.OJsonObject, []byte, error) {
+	items := oj.OJsonList{}
+	for _, itemType := range itemTypes {
+		item, rest, err := dec.decodeNested(itemType, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		items.Items = append(items.Items, item)
+		data = rest
+	}
+	return &items, data, nil
+}
+
+func (dec *Decoder) decodeFields(fields []*scenmodel.ABIField, data []byte) (oj.OJsonObject, []byte, error) {
+	result := oj.NewMap()
+	for _, field := range fields {
+		fieldType, err := parseTypeName(field.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, rest, err := dec.decodeNested(fieldType, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		result.Put(field.Name, value)
+		data = rest
+	}
+	return result, data, nil
+}
+
+func (dec *Decoder) bytesValue(t *abiType, data []byte) oj.OJsonObject {
+	if addressTypes[t.name] && dec.FormatAddress != nil {
+		address := dec.FormatAddress(data)
+		if !strings.Contains(address, " ") {
+			return &oj.OJsonString{Value: address}
+		}
+	}
+	if len(data) == 0 {
+		return &oj.OJsonString{Value: ""}
+	}
+	for _, b := range data {
+		if b < 32 || b > 126 || b == '"' || b == '\\' || b == '|' {
+			return &oj.OJsonString{Value: "0x" + hex.EncodeToString(data)}
+		}
+	}
+	return &oj.OJsonString{Value: "str:" + string(data)}
+}
+
+func decodeNumber(t *abiType, data []byte) oj.OJsonObject {
+	n := big.NewInt(0).SetBytes(data)
+	if signedNumbers[t.name] {
+		n = twos.FromBytes(data)
+	}
+	return &oj.OJsonNumber{Value: n.String()}
+}
+
+func variantByDiscriminant(
+	t *abiType,
+	description *scenmodel.ABITypeDescription,
+	discriminant *big.Int,
+) (*oj.OJsonString, error) {
+	for _, variant := range description.Variants {
+		if discriminant.IsInt64() && int64(variant.Discriminant) == discriminant.Int64() {
+			return &oj.OJsonString{Value: variant.Name}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown discriminant %d for %s", discriminant, t)
+}
+
+func lengthPrefixedContent(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, errNotEnoughBytes
+	}
+	length := int(binary.BigEndian.Uint32(data[:4]))
+	if len(data)-4 < length {
+		return nil, nil, errNotEnoughBytes
+	}
+	return data[4 : 4+length], data[4+length:], nil
+}
+
+func repeatType(t *abiType, count int) []*abiType {
+	types := make([]*abiType, count)
+	for i := range types {
+		types[i] = t
+	}
+	return types
+}
+
+// SingleLineJSON formats typed values compactly, for error messages.
+func SingleLineJSON(value oj.OJsonObject) string {
+	switch v := value.(type) {
+	case *oj.OJsonList:
+		items := make([]string, len(v.AsList()))
+		for i, item := range v.AsList() {
+			items[i] = SingleLineJSON(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *oj.OJsonMap:
+		entries := make([]string, len(v.OrderedKV))
+		for i, kvp := range v.OrderedKV {
+			entries[i] = fmt.Sprintf("\"%s\": %s", kvp.Key, SingleLineJSON(kvp.Value))
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	default:
+		return oj.JSONString(value)
+	}
+}
@@ -0,0 +1,379 @@
+package scenabi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
+)
+
+// Encoder serializes typed JSON values, according to a contract ABI.
+// The values follow the shape of their types:
+// - numbers are JSON numbers, or value expressions, e.g. "rewa:1.5"; negative expressions must start with "-"
+// - bools are JSON bools
+// - addresses, buffers, token identifiers, are value expressions, e.g. "str:abc", "sc:adder"
+// - Option::None is null, Option::Some is the contained value
+// - lists, arrays and tuples are JSON lists; lists of u8 can also be value expressions
+// - structs are maps from field names to field values
+// - enum variants without fields are their names, otherwise maps with the variant name as single key,
+// and a map of field values
+type Encoder struct {
+	ABI         *scenmodel.ABI
+	Interpreter *ei.ExprInterpreter
+}
+
+// EncodeTopLevel serializes a value as a whole argument or result.
+func (enc *Encoder) EncodeTopLevel(typeName string, value oj.OJsonObject) ([]byte, error) {
+	t, err := parseTypeName(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return enc.encodeTopLevel(t, value)
+}
+
+func (enc *Encoder) encodeTopLevel(t *abiType, value oj.OJsonObject) ([]byte, error) {
+	switch {
+	case isNumberType(t):
+		n, err := enc.interpretNumber(t, value)
+		if err != nil {
+			return nil, err
+		}
+		if signedNumbers[t.name] {
+			return twos.ToBytes(n), nil
+		}
+		return n.Bytes(), nil
+	case t.name == "bool":
+		b, err := interpretBool(value)
+		if err != nil || !b {
+			return []byte{}, err
+		}
+		return []byte{1}, nil
+	case bytesTypes[t.name]:
+		return enc.interpretBytes(t, value)
+	case t.name == "Option" && len(t.args) == 1:
+		if _, isNull := value.(*oj.OJsonNull); isNull {
+			return []byte{}, nil
+		}
+		nested, err := enc.encodeNested(t.args[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{1}, nested...), nil
+	case isListType(t):
+		if _, isStr := value.(*oj.OJsonString); isStr && isBytesList(t) {
+			return enc.interpretBytes(t, value)
+		}
+		items, err := listItems(t, value)
+		if err != nil {
+			return nil, err
+		}
+		return enc.encodeItems(t.args[0], items)
+	}
+
+	if _, isCustom := enc.ABI.Types[t.name]; isCustom && len(t.args) == 0 {
+		description, err := t.customType(enc.ABI)
+		if err != nil {
+			return nil, err
+		}
+		if isSimpleEnum(description) {
+			variant, _, err := findVariant(t, description, value)
+			if err != nil {
+				return nil, err
+			}
+			return big.NewInt(int64(variant.Discriminant)).Bytes(), nil
+		}
+	}
+
+	return enc.encodeNested(t, value)
+}
+
+func (enc *Encoder) encodeNested(t *abiType, value oj.OJsonObject) ([]byte, error) {
+	if width, isFixedWidth := fixedWidthNumbers[t.name]; isFixedWidth {
+		n, err := enc.interpretNumber(t, value)
+		if err != nil {
+			return nil, err
+		}
+		if signedNumbers[t.name] {
+			return twos.ToBytesOfLength(n, width)
+		}
+		return n.FillBytes(make([]byte, width)), nil
+	}
+	if _, isFixedLength := fixedLengthTypes[t.name]; isFixedLength {
+		return enc.interpretBytes(t, value)
+	} else if length, isArray := t.arrayLength(); isArray {
+		if _, isStr := value.(*oj.OJsonString); isStr && isBytesList(t) {
+			return enc.interpretBytes(t, value)
+		}
+		items, err := listItems(t, value)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) != length {
+			return nil, fmt.Errorf("expected %d items for %s, got %d", length, t, len(items))
+		}
+		return enc.encodeItems(t.args[0], items)
+	}
+
+	switch {
+	case t.name == "BigUint" || t.name == "BigInt" || bytesTypes[t.name]:
+		encoded, err := enc.encodeTopLevel(t, value)
+		if err != nil {
+			return nil, err
+		}
+		return lengthPrefixed(encoded), nil
+	case t.name == "bool":
+		b, err := interpretBool(value)
+		if err != nil || !b {
+			return []byte{0}, err
+		}
+		return []byte{1}, nil
+	case t.name == "Option" && len(t.args) == 1:
+		if _, isNull := value.(*oj.OJsonNull); isNull {
+			return []byte{0}, nil
+		}
+		nested, err := enc.encodeNested(t.args[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{1}, nested...), nil
+	case isListType(t):
+		if _, isStr := value.(*oj.OJsonString); isStr && isBytesList(t) {
+			encoded, err := enc.interpretBytes(t, value)
+			if err != nil {
+				return nil, err
+			}
+			return lengthPrefixed(encoded), nil
+		}
+		items, err := listItems(t, value)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := enc.encodeItems(t.args[0], items)
+		if err != nil {
+			return nil, err
+		}
+		return append(u32Bytes(len(items)), encoded...), nil
+	case t.isTuple():
+		items, err := listItems(t, value)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) != len(t.args) {
+			return nil, fmt.Errorf("expected %d items for %s, got %d", len(t.args), t, len(items))
+		}
+		var encoded []byte
+		for i, item := range items {
+			itemEncoded, err := enc.encodeNested(t.args[i], item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			encoded = append(encoded, itemEncoded...)
+		}
+		return encoded, nil
+	case t.name == optionalType || t.name == variadicType:
+		return nil, fmt.Errorf("%s is only allowed as an endpoint argument or result", t)
+	}
+
+	description, err := t.customType(enc.ABI)
+	if err != nil {
+		return nil, err
+	}
+	if description.Type == scenmodel.ABIStructType {
+		return enc.encodeFields(t, description.Fields, value)
+	}
+	variant, fieldValues, err := findVariant(t, description, value)
+	if err != nil {
+		return nil, err
+	}
+	encodedFields, err := enc.encodeFields(t, variant.Fields, fieldValues)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(variant.Discriminant)}, encodedFields...), nil
+}
+
+func (enc *Encoder) encodeItems(itemType *abiType, items []oj.OJsonObject) ([]byte, error) {
+	encoded := make([]byte, 0)
+	for i, item := range items {
+		itemEncoded, err := enc.encodeNested(itemType, item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		encoded = append(encoded, itemEncoded...)
+	}
+	return encoded, nil
+}
+
+// encodeFields concatenates the nested encodings of struct or enum variant fields, given as a map.
+// A nil value stands for no fields.
+func (enc *Encoder) encodeFields(t *abiType, fields []*scenmodel.ABIField, value oj.OJsonObject) ([]byte, error) {
+	fieldMap := oj.NewMap()
+	if value != nil {
+		var isMap bool
+		fieldMap, isMap = value.(*oj.OJsonMap)
+		if !isMap {
+			return nil, fmt.Errorf("expected a map of field values for %s", t)
+		}
+	}
+
+	encoded := make([]byte, 0)
+	fieldNames := make(map[string]bool)
+	for _, field := range fields {
+		fieldNames[field.Name] = true
+		fieldValue := mapValue(fieldMap, field.Name)
+		if fieldValue == nil {
+			return nil, fmt.Errorf("missing field %s in %s", field.Name, t)
+		}
+		fieldType, err := parseTypeName(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		fieldEncoded, err := enc.encodeNested(fieldType, fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		encoded = append(encoded, fieldEncoded...)
+	}
+	for _, kvp := range fieldMap.OrderedKV {
+		if !fieldNames[kvp.Key] {
+			return nil, fmt.Errorf("unknown field %s in %s", kvp.Key, t)
+		}
+	}
+	return encoded, nil
+}
+
+// findVariant yields the enum variant a value refers to, either by its name,
+// or as a map with the variant name as single key, and the field values.
+func findVariant(
+	t *abiType,
+	description *scenmodel.ABITypeDescription,
+	value oj.OJsonObject,
+) (*scenmodel.ABIEnumVariant, oj.OJsonObject, error) {
+	var name string
+	var fieldValues oj.OJsonObject
+	switch v := value.(type) {
+	case *oj.OJsonString:
+		name = v.Value
+	case *oj.OJsonMap:
+		if v.Size() != 1 {
+			return nil, nil, fmt.Errorf("expected a single variant for %s", t)
+		}
+		name = v.OrderedKV[0].Key
+		fieldValues = v.OrderedKV[0].Value
+	default:
+		return nil, nil, fmt.Errorf("expected a variant name for %s", t)
+	}
+
+	for _, variant := range description.Variants {
+		if variant.Name == name {
+			return variant, fieldValues, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("unknown variant %s of %s", name, t)
+}
+
+func (enc *Encoder) interpretNumber(t *abiType, value oj.OJsonObject) (*big.Int, error) {
+	var n *big.Int
+	switch v := value.(type) {
+	case *oj.OJsonNumber:
+		var isInteger bool
+		n, isInteger = big.NewInt(0).SetString(v.Value, 10)
+		if !isInteger {
+			return nil, fmt.Errorf("%s is not an integer", v.Value)
+		}
+	case *oj.OJsonString:
+		encoded, err := enc.Interpreter.InterpretString(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		if signedNumbers[t.name] && strings.HasPrefix(strings.TrimSpace(v.Value), "-") {
+			n = twos.FromBytes(encoded)
+		} else {
+			n = big.NewInt(0).SetBytes(encoded)
+		}
+	default:
+		return nil, fmt.Errorf("expected a number for %s", t)
+	}
+
+	if !signedNumbers[t.name] && n.Sign() < 0 {
+		return nil, fmt.Errorf("negative value %d for %s", n, t)
+	}
+	if width, isFixedWidth := fixedWidthNumbers[t.name]; isFixedWidth {
+		var fits bool
+		if signedNumbers[t.name] {
+			_, err := twos.ToBytesOfLength(n, width)
+			fits = err == nil
+		} else {
+			fits = n.BitLen() <= 8*width
+		}
+		if !fits {
+			return nil, fmt.Errorf("value %d does not fit in %s", n, t)
+		}
+	}
+	return n, nil
+}
+
+func interpretBool(value oj.OJsonObject) (bool, error) {
+	b, isBool := value.(*oj.OJsonBool)
+	if !isBool {
+		return false, errors.New("expected a bool")
+	}
+	return bool(*b), nil
+}
+
+func (enc *Encoder) interpretBytes(t *abiType, value oj.OJsonObject) ([]byte, error) {
+	str, isStr := value.(*oj.OJsonString)
+	if !isStr {
+		return nil, fmt.Errorf("expected a value expression for %s", t)
+	}
+	encoded, err := enc.Interpreter.InterpretString(str.Value)
+	if err != nil {
+		return nil, err
+	}
+	if enc.Interpreter.PendingVariableReferenced {
+		// the real value is only known at execution, it gets encoded then
+		return encoded, nil
+	}
+
+	expectedLength, isFixedLength := fixedLengthTypes[t.name]
+	if arrayLength, isArray := t.arrayLength(); isArray {
+		expectedLength, isFixedLength = arrayLength, true
+	}
+	if isFixedLength && len(encoded) != expectedLength {
+		return nil, fmt.Errorf("expected %d bytes for %s, got %d", expectedLength, t, len(encoded))
+	}
+	return encoded, nil
+}
+
+func listItems(t *abiType, value oj.OJsonObject) ([]oj.OJsonObject, error) {
+	list, isList := value.(*oj.OJsonList)
+	if !isList {
+		return nil, fmt.Errorf("expected a list for %s", t)
+	}
+	return list.AsList(), nil
+}
+
+func mapValue(m *oj.OJsonMap, key string) oj.OJsonObject {
+	for _, kvp := range m.OrderedKV {
+		if kvp.Key == key {
+			return kvp.Value
+		}
+	}
+	return nil
+}
+
+func u32Bytes(n int) []byte {
+	encoded := make([]byte, 4)
+	binary.BigEndian.PutUint32(encoded, uint32(n))
+	return encoded
+}
+
+func lengthPrefixed(value []byte) []byte {
+	return append(u32Bytes(len(value)), value...)
+}
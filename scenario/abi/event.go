@@ -0,0 +1,91 @@
+package scenabi
+
+import (
+	"fmt"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// FindEvent yields the ABI event with the given identifier, nil if there is none.
+func FindEvent(abi *scenmodel.ABI, identifier []byte) *scenmodel.ABIEvent {
+	for _, event := range abi.Events {
+		if event.Identifier == string(identifier) {
+			return event
+		}
+	}
+	return nil
+}
+
+// DecodeEventTrees shows the topics and data of a log as value trees, named after the inputs of the ABI event.
+// The first topic is the event identifier, the other topics are the indexed inputs.
+// The data holds the other inputs, either one per data item, or all nested in a single data item.
+func (dec *Decoder) DecodeEventTrees(topics [][]byte, data [][]byte) ([]oj.OJsonObject, []oj.OJsonObject, error) {
+	if len(topics) == 0 {
+		return nil, nil, fmt.Errorf("log has no topics")
+	}
+	event := FindEvent(dec.ABI, topics[0])
+	if event == nil {
+		return nil, nil, fmt.Errorf("no event %s in ABI", topics[0])
+	}
+
+	var indexed, unindexed []*scenmodel.ABIEventInput
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		} else {
+			unindexed = append(unindexed, input)
+		}
+	}
+	if len(topics)-1 != len(indexed) {
+		return nil, nil, fmt.Errorf("event %s has %d indexed inputs, the log has %d topics after the identifier",
+			event.Identifier, len(indexed), len(topics)-1)
+	}
+
+	topicTrees := []oj.OJsonObject{expression("str:" + event.Identifier)}
+	for i, input := range indexed {
+		topicTree, err := dec.DecodeTopLevelTree(input.Type, topics[i+1])
+		if err != nil {
+			return nil, nil, err
+		}
+		topicTrees = append(topicTrees, namedTree(input.Name, topicTree))
+	}
+
+	var dataTrees []oj.OJsonObject
+	switch {
+	case len(data) == len(unindexed):
+		for i, input := range unindexed {
+			dataTree, err := dec.DecodeTopLevelTree(input.Type, data[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			dataTrees = append(dataTrees, namedTree(input.Name, dataTree))
+		}
+	case len(data) == 1:
+		fields := make([]*scenmodel.ABIField, len(unindexed))
+		for i, input := range unindexed {
+			fields[i] = &scenmodel.ABIField{Name: input.Name, Type: input.Type}
+		}
+		dataTree, err := dec.decodeFieldTrees(fields, data[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		dataTrees = append(dataTrees, dataTree)
+	default:
+		return nil, nil, fmt.Errorf("event %s has %d data inputs, the log has %d data items",
+			event.Identifier, len(unindexed), len(data))
+	}
+	return topicTrees, dataTrees, nil
+}
+
+// decodeFieldTrees decodes fields nested one after the other, as a value tree.
+func (dec *Decoder) decodeFieldTrees(fields []*scenmodel.ABIField, data []byte) (oj.OJsonObject, error) {
+	values, rest, err := dec.decodeFields(fields, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%d unexpected bytes at the end", len(rest))
+	}
+	return dec.fieldTrees(&abiType{name: "event data"}, oj.OJsonList{}, fields, values)
+}
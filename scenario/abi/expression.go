@@ -0,0 +1,199 @@
+package scenabi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
+)
+
+// DecodeTopLevelTree deserializes a whole value, into a scenario value tree that evaluates back to it,
+// e.g. [{"a": "u32:5"}, {"b": "nested:str:x"}] for a struct.
+// The keys of single entry maps name the struct fields and enum variants, they are ignored when evaluating the tree.
+// Unlike typed values, trees can be used where the scenario expects raw values, such as storage or logs.
+func (dec *Decoder) DecodeTopLevelTree(typeName string, data []byte) (oj.OJsonObject, error) {
+	t, err := parseTypeName(typeName)
+	if err != nil {
+		return nil, err
+	}
+	value, err := dec.decodeTopLevel(t, data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode 0x%s as %s: %w", hex.EncodeToString(data), typeName, err)
+	}
+	return dec.valueTree(t, value, false)
+}
+
+// valueTree converts a decoded typed value into a value tree, with the nested encoding, or the top-level one.
+func (dec *Decoder) valueTree(t *abiType, value oj.OJsonObject, nested bool) (oj.OJsonObject, error) {
+	if t.name == "Option" && len(t.args) == 1 {
+		if _, isNull := value.(*oj.OJsonNull); isNull {
+			if nested {
+				return expression("u8:0"), nil
+			}
+			return expression(""), nil
+		}
+		inner, err := dec.valueTree(t.args[0], value, true)
+		if err != nil {
+			return nil, err
+		}
+		return &oj.OJsonList{Items: []oj.OJsonObject{expression("u8:1"), inner}}, nil
+	}
+	if number, isNumber := value.(*oj.OJsonNumber); isNumber {
+		return numberTree(t, number.Value, nested)
+	}
+	if _, isFixedLength := fixedLengthTypes[t.name]; isFixedLength {
+		return value, nil
+	}
+
+	switch {
+	case t.name == "bool":
+		b, err := interpretBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if nested {
+			return expression(fmt.Sprintf("u8:%d", boolByte(b))), nil
+		}
+		return expression(fmt.Sprintf("%v", b)), nil
+	case bytesTypes[t.name]:
+		if nested {
+			return expression("nested:" + value.(*oj.OJsonString).Value), nil
+		}
+		return value, nil
+	case isListType(t):
+		items, err := listItems(t, value)
+		if err != nil {
+			return nil, err
+		}
+		tree := oj.OJsonList{}
+		if nested {
+			tree.Items = append(tree.Items, expression(fmt.Sprintf("u32:%d", len(items))))
+		}
+		return dec.itemTrees(tree, repeatType(t.args[0], len(items)), items)
+	case t.isTuple():
+		items, err := listItems(t, value)
+		if err != nil {
+			return nil, err
+		}
+		return dec.itemTrees(oj.OJsonList{}, t.args, items)
+	}
+	if length, isArray := t.arrayLength(); isArray {
+		items, err := listItems(t, value)
+		if err != nil {
+			return nil, err
+		}
+		return dec.itemTrees(oj.OJsonList{}, repeatType(t.args[0], length), items)
+	}
+
+	description, err := t.customType(dec.ABI)
+	if err != nil {
+		return nil, err
+	}
+	if description.Type == scenmodel.ABIStructType {
+		return dec.fieldTrees(t, oj.OJsonList{}, description.Fields, value)
+	}
+
+	variant, fieldValues, err := findVariant(t, description, value)
+	if err != nil {
+		return nil, err
+	}
+	discriminant := fmt.Sprintf("u8:%d", variant.Discriminant)
+	if !nested && isSimpleEnum(description) {
+		discriminant = fmt.Sprintf("%d", variant.Discriminant)
+	}
+	variantTree := namedTree(variant.Name, expression(discriminant))
+	if len(variant.Fields) == 0 {
+		return variantTree, nil
+	}
+	return dec.fieldTrees(t, oj.OJsonList{Items: []oj.OJsonObject{variantTree}}, variant.Fields, fieldValues)
+}
+
+func (dec *Decoder) itemTrees(tree oj.OJsonList, itemTypes []*abiType, items []oj.OJsonObject) (oj.OJsonObject, error) {
+	if len(itemTypes) != len(items) {
+		return nil, fmt.Errorf("expected %d items, got %d", len(itemTypes), len(items))
+	}
+	for i, item := range items {
+		itemTree, err := dec.valueTree(itemTypes[i], item, true)
+		if err != nil {
+			return nil, err
+		}
+		tree.Items = append(tree.Items, itemTree)
+	}
+	return &tree, nil
+}
+
+// fieldTrees appends the fields of a decoded struct or enum variant, as single entry maps named after the fields.
+func (dec *Decoder) fieldTrees(
+	t *abiType,
+	tree oj.OJsonList,
+	fields []*scenmodel.ABIField,
+	value oj.OJsonObject,
+) (oj.OJsonObject, error) {
+	fieldMap, isMap := value.(*oj.OJsonMap)
+	if !isMap {
+		return nil, fmt.Errorf("expected a map of field values for %s", t)
+	}
+	for _, field := range fields {
+		fieldType, err := parseTypeName(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		fieldValue := mapValue(fieldMap, field.Name)
+		if fieldValue == nil {
+			return nil, fmt.Errorf("missing field %s in %s", field.Name, t)
+		}
+		fieldTree, err := dec.valueTree(fieldType, fieldValue, true)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		tree.Items = append(tree.Items, namedTree(field.Name, fieldTree))
+	}
+	return &tree, nil
+}
+
+func numberTree(t *abiType, number string, nested bool) (oj.OJsonObject, error) {
+	n, isInteger := big.NewInt(0).SetString(number, 10)
+	if !isInteger {
+		return nil, fmt.Errorf("%s is not an integer", number)
+	}
+	if width, isFixedWidth := fixedWidthNumbers[t.name]; isFixedWidth && nested {
+		prefix := "u"
+		if signedNumbers[t.name] {
+			prefix = "i"
+		}
+		return expression(fmt.Sprintf("%s%d:%s", prefix, width*8, number)), nil
+	}
+
+	topLevel := number
+	if signedNumbers[t.name] && n.Sign() > 0 && n.Bytes()[0] >= 0x80 {
+		// the decimal would be taken as unsigned, without the leading zero byte of the signed encoding
+		topLevel = "0x" + hex.EncodeToString(twos.ToBytes(n))
+	}
+	if !nested {
+		return expression(topLevel), nil
+	}
+	if t.name == "BigUint" {
+		return expression("biguint:" + number), nil
+	}
+	return expression("nested:" + topLevel), nil
+}
+
+func namedTree(name string, tree oj.OJsonObject) oj.OJsonObject {
+	named := oj.NewMap()
+	named.Put(name, tree)
+	return named
+}
+
+func expression(value string) oj.OJsonObject {
+	return &oj.OJsonString{Value: value}
+}
+
+func boolByte(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
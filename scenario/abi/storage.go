@@ -0,0 +1,58 @@
+package scenabi
+
+import (
+	"bytes"
+
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// Storage mappers that the storage annotations can name.
+// Any other annotation is taken as the type of a single value.
+const (
+	singleValueMapper = "SingleValueMapper"
+	vecMapper         = "VecMapper"
+)
+
+// Suffixes of the keys where a VecMapper stores its length and items.
+const (
+	vecMapperLenSuffix  = ".len"
+	vecMapperItemSuffix = ".item"
+)
+
+// StorageValueType yields the type of the value stored under a key, according to the storage annotations of the ABI.
+// The annotations map storage key names to the mappers that use them:
+// - "SingleValueMapper<T>", or just "T": the value under the name, optionally followed by the mapper keys, is a T
+// - "VecMapper<T>": the length under "NAME.len" is a u32, the items under "NAME.item" and the u32 index are Ts
+// Also yields the part of the key that the annotation matched, the rest of the key is made of mapper keys.
+func StorageValueType(abi *scenmodel.ABI, key []byte) (string, []byte, bool) {
+	var valueType string
+	var matched []byte
+	longestName := -1
+	for name, annotation := range abi.Storage {
+		if len(name) <= longestName || !bytes.HasPrefix(key, []byte(name)) {
+			continue
+		}
+		mapper, err := parseTypeName(annotation)
+		if err != nil {
+			continue
+		}
+
+		rest := key[len(name):]
+		switch {
+		case mapper.name == vecMapper && len(mapper.args) == 1:
+			if bytes.Equal(rest, []byte(vecMapperLenSuffix)) {
+				valueType, matched = "u32", key
+			} else if bytes.HasPrefix(rest, []byte(vecMapperItemSuffix)) && len(rest) == len(vecMapperItemSuffix)+4 {
+				valueType, matched = mapper.args[0].String(), key[:len(name)+len(vecMapperItemSuffix)]
+			} else {
+				continue
+			}
+		case mapper.name == singleValueMapper && len(mapper.args) == 1:
+			valueType, matched = mapper.args[0].String(), key[:len(name)]
+		default:
+			valueType, matched = mapper.String(), key[:len(name)]
+		}
+		longestName = len(name)
+	}
+	return valueType, matched, len(valueType) > 0
+}
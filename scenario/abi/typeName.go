@@ -0,0 +1,89 @@
+package scenabi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// abiType is a parsed ABI type name, e.g. "Option<List<u32>>" or "tuple<u8,BigUint>".
+type abiType struct {
+	name string
+	args []*abiType
+}
+
+func (t *abiType) String() string {
+	if len(t.args) == 0 {
+		return t.name
+	}
+	argNames := make([]string, len(t.args))
+	for i, arg := range t.args {
+		argNames[i] = arg.String()
+	}
+	return t.name + "<" + strings.Join(argNames, ",") + ">"
+}
+
+// parseTypeName parses the type names found in ABI files.
+func parseTypeName(typeName string) (*abiType, error) {
+	t, rest, err := parseTypeNamePrefix(strings.TrimSpace(typeName))
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("invalid ABI type: %s", typeName)
+	}
+	return t, nil
+}
+
+func parseTypeNamePrefix(typeName string) (*abiType, string, error) {
+	nameEnd := strings.IndexAny(typeName, "<,>")
+	if nameEnd < 0 {
+		nameEnd = len(typeName)
+	}
+	t := &abiType{name: strings.TrimSpace(typeName[:nameEnd])}
+	if len(t.name) == 0 {
+		return nil, "", fmt.Errorf("invalid ABI type: %s", typeName)
+	}
+	rest := typeName[nameEnd:]
+	if !strings.HasPrefix(rest, "<") {
+		return t, rest, nil
+	}
+
+	rest = rest[1:]
+	for {
+		arg, argRest, err := parseTypeNamePrefix(rest)
+		if err != nil {
+			return nil, "", err
+		}
+		t.args = append(t.args, arg)
+		switch {
+		case strings.HasPrefix(argRest, ","):
+			rest = argRest[1:]
+		case strings.HasPrefix(argRest, ">"):
+			return t, argRest[1:], nil
+		default:
+			return nil, "", fmt.Errorf("invalid ABI type: %s", typeName)
+		}
+	}
+}
+
+// arrayLength yields N, for fixed size array types, "arrayN<T>".
+func (t *abiType) arrayLength() (int, bool) {
+	if !strings.HasPrefix(t.name, "array") || len(t.args) != 1 {
+		return 0, false
+	}
+	length, err := strconv.Atoi(t.name[len("array"):])
+	if err != nil {
+		return 0, false
+	}
+	return length, true
+}
+
+// isTuple covers both "tuple<A,B>" and the older "tuple2<A,B>".
+func (t *abiType) isTuple() bool {
+	if !strings.HasPrefix(t.name, "tuple") {
+		return false
+	}
+	_, err := strconv.Atoi(t.name[len("tuple"):])
+	return t.name == "tuple" || err == nil
+}
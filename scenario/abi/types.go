@@ -0,0 +1,178 @@
+package scenabi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// fixedWidthNumbers are the fixed size integer types, with their nested encoding size in bytes.
+var fixedWidthNumbers = map[string]int{
+	"u8":    1,
+	"u16":   2,
+	"u32":   4,
+	"u64":   8,
+	"usize": 4,
+	"i8":    1,
+	"i16":   2,
+	"i32":   4,
+	"i64":   8,
+	"isize": 4,
+}
+
+var signedNumbers = map[string]bool{
+	"i8":     true,
+	"i16":    true,
+	"i32":    true,
+	"i64":    true,
+	"isize":  true,
+	"BigInt": true,
+}
+
+// bytesTypes are encoded as their raw bytes top-level, and length-prefixed when nested.
+var bytesTypes = map[string]bool{
+	"bytes":                     true,
+	"BoxedBytes":                true,
+	"ManagedBuffer":             true,
+	"String":                    true,
+	"&str":                      true,
+	"utf-8 string":              true,
+	"TokenIdentifier":           true,
+	"DcdtTokenIdentifier":       true,
+	"RewaOrDcdtTokenIdentifier": true,
+}
+
+// fixedLengthTypes are encoded as exactly so many bytes, both top-level and nested.
+var fixedLengthTypes = map[string]int{
+	"Address":        32,
+	"ManagedAddress": 32,
+	"H256":           32,
+	"CodeMetadata":   2,
+}
+
+var addressTypes = map[string]bool{
+	"Address":        true,
+	"ManagedAddress": true,
+}
+
+var listTypes = map[string]bool{
+	"List":       true,
+	"Vec":        true,
+	"ManagedVec": true,
+	"VecDeque":   true,
+}
+
+// multiTypes only make sense as endpoint arguments or results, they take up several of them.
+const (
+	optionalType = "optional"
+	variadicType = "variadic"
+)
+
+func isNumberType(t *abiType) bool {
+	_, isFixedWidth := fixedWidthNumbers[t.name]
+	return isFixedWidth || t.name == "BigUint" || t.name == "BigInt"
+}
+
+func isListType(t *abiType) bool {
+	return listTypes[t.name] && len(t.args) == 1
+}
+
+func isBytesList(t *abiType) bool {
+	_, isArray := t.arrayLength()
+	return (isListType(t) || isArray) && t.args[0].name == "u8"
+}
+
+// ParseABI loads the contents of a .abi.json file.
+func ParseABI(contents []byte) (*scenmodel.ABI, error) {
+	abi := &scenmodel.ABI{}
+	err := json.Unmarshal(contents, abi)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	return abi, nil
+}
+
+// ParamTypes yields the type of each of a number of top-level values, as arguments or results of an endpoint.
+// Optional parameters can be left out at the end, and a final variadic parameter takes any number of values.
+func ParamTypes(params []*scenmodel.ABIParam, valueCount int) ([]string, error) {
+	return paramTypes(params, valueCount, false)
+}
+
+// LeadingParamTypes yields the type of each of the first values, as arguments or results of an endpoint,
+// when further values may follow them.
+func LeadingParamTypes(params []*scenmodel.ABIParam, valueCount int) ([]string, error) {
+	return paramTypes(params, valueCount, true)
+}
+
+func paramTypes(params []*scenmodel.ABIParam, valueCount int, leading bool) ([]string, error) {
+	var types []string
+	for i, param := range params {
+		t, err := parseTypeName(param.Type)
+		if err != nil {
+			return nil, err
+		}
+		remaining := valueCount - len(types)
+		switch t.name {
+		case variadicType, optionalType:
+			if len(t.args) != 1 {
+				return nil, fmt.Errorf("invalid ABI type: %s", param.Type)
+			}
+			if t.name == variadicType && i != len(params)-1 {
+				return nil, fmt.Errorf("variadic parameter %s must be the last one", param.Name)
+			}
+			count := remaining
+			if t.name == optionalType && count > 1 {
+				count = 1
+			}
+			for j := 0; j < count; j++ {
+				types = append(types, t.args[0].String())
+			}
+		default:
+			if remaining == 0 && leading {
+				return types, nil
+			}
+			if remaining == 0 {
+				return nil, fmt.Errorf("missing value for %s", paramDescription(param))
+			}
+			types = append(types, param.Type)
+		}
+	}
+	if len(types) < valueCount {
+		return nil, fmt.Errorf("too many values, expected %d", len(types))
+	}
+	return types, nil
+}
+
+func paramDescription(param *scenmodel.ABIParam) string {
+	if len(param.Name) == 0 {
+		return param.Type
+	}
+	return fmt.Sprintf("%s (%s)", param.Name, param.Type)
+}
+
+func (t *abiType) customType(abi *scenmodel.ABI) (*scenmodel.ABITypeDescription, error) {
+	if len(t.args) > 0 || abi == nil {
+		return nil, fmt.Errorf("unsupported ABI type: %s", t)
+	}
+	description, found := abi.Types[t.name]
+	if !found {
+		return nil, fmt.Errorf("unknown ABI type: %s", t)
+	}
+	if description.Type != scenmodel.ABIStructType && description.Type != scenmodel.ABIEnumType {
+		return nil, fmt.Errorf("unsupported ABI type: %s (%s)", t, description.Type)
+	}
+	return description, nil
+}
+
+func isSimpleEnum(description *scenmodel.ABITypeDescription) bool {
+	if description.Type != scenmodel.ABIEnumType {
+		return false
+	}
+	for _, variant := range description.Variants {
+		if len(variant.Fields) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package scenexec
+
+import (
+	"bytes"
+	"errors"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenabi "github.com/kalyan3104/k-chain-scenario-go/scenario/abi"
+	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// abiResults decodes the results of a transaction into typed values, according to its contract ABI.
+func (ae *ScenarioExecutor) abiResults(tx *scenmodel.Transaction, results [][]byte) ([]string, []oj.OJsonObject, error) {
+	endpoint := tx.ABI.EndpointFor(tx.Type, tx.Function)
+	if endpoint == nil {
+		return nil, nil, errors.New("endpoint not found in ABI")
+	}
+	types, err := scenabi.ParamTypes(endpoint.Outputs, len(results))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reconstructor := ae.exprReconstructor
+	reconstructor.ABI = tx.ABI
+	decoder := reconstructor.ABIDecoder()
+	values := make([]oj.OJsonObject, len(results))
+	for i, result := range results {
+		values[i], err = decoder.DecodeTopLevel(types[i], result)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return types, values, nil
+}
+
+// resultsPretty formats the results of a transaction for error messages,
+// as typed values if the transaction has a contract ABI.
+func (ae *ScenarioExecutor) resultsPretty(tx *scenmodel.Transaction, results [][]byte) string {
+	if tx.ABI != nil {
+		_, values, err := ae.abiResults(tx, results)
+		if err == nil {
+			valueList := oj.OJsonList{Items: values}
+			return scenabi.SingleLineJSON(&valueList)
+		}
+	}
+	return ae.exprReconstructor.ReconstructList(results, er.NoHint)
+}
+
+// expectedResultsPretty formats the expected results of a transaction for error messages.
+func expectedResultsPretty(tx *scenmodel.Transaction, expected scenmodel.JSONCheckValueList) string {
+	if tx.ABI == nil {
+		return checkBytesListPretty(expected)
+	}
+	originals := make([]oj.OJsonObject, len(expected.Values))
+	for i, jcb := range expected.Values {
+		originals[i] = jcb.Original
+	}
+	if expected.MoreAllowed {
+		originals = append(originals, &oj.OJsonString{Value: scenmodel.JSONCheckValueListMore})
+	}
+	originalList := oj.OJsonList{Items: originals}
+	return scenabi.SingleLineJSON(&originalList)
+}
+
+// recordABICheckValueList records the results of a transaction as typed values, according to its contract ABI.
+// Values that do not encode back to the same bytes are recorded as raw values.
+func (ae *ScenarioExecutor) recordABICheckValueList(
+	tx *scenmodel.Transaction,
+	expected scenmodel.JSONCheckValueList,
+	actual [][]byte,
+) scenmodel.JSONCheckValueList {
+	if !expected.IsUnspecified() && !expected.IsStar && expected.CheckList(actual) {
+		return expected
+	}
+	types, values, err := ae.abiResults(tx, actual)
+	if err != nil {
+		return ae.recordCheckValueList(expected, actual)
+	}
+
+	encoder := &scenabi.Encoder{
+		ABI:         tx.ABI,
+		Interpreter: &ei.ExprInterpreter{FileResolver: ae.fileResolver, VMType: ae.GetVMType()},
+	}
+	recorded := scenmodel.JSONCheckValueList{
+		Values: make([]scenmodel.JSONCheckBytes, len(actual)),
+	}
+	for i, value := range actual {
+		if !expected.IsStar && i < len(expected.Values) && expected.Values[i].Check(value) {
+			recorded.Values[i] = expected.Values[i]
+			continue
+		}
+		encoded, err := encoder.EncodeTopLevel(types[i], values[i])
+		if err != nil || !bytes.Equal(encoded, value) {
+			recorded.Values[i] = ae.recordCheckBytes(value, er.NoHint)
+			continue
+		}
+		recorded.Values[i] = scenmodel.JSONCheckBytes{
+			Value:    value,
+			Original: values[i],
+		}
+	}
+	return recorded
+}
@@ -0,0 +1,387 @@
+package scenexec
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/TwiN/go-color"
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenabi "github.com/kalyan3104/k-chain-scenario-go/scenario/abi"
+	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
+	"github.com/kalyan3104/k-chain-scenario-go/worldmock/dcdtconvert"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+// AccountDiffStyle selects whether failed account checks also display a diff of the accounts.
+type AccountDiffStyle int
+
+const (
+	// NoAccountDiff only reports the mismatch messages.
+	NoAccountDiff AccountDiffStyle = iota
+
+	// PlainAccountDiff appends a unified diff of each mismatching account to the mismatch messages.
+	PlainAccountDiff
+
+	// ColorAccountDiff is a PlainAccountDiff with removed lines in red and added lines in green.
+	ColorAccountDiff
+)
+
+const (
+	diffContext = ' '
+	diffRemoved = '-'
+	diffAdded   = '+'
+)
+
+// accountDiffWriter builds a diff of the JSON representation of an account, line by line.
+type accountDiffWriter struct {
+	lines    []string
+	colorize bool
+}
+
+func (w *accountDiffWriter) line(marker byte, indent int, text string) {
+	for _, textLine := range strings.Split(text, "\n") {
+		diffLine := string(marker) + " " + strings.Repeat("    ", indent) + textLine
+		if w.colorize {
+			switch marker {
+			case diffRemoved:
+				diffLine = color.Ize(color.Red, diffLine)
+			case diffAdded:
+				diffLine = color.Ize(color.Green, diffLine)
+			}
+		}
+		w.lines = append(w.lines, diffLine)
+	}
+}
+
+// field writes a key and its value, as expected and as found.
+// Empty values are not displayed, they stand for fields that are missing on one side.
+func (w *accountDiffWriter) field(indent int, key string, expected string, actual string, matches bool) {
+	if matches {
+		if len(expected) > 0 {
+			w.line(diffContext, indent, jsonString(key)+": "+expected)
+		}
+		return
+	}
+	if len(expected) > 0 {
+		w.line(diffRemoved, indent, jsonString(key)+": "+expected)
+	}
+	if len(actual) > 0 {
+		w.line(diffAdded, indent, jsonString(key)+": "+actual)
+	}
+}
+
+// mismatchedAccount pairs an account that failed a check with the account found in the world.
+// One of them is nil if the account was either unexpected or missing.
+type mismatchedAccount struct {
+	expected *scenmodel.CheckAccount
+	actual   *worldmock.Account
+}
+
+// withAccountDiffs appends the diffs of the mismatching accounts to a check error, if configured so.
+// When stopping at the first mismatch, only the diff of the reported account is displayed.
+func (ae *ScenarioExecutor) withAccountDiffs(checkErr error, accounts []mismatchedAccount) error {
+	if checkErr == nil || ae.accountDiffStyle == NoAccountDiff || len(accounts) == 0 {
+		return checkErr
+	}
+	if !ae.collectAllMismatches {
+		accounts = accounts[:1]
+	}
+
+	diffs := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		diff, err := ae.renderAccountDiff(account.expected, account.actual)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return fmt.Errorf("%w\n%s", checkErr, strings.Join(diffs, "\n"))
+}
+
+// renderAccountDiff displays an expected account next to the one found in the world, as a unified diff.
+// The expected account is nil for unexpected accounts, the world account is nil for missing ones.
+func (ae *ScenarioExecutor) renderAccountDiff(expectedAcct *scenmodel.CheckAccount, account *worldmock.Account) (string, error) {
+	w := &accountDiffWriter{colorize: ae.accountDiffStyle == ColorAccountDiff}
+
+	blockMarker := byte(diffContext)
+	var addressExpr string
+	switch {
+	case expectedAcct == nil:
+		blockMarker = diffAdded
+		addressExpr = ae.exprReconstructor.Reconstruct(account.Address, er.AddressHint)
+	case account == nil:
+		blockMarker = diffRemoved
+		addressExpr = expectedAcct.Address.Original
+	default:
+		addressExpr = expectedAcct.Address.Original
+	}
+	bothExist := expectedAcct != nil && account != nil
+
+	w.line(blockMarker, 0, jsonString(addressExpr)+": {")
+
+	var expected, actual string
+	if expectedAcct != nil {
+		expected = checkUint64Expr(expectedAcct.Nonce)
+	}
+	if account != nil {
+		actual = jsonString(ae.exprReconstructor.ReconstructFromUint64(account.Nonce))
+	}
+	w.field(1, "nonce", expected, actual, bothExist && expectedAcct.Nonce.Check(account.Nonce))
+
+	expected, actual = "", ""
+	if expectedAcct != nil {
+		expected = checkBigIntExpr(expectedAcct.Balance)
+	}
+	if account != nil {
+		actual = jsonString(ae.exprReconstructor.ReconstructFromBigInt(account.Balance))
+	}
+	w.field(1, "balance", expected, actual, bothExist && expectedAcct.Balance.Check(account.Balance))
+
+	expected, actual = "", ""
+	if expectedAcct != nil {
+		expected = checkBytesExpr(expectedAcct.Username)
+	}
+	if account != nil && len(account.Username) > 0 {
+		actual = jsonString(ae.recordExpression(account.Username, er.StrHint))
+	}
+	w.field(1, "username", expected, actual, bothExist && expectedAcct.Username.Check(account.Username))
+
+	err := ae.writeStorageDiff(w, blockMarker, expectedAcct, account)
+	if err != nil {
+		return "", err
+	}
+
+	err = ae.writeDCDTDiff(w, blockMarker, expectedAcct, account)
+	if err != nil {
+		return "", err
+	}
+
+	expected, actual = "", ""
+	if expectedAcct != nil {
+		expected = checkBytesExpr(expectedAcct.Code)
+	}
+	if account != nil && len(account.Code) > 0 {
+		actual = jsonString(ae.exprReconstructor.Reconstruct(account.Code, er.CodeHint))
+	}
+	w.field(1, "code", expected, actual, bothExist && expectedAcct.Code.Check(account.Code))
+
+	expected, actual = "", ""
+	if expectedAcct != nil {
+		expected = checkBytesExpr(expectedAcct.CodeMetadata)
+	}
+	if account != nil && len(account.CodeMetadata) > 0 {
+		actual = jsonString(ae.exprReconstructor.Reconstruct(account.CodeMetadata, er.HexHint))
+	}
+	w.field(1, "codeMetadata", expected, actual, bothExist &&
+		(expectedAcct.CodeMetadata.IsUnspecified() || expectedAcct.CodeMetadata.Check(account.CodeMetadata)))
+
+	expected, actual = "", ""
+	if expectedAcct != nil {
+		expected = checkBytesExpr(expectedAcct.Owner)
+	}
+	if account != nil && len(account.OwnerAddress) > 0 {
+		actual = jsonString(ae.recordExpression(account.OwnerAddress, er.AddressHint))
+	}
+	w.field(1, "owner", expected, actual, bothExist &&
+		(expectedAcct.Owner.IsUnspecified() || expectedAcct.Owner.Check(account.OwnerAddress)))
+
+	w.line(blockMarker, 0, "}")
+
+	return strings.Join(w.lines, "\n"), nil
+}
+
+func (ae *ScenarioExecutor) writeStorageDiff(
+	w *accountDiffWriter,
+	blockMarker byte,
+	expectedAcct *scenmodel.CheckAccount,
+	account *worldmock.Account,
+) error {
+	if expectedAcct != nil && expectedAcct.IgnoreStorage {
+		if expectedAcct.ExplicitStorage {
+			w.line(blockMarker, 1, `"storage": "*"`)
+		}
+		return nil
+	}
+
+	expectedStorage := make(map[string]*scenmodel.CheckStorageKeyValuePair)
+	allKeys := make(map[string]bool)
+	if expectedAcct != nil {
+		for _, stkvp := range expectedAcct.CheckStorage {
+			expectedStorage[string(stkvp.Key.Value)] = stkvp
+			allKeys[string(stkvp.Key.Value)] = true
+		}
+	}
+	if account != nil {
+		for key, value := range account.Storage {
+			if len(value) > 0 {
+				allKeys[key] = true
+			}
+		}
+	}
+
+	var sortedKeys []string
+	for key := range allKeys {
+		// reserved keys are not checked
+		if !strings.HasPrefix(key, core.ProtectedKeyPrefix) {
+			sortedKeys = append(sortedKeys, key)
+		}
+	}
+	sort.Strings(sortedKeys)
+
+	var address []byte
+	if account != nil {
+		address = account.Address
+	} else {
+		address = expectedAcct.Address.Value
+	}
+
+	w.line(blockMarker, 1, `"storage": {`)
+	for _, key := range sortedKeys {
+		keyExpr, found := ae.abiStorageKey(address, []byte(key))
+		if !found {
+			keyExpr = ae.recordExpression([]byte(key), er.StrHint)
+		}
+		want := scenmodel.JSONCheckBytesUnspecified()
+		if expectedAcct != nil && expectedAcct.MoreStorageAllowed {
+			want = scenmodel.JSONCheckBytesStar()
+		}
+		var expected, actual string
+		if stkvp, specified := expectedStorage[key]; specified {
+			keyExpr = stkvp.Key.Original
+			want = stkvp.CheckValue
+			expected = checkBytesExpr(want)
+		}
+		var have []byte
+		if account != nil {
+			have = account.StorageValue(key)
+		}
+		if len(have) > 0 {
+			actual = jsonString(ae.recordExpression(have, er.NoHint))
+			if haveTree := ae.storageValueTree(address, []byte(key), have); haveTree != nil {
+				actual = scenabi.SingleLineJSON(haveTree)
+			}
+		}
+		w.field(2, keyExpr, expected, actual, expectedAcct != nil && account != nil && want.Check(have))
+	}
+	w.line(blockMarker, 1, "}")
+
+	return nil
+}
+
+// writeDCDTDiff only compares token balances, the other token differences are listed in the mismatch messages.
+func (ae *ScenarioExecutor) writeDCDTDiff(
+	w *accountDiffWriter,
+	blockMarker byte,
+	expectedAcct *scenmodel.CheckAccount,
+	account *worldmock.Account,
+) error {
+	if expectedAcct != nil && expectedAcct.IgnoreDCDT {
+		w.line(blockMarker, 1, `"dcdt": "*"`)
+		return nil
+	}
+
+	expectedBalances := make(map[string]scenmodel.JSONCheckBigInt)
+	actualBalances := make(map[string]*big.Int)
+	tokenExprs := make(map[string]string)
+	if expectedAcct != nil {
+		for _, expectedToken := range expectedAcct.CheckDCDTData {
+			for _, expectedInstance := range expectedToken.Instances {
+				key := dcdtDiffKey(expectedToken.TokenIdentifier.Original, expectedInstance.Nonce.Value)
+				expectedBalances[key] = expectedInstance.Balance
+				tokenExprs[key] = key
+			}
+		}
+	}
+	if account != nil {
+		systemAccStorage := make(map[string][]byte)
+		systemAcc, exists := ae.World.AcctMap[string(vmcommon.SystemAccountAddress)]
+		if exists {
+			systemAccStorage = systemAcc.Storage
+		}
+		accountTokens, err := dcdtconvert.GetFullMockDCDTData(account.Storage, systemAccStorage)
+		if err != nil {
+			return err
+		}
+		for _, accountToken := range accountTokens {
+			tokenExpr := ae.recordExpression(accountToken.TokenIdentifier, er.StrHint)
+			for _, accountInstance := range accountToken.Instances {
+				key := dcdtDiffKey(tokenExpr, accountInstance.TokenMetaData.Nonce)
+				actualBalances[key] = accountInstance.Value
+				tokenExprs[key] = key
+			}
+		}
+	}
+	if len(tokenExprs) == 0 {
+		return nil
+	}
+
+	var sortedKeys []string
+	for key := range tokenExprs {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	w.line(blockMarker, 1, `"dcdt": {`)
+	for _, key := range sortedKeys {
+		want, specified := expectedBalances[key]
+		have, held := actualBalances[key]
+		var expected, actual string
+		if specified {
+			expected = checkBigIntExpr(want)
+		}
+		if !held {
+			have = big.NewInt(0)
+		} else {
+			actual = jsonString(ae.exprReconstructor.ReconstructFromBigInt(have))
+		}
+		matches := expectedAcct != nil && account != nil
+		if specified {
+			matches = matches && want.Check(have)
+		} else {
+			matches = matches && have.Sign() == 0
+		}
+		w.field(2, key, expected, actual, matches)
+	}
+	w.line(blockMarker, 1, "}")
+
+	return nil
+}
+
+func dcdtDiffKey(tokenExpr string, nonce uint64) string {
+	if nonce == 0 {
+		return tokenExpr
+	}
+	return fmt.Sprintf("%s (nonce %d)", tokenExpr, nonce)
+}
+
+func jsonString(value string) string {
+	return oj.JSONString(&oj.OJsonString{Value: value})
+}
+
+func checkUint64Expr(check scenmodel.JSONCheckUint64) string {
+	if check.IsUnspecified() {
+		return ""
+	}
+	return jsonString(check.Original)
+}
+
+func checkBigIntExpr(check scenmodel.JSONCheckBigInt) string {
+	if check.IsUnspecified() {
+		return ""
+	}
+	return jsonString(check.Original)
+}
+
+func checkBytesExpr(check scenmodel.JSONCheckBytes) string {
+	if check.IsUnspecified() || check.Original == nil {
+		return ""
+	}
+	return oj.JSONString(check.Original)
+}
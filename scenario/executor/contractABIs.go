@@ -0,0 +1,96 @@
+package scenexec
+
+import (
+	"bytes"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+// registerContractABI remembers the ABI of the contract that a transaction deployed or called,
+// so that its storage and logs can be shown decoded.
+func (ae *ScenarioExecutor) registerContractABI(tx *scenmodel.Transaction, output *vmcommon.VMOutput) {
+	if tx.ABI == nil || output.ReturnCode != vmcommon.Ok {
+		return
+	}
+	if ae.contractABIs == nil {
+		ae.contractABIs = make(map[string]*scenmodel.ABI)
+	}
+
+	address := tx.To.Value
+	if tx.Type == scenmodel.ScDeploy {
+		address = ae.World.LastCreatedContractAddress
+	}
+	ae.contractABIs[string(address)] = tx.ABI
+}
+
+// reconstructorFor yields a reconstructor that knows the ABI of the contract at the address, if a transaction gave it.
+func (ae *ScenarioExecutor) reconstructorFor(address []byte) *er.ExprReconstructor {
+	reconstructor := ae.exprReconstructor
+	reconstructor.ABI = ae.contractABIs[string(address)]
+	return &reconstructor
+}
+
+// abiStorageKey names a storage key as the ABI storage annotations of the account do.
+// Yields false if the key is not annotated.
+func (ae *ScenarioExecutor) abiStorageKey(address []byte, key []byte) (string, bool) {
+	keyExpr, found := ae.reconstructorFor(address).ReconstructStorageKey(key)
+	if !found || !ae.interpretsTo(&oj.OJsonString{Value: keyExpr}, key) {
+		return "", false
+	}
+	return keyExpr, true
+}
+
+// storageValueTree shows a storage value decoded according to the ABI storage annotations of the account.
+// Yields nil if the value is not annotated, or if it cannot be shown in a way that evaluates back to it.
+func (ae *ScenarioExecutor) storageValueTree(address []byte, key []byte, value []byte) oj.OJsonObject {
+	tree := ae.reconstructorFor(address).ReconstructStorageValue(key, value)
+	if tree == nil || !ae.interpretsTo(tree, value) {
+		return nil
+	}
+	return tree
+}
+
+// logTrees shows the topics and data of a log decoded according to the ABI event of the contract that logged it.
+// Yields false if the log does not match an event, or if it cannot be shown in a way that evaluates back to it.
+func (ae *ScenarioExecutor) logTrees(outputLog *vmcommon.LogEntry) ([]oj.OJsonObject, []oj.OJsonObject, bool) {
+	topicTrees, dataTrees, found := ae.reconstructorFor(outputLog.Address).ReconstructEvent(outputLog.Topics, outputLog.Data)
+	if !found || len(dataTrees) != len(outputLog.Data) {
+		return nil, nil, false
+	}
+	for i, topicTree := range topicTrees {
+		if !ae.interpretsTo(topicTree, outputLog.Topics[i]) {
+			return nil, nil, false
+		}
+	}
+	for i, dataTree := range dataTrees {
+		if !ae.interpretsTo(dataTree, outputLog.Data[i]) {
+			return nil, nil, false
+		}
+	}
+	return topicTrees, dataTrees, true
+}
+
+// interpretsTo checks that a value tree evaluates back to the value it was reconstructed from.
+func (ae *ScenarioExecutor) interpretsTo(tree oj.OJsonObject, value []byte) bool {
+	interpreter := ei.ExprInterpreter{VMType: ae.GetVMType()}
+	interpreted, err := interpreter.InterpretSubTree(tree)
+	return err == nil && bytes.Equal(interpreted, value)
+}
+
+// checkValueListFromTrees pairs values with the value trees reconstructed from them.
+func checkValueListFromTrees(values [][]byte, trees []oj.OJsonObject) scenmodel.JSONCheckValueList {
+	checkList := scenmodel.JSONCheckValueList{
+		Values: make([]scenmodel.JSONCheckBytes, len(values)),
+	}
+	for i, value := range values {
+		checkList.Values[i] = scenmodel.JSONCheckBytes{
+			Value:    value,
+			Original: trees[i],
+		}
+	}
+	return checkList
+}
@@ -0,0 +1,60 @@
+package scenexec
+
+import (
+	"strings"
+
+	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+var codeFilePrefixes = []string{"file:", "drtsc:"}
+
+// registerCodePath remembers the file a contract code was loaded from, to group the coverage by code file.
+func (ae *ScenarioExecutor) registerCodePath(code scenmodel.JSONBytesFromString) {
+	if ae.coverage == nil || len(code.Value) == 0 || ae.fileResolver == nil {
+		return
+	}
+	for _, prefix := range codeFilePrefixes {
+		if strings.HasPrefix(code.Original, prefix) {
+			ae.codePaths[string(code.Value)] = ae.fileResolver.ResolveAbsolutePath(code.Original[len(prefix):])
+			return
+		}
+	}
+}
+
+// recordCoverage counts deploys, upgrades and endpoint calls, if a coverage collector is configured.
+func (ae *ScenarioExecutor) recordCoverage(tx *scenmodel.Transaction, output *vmcommon.VMOutput) {
+	if ae.coverage == nil {
+		return
+	}
+
+	switch tx.Type {
+	case scenmodel.ScDeploy:
+		ae.registerCodePath(tx.Code)
+		if output.ReturnCode != vmcommon.Ok {
+			return
+		}
+		for _, outputAccount := range output.OutputAccounts {
+			if len(outputAccount.Code) > 0 {
+				ae.coverage.RecordDeploy(ae.codePaths[string(tx.Code.Value)], ae.addressExpr(outputAccount.Address))
+			}
+		}
+	case scenmodel.ScUpgrade:
+		ae.registerCodePath(tx.Code)
+		if output.ReturnCode == vmcommon.Ok {
+			ae.coverage.RecordUpgrade(ae.codePaths[string(tx.Code.Value)], ae.addressExpr(tx.To.Value))
+		}
+	case scenmodel.ScCall, scenmodel.ScQuery:
+		var codePath string
+		recipient := ae.World.AcctMap.GetAccount(tx.To.Value)
+		if recipient != nil {
+			codePath = ae.codePaths[string(recipient.Code)]
+		}
+		ae.coverage.RecordCall(codePath, ae.addressExpr(tx.To.Value), tx.Function, uint64(output.ReturnCode))
+	}
+}
+
+func (ae *ScenarioExecutor) addressExpr(address []byte) string {
+	return ae.exprReconstructor.Reconstruct(address, er.AddressHint)
+}
@@ -2,6 +2,7 @@ package scenexec
 
 import (
 	fr "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/fileresolver"
+	scenio "github.com/kalyan3104/k-chain-scenario-go/scenario/io"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 )
 
@@ -9,23 +10,92 @@ import (
 func (ae *ScenarioExecutor) RunScenario(scenario *scenmodel.Scenario, fileResolver fr.FileResolver) error {
 	ae.fileResolver = fileResolver
 	ae.checkGas = scenario.CheckGas
+	// copied, since the variables captured while running are added to it
+	ae.constants = make(map[string][]byte, len(scenario.ConstantValues))
+	for name, value := range scenario.ConstantValues {
+		ae.constants[name] = value
+	}
+	ae.negativeConstants = scenario.NegativeConstants
+	if ae.externalStepsDepth == 0 {
+		ae.scenarioPath = scenario.Path
+		ae.capturedVariables = nil
+		if ae.gasBaseline != nil {
+			ae.gasBaseline.StartScenario(scenario.Path)
+		}
+	}
 	resetGasTracesIfNewTest(ae, scenario)
 
-	err := ae.InitVM(scenario.GasSchedule)
+	err := ae.initScenarioVM(scenario)
 	if err != nil {
 		return err
 	}
+	if ae.externalStepsDepth == 0 {
+		defer ae.restoreGasSchedule()
+	}
+
+	for stepIndex, generalStep := range scenario.Steps {
+		if deferredStep, isDeferred := generalStep.(*scenmodel.DeferredStep); isDeferred {
+			generalStep, err = ae.parseDeferredStep(deferredStep)
+			if err != nil {
+				return newStepError(stepIndex, deferredStep, err)
+			}
+			if ae.recordMode {
+				// the results are recorded in the parsed step, which is written instead
+				scenario.Steps[stepIndex] = generalStep
+			}
+		}
 
-	txIndex := 0
-	for _, generalStep := range scenario.Steps {
 		setGasTraceInMetering(ae, true)
 		err := ae.ExecuteStep(generalStep)
 		if err != nil {
-			return err
+			return newStepError(stepIndex, generalStep, err)
 		}
 		setGasTraceInMetering(ae, false)
-		txIndex++
+	}
+
+	if ae.recordMode && ae.externalStepsDepth == 0 && needsRecordedCheckState(scenario) {
+		checkStateStep, err := ae.newRecordedCheckStateStep()
+		if err != nil {
+			return err
+		}
+		scenario.Steps = append(scenario.Steps, checkStateStep)
 	}
 
 	return nil
 }
+
+// newStepError wraps a step failure with the step index, id and position.
+// External steps are identified by their path.
+func newStepError(stepIndex int, generalStep scenmodel.Step, err error) *scenio.StepError {
+	var stepID string
+	var position scenmodel.SourcePosition
+	switch step := generalStep.(type) {
+	case *scenmodel.ExternalStepsStep:
+		stepID = step.Path
+		position = step.Position
+	case *scenmodel.SetStateStep:
+		stepID = step.SetStateIdent
+		position = step.Position
+	case *scenmodel.CheckStateStep:
+		stepID = step.CheckStateIdent
+		position = step.Position
+	case *scenmodel.DumpStateStep:
+		position = step.Position
+	case *scenmodel.SetGasScheduleStep:
+		position = step.Position
+	case *scenmodel.DeferredStep:
+		position = step.Position
+	case *scenmodel.TxStep:
+		stepID = step.TxIdent
+		position = step.Position
+	}
+
+	return &scenio.StepError{
+		StepIndex: stepIndex,
+		StepID:    stepID,
+		StepType:  generalStep.StepTypeName(),
+		Line:      position.Line,
+		Column:    position.Column,
+		Err:       err,
+	}
+}
@@ -0,0 +1,146 @@
+package scenexec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+)
+
+// initScenarioVM initializes the VM and the builtin function container with the gas schedule of the scenario.
+// If the VM was already initialized by a previous scenario, it is switched to the gas schedule of this one.
+// External steps run with the gas schedule of the scenario that includes them.
+func (ae *ScenarioExecutor) initScenarioVM(scenario *scenmodel.Scenario) error {
+	if ae.vm != nil && ae.externalStepsDepth > 0 {
+		return nil
+	}
+
+	gasSchedule, err := ae.gasScheduleMap(scenario.GasSchedule, scenario.GasSchedulePath, scenario.GasScheduleOverrides)
+	if err != nil {
+		return err
+	}
+
+	if ae.vm == nil {
+		return ae.initVMWithGasSchedule(gasSchedule)
+	}
+	if !reflect.DeepEqual(gasSchedule, ae.vmGasSchedule) {
+		ae.changeGasSchedule(gasSchedule)
+		ae.vmGasSchedule = gasSchedule
+	}
+	return nil
+}
+
+// changeGasSchedule switches the VM and the builtin functions to another gas schedule.
+func (ae *ScenarioExecutor) changeGasSchedule(gasSchedule worldmock.GasScheduleMap) {
+	ae.World.BuiltinFuncs.GasScheduleChange(gasSchedule)
+	ae.vm.GasScheduleChange(gasSchedule)
+}
+
+// restoreGasSchedule switches back to the gas schedule the VM was initialized with,
+// so that the setGasSchedule steps of a scenario do not affect the scenarios run after it.
+func (ae *ScenarioExecutor) restoreGasSchedule() {
+	if !ae.gasScheduleChanged {
+		return
+	}
+	ae.changeGasSchedule(ae.vmGasSchedule)
+	ae.gasScheduleChanged = false
+}
+
+// gasScheduleMap yields the gas schedule selected by a scenario or a step, with the overrides applied.
+// The gas schedule path is only used for GasScheduleFile.
+func (ae *ScenarioExecutor) gasScheduleMap(
+	scenGasSchedule scenmodel.GasSchedule,
+	gasSchedulePath string,
+	overrides []*scenmodel.GasScheduleOverride,
+) (worldmock.GasScheduleMap, error) {
+	var gasSchedule worldmock.GasScheduleMap
+	var err error
+	if scenGasSchedule == scenmodel.GasScheduleFile {
+		gasSchedule, err = loadGasScheduleFile(ae.fileResolver.ResolveAbsolutePath(gasSchedulePath))
+	} else {
+		gasSchedule, err = ae.vmBuilder.GasScheduleMapFromScenarios(scenGasSchedule)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return applyGasScheduleOverrides(gasSchedule, overrides)
+}
+
+// loadGasScheduleFile reads a gas schedule from a TOML file, as used by the nodes, or from a JSON file.
+func loadGasScheduleFile(gasSchedulePath string) (worldmock.GasScheduleMap, error) {
+	switch filepath.Ext(gasSchedulePath) {
+	case ".toml":
+		tomlMap, err := core.LoadTomlFileToMap(gasSchedulePath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load gas schedule file: %w", err)
+		}
+		return convertTomlGasSchedule(tomlMap)
+	case ".json":
+		contents, err := os.ReadFile(gasSchedulePath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load gas schedule file: %w", err)
+		}
+		gasSchedule := make(worldmock.GasScheduleMap)
+		err = json.Unmarshal(contents, &gasSchedule)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing gas schedule file %s: %w", gasSchedulePath, err)
+		}
+		return gasSchedule, nil
+	default:
+		return nil, fmt.Errorf("gas schedule file should be .toml or .json: %s", gasSchedulePath)
+	}
+}
+
+func convertTomlGasSchedule(tomlMap map[string]interface{}) (worldmock.GasScheduleMap, error) {
+	gasSchedule := make(worldmock.GasScheduleMap)
+	for sectionName, section := range tomlMap {
+		entries, isMap := section.(map[string]interface{})
+		if !isMap {
+			return nil, fmt.Errorf("gas schedule section %s is not a table", sectionName)
+		}
+		gasSchedule[sectionName] = make(map[string]uint64)
+		for entryName, entry := range entries {
+			value, isInt := entry.(int64)
+			if !isInt || value < 0 {
+				return nil, fmt.Errorf("invalid gas schedule value for %s.%s: %v", sectionName, entryName, entry)
+			}
+			gasSchedule[sectionName][entryName] = uint64(value)
+		}
+	}
+	return gasSchedule, nil
+}
+
+// applyGasScheduleOverrides yields a copy of the gas schedule, with some of its entries replaced.
+// Only existing entries can be overridden, to catch misspelled names.
+func applyGasScheduleOverrides(
+	gasSchedule worldmock.GasScheduleMap,
+	overrides []*scenmodel.GasScheduleOverride,
+) (worldmock.GasScheduleMap, error) {
+	if len(overrides) == 0 {
+		return gasSchedule, nil
+	}
+
+	result := make(worldmock.GasScheduleMap, len(gasSchedule))
+	for sectionName, section := range gasSchedule {
+		result[sectionName] = make(map[string]uint64, len(section))
+		for entryName, value := range section {
+			result[sectionName][entryName] = value
+		}
+	}
+
+	for _, override := range overrides {
+		_, found := result[override.Section][override.Name]
+		if !found {
+			return nil, fmt.Errorf("cannot override unknown gas schedule entry %s.%s", override.Section, override.Name)
+		}
+		result[override.Section][override.Name] = override.Value.Value
+	}
+	return result, nil
+}
@@ -4,27 +4,54 @@ import (
 	"fmt"
 
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 )
 
-func logGasTrace(ae *ScenarioExecutor) {
+func logGasTrace(ae *ScenarioExecutor, generalStep scenmodel.Step) {
+	if ae.PeekTraceGas() && ae.gasProfile != nil {
+		if txStep, isTx := generalStep.(*scenmodel.TxStep); isTx {
+			ae.gasProfile.RecordTxGas(ae.scenarioPath, txStep.TxIdent, readableGasTrace(ae))
+		}
+		return
+	}
+
 	if ae.PeekTraceGas() {
 		scGasTrace := ae.vm.GetGasTrace()
 		totalGasUsedByAPIs := 0
 		for scAddress, gasTrace := range scGasTrace {
-			fmt.Println("Gas Trace for: ", "SC Address", scAddress)
+			fmt.Fprintln(ae.output, "Gas Trace for: ", "SC Address", scAddress)
 			for functionName, value := range gasTrace {
 				totalGasUsed := uint64(0)
 				for _, usedGas := range value {
 					totalGasUsed += usedGas
 				}
-				fmt.Println("GasTrace: functionName:", functionName, ",  totalGasUsed:", totalGasUsed, ", numberOfCalls:", len(value))
+				fmt.Fprintln(ae.output, "GasTrace: functionName:", functionName, ",  totalGasUsed:", totalGasUsed, ", numberOfCalls:", len(value))
 				totalGasUsedByAPIs += int(totalGasUsed)
 			}
-			fmt.Println("TotalGasUsedByAPIs: ", totalGasUsedByAPIs)
+			fmt.Fprintln(ae.output, "TotalGasUsedByAPIs: ", totalGasUsedByAPIs)
 		}
 	}
 }
 
+// readableGasTrace yields the gas trace of the VM, with the contract addresses as scenario expressions.
+func readableGasTrace(ae *ScenarioExecutor) map[string]map[string][]uint64 {
+	gasTrace := make(map[string]map[string][]uint64)
+	for scAddress, functionTraces := range ae.vm.GetGasTrace() {
+		gasTrace[ae.addressExpr([]byte(scAddress))] = functionTraces
+	}
+	return gasTrace
+}
+
+// checkGasBaseline compares the gas used by a tx step with the baseline, or records it, if a baseline is configured.
+// Only transactions that pay for gas are considered.
+func checkGasBaseline(ae *ScenarioExecutor, step *scenmodel.TxStep, output *vmcommon.VMOutput) error {
+	if ae.gasBaseline == nil || !step.Tx.Type.HasSender() {
+		return nil
+	}
+	gasUsed := step.Tx.GasLimit.Value - output.GasRemaining
+	return ae.gasBaseline.CheckTxGas(ae.scenarioPath, step.TxIdent, gasUsed)
+}
+
 func setGasTraceInMetering(ae *ScenarioExecutor, enable bool) {
 	if enable && ae.PeekTraceGas() {
 		ae.vm.SetGasTracing(true)
@@ -0,0 +1,101 @@
+package scenexec
+
+import (
+	"fmt"
+
+	scenjwrite "github.com/kalyan3104/k-chain-scenario-go/scenario/json/write"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+// checkedLogIndexes yields the indexes of the actual logs that are not ignored by the expected log list.
+func checkedLogIndexes(expectedLogs scenmodel.LogList, actualLogs []*vmcommon.LogEntry) []int {
+	var checkedIndexes []int
+	for i, actualLog := range actualLogs {
+		if !expectedLogs.IsIgnored(actualLog.Identifier) {
+			checkedIndexes = append(checkedIndexes, i)
+		}
+	}
+	return checkedIndexes
+}
+
+// checkTxLogsUnordered looks for a pairing of expected and actual logs, regardless of their order.
+// Only the actual logs at checkedIndexes take part.
+func (ae *ScenarioExecutor) checkTxLogsUnordered(
+	txIndex string,
+	expectedLogs scenmodel.LogList,
+	actualLogs []*vmcommon.LogEntry,
+	checkedIndexes []int,
+) []error {
+	canMatch := make([][]bool, len(expectedLogs.List))
+	for expectedIndex, expectedLog := range expectedLogs.List {
+		canMatch[expectedIndex] = make([]bool, len(checkedIndexes))
+		for checkedIndex, i := range checkedIndexes {
+			canMatch[expectedIndex][checkedIndex] =
+				ae.checkTxLog(txIndex, i, expectedLog, actualLogs[i]) == nil
+		}
+	}
+	actualToExpected := matchLogs(canMatch, len(checkedIndexes))
+
+	isMatched := make([]bool, len(expectedLogs.List))
+	for _, expectedIndex := range actualToExpected {
+		if expectedIndex >= 0 {
+			isMatched[expectedIndex] = true
+		}
+	}
+
+	var mismatches []error
+	for expectedIndex, expectedLog := range expectedLogs.List {
+		if !isMatched[expectedIndex] {
+			mismatches = append(mismatches, fmt.Errorf("no matching log for expected log. Tx '%s'. Expected log index: %d. Want:\n%s",
+				txIndex,
+				expectedIndex,
+				scenjwrite.LogToString(expectedLog),
+			))
+		}
+	}
+	if !expectedLogs.MoreAllowedAtEnd {
+		for checkedIndex, i := range checkedIndexes {
+			if actualToExpected[checkedIndex] < 0 {
+				mismatches = append(mismatches, fmt.Errorf("unmatched log. Tx '%s'. Log index: %d. Log:\n%s",
+					txIndex,
+					i,
+					scenjwrite.LogToString(ae.convertLogToTestFormat(actualLogs[i])),
+				))
+			}
+		}
+	}
+
+	return mismatches
+}
+
+// matchLogs finds a maximum matching between expected and actual logs, using augmenting paths.
+// canMatch[e][a] tells whether expected log e accepts actual log a.
+// It yields, for each actual log, the index of the expected log it was paired with, or -1.
+func matchLogs(canMatch [][]bool, actualCount int) []int {
+	actualToExpected := make([]int, actualCount)
+	for a := range actualToExpected {
+		actualToExpected[a] = -1
+	}
+
+	var tryMatch func(e int, visited []bool) bool
+	tryMatch = func(e int, visited []bool) bool {
+		for a, accepted := range canMatch[e] {
+			if !accepted || visited[a] {
+				continue
+			}
+			visited[a] = true
+			if actualToExpected[a] < 0 || tryMatch(actualToExpected[a], visited) {
+				actualToExpected[a] = e
+				return true
+			}
+		}
+		return false
+	}
+
+	for e := range canMatch {
+		tryMatch(e, make([]bool, actualCount))
+	}
+	return actualToExpected
+}
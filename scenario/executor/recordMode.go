@@ -0,0 +1,511 @@
+package scenexec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"strings"
+
+	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
+	"github.com/kalyan3104/k-chain-scenario-go/worldmock/dcdtconvert"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+const maxBytesRecordedAsNumber = 15
+
+// recordTxResults fills in the expected result of a tx step from the actual output.
+// Expectations that are missing, "*" or that do not hold are replaced,
+// the ones that still hold are kept together with their original expressions.
+func (ae *ScenarioExecutor) recordTxResults(step *scenmodel.TxStep, output *vmcommon.VMOutput) {
+	if !step.Tx.Type.IsSmartContractTx() {
+		return
+	}
+	if step.ExpectedResult == nil {
+		step.ExpectedResult = &scenmodel.TransactionResult{
+			Out:             scenmodel.JSONCheckValueListUnspecified(),
+			Status:          scenmodel.JSONCheckBigIntUnspecified(),
+			Message:         scenmodel.JSONCheckBytesUnspecified(),
+			Gas:             scenmodel.JSONCheckUint64Unspecified(),
+			Refund:          scenmodel.JSONCheckBigIntUnspecified(),
+			Logs:            scenmodel.LogList{IsUnspecified: true},
+			OutputTransfers: scenmodel.OutputTransferList{IsUnspecified: true},
+		}
+	}
+	blResult := step.ExpectedResult
+
+	if step.Tx.ABI != nil {
+		blResult.Out = ae.recordABICheckValueList(step.Tx, blResult.Out, output.ReturnData)
+	} else {
+		blResult.Out = ae.recordCheckValueList(blResult.Out, output.ReturnData)
+	}
+
+	status := big.NewInt(int64(output.ReturnCode))
+	if blResult.Status.IsUnspecified() || blResult.Status.IsStar || !blResult.Status.Check(status) {
+		blResult.Status = ae.recordCheckBigInt(status)
+	}
+
+	message := []byte(output.ReturnMessage)
+	if blResult.Message.IsStar || !blResult.Message.Check(message) ||
+		(blResult.Message.IsUnspecified() && len(message) > 0) {
+		blResult.Message = ae.recordCheckBytes(message, er.StrHint)
+	}
+
+	if ae.checkGas && (blResult.Gas.IsUnspecified() || blResult.Gas.IsStar || !blResult.Gas.Check(output.GasRemaining)) {
+		blResult.Gas = scenmodel.JSONCheckUint64{
+			Value:    output.GasRemaining,
+			Original: ae.exprReconstructor.ReconstructFromUint64(output.GasRemaining),
+		}
+	}
+
+	if !blResult.Refund.Check(output.GasRefund) {
+		blResult.Refund = ae.recordCheckBigInt(output.GasRefund)
+	}
+
+	blResult.Logs = ae.recordTxLogs(step.TxIdent, blResult.Logs, output.Logs)
+	blResult.OutputTransfers = ae.recordOutputTransfers(step.TxIdent, blResult.OutputTransfers, output)
+}
+
+func (ae *ScenarioExecutor) recordTxLogs(
+	txIndex string,
+	expectedLogs scenmodel.LogList,
+	actualLogs []*vmcommon.LogEntry,
+) scenmodel.LogList {
+	if !expectedLogs.IsUnspecified && !expectedLogs.IsStar &&
+		len(ae.checkTxLogs(txIndex, expectedLogs, actualLogs)) == 0 {
+		return expectedLogs
+	}
+
+	recordedLogs := scenmodel.LogList{
+		Unordered:          expectedLogs.Unordered,
+		IgnoredIdentifiers: expectedLogs.IgnoredIdentifiers,
+	}
+	for checkedIndex, i := range checkedLogIndexes(expectedLogs, actualLogs) {
+		actualLog := actualLogs[i]
+		if !expectedLogs.IsStar && !expectedLogs.Unordered && checkedIndex < len(expectedLogs.List) &&
+			ae.checkTxLog(txIndex, i, expectedLogs.List[checkedIndex], actualLog) == nil {
+			recordedLogs.List = append(recordedLogs.List, expectedLogs.List[checkedIndex])
+			continue
+		}
+
+		recordedLog := &scenmodel.LogEntry{
+			Address:  ae.recordCheckBytes(actualLog.Address, er.AddressHint),
+			Endpoint: ae.recordCheckBytes(actualLog.Identifier, er.StrHint),
+			Topics:   ae.recordCheckValueList(scenmodel.JSONCheckValueListUnspecified(), actualLog.Topics),
+			Data:     ae.recordCheckValueList(scenmodel.JSONCheckValueListUnspecified(), actualLog.Data),
+		}
+		if topicTrees, dataTrees, found := ae.logTrees(actualLog); found {
+			recordedLog.Topics = checkValueListFromTrees(actualLog.Topics, topicTrees)
+			recordedLog.Data = checkValueListFromTrees(actualLog.Data, dataTrees)
+		}
+		recordedLogs.List = append(recordedLogs.List, recordedLog)
+	}
+
+	return recordedLogs
+}
+
+// recordOutputTransfers only adds output transfers to results that do not mention them if there are any.
+func (ae *ScenarioExecutor) recordOutputTransfers(
+	txIndex string,
+	expectedTransfers scenmodel.OutputTransferList,
+	output *vmcommon.VMOutput,
+) scenmodel.OutputTransferList {
+	actualTransfers := collectOutputTransfers(output)
+	if expectedTransfers.IsUnspecified && len(actualTransfers) == 0 {
+		return expectedTransfers
+	}
+	if !expectedTransfers.IsUnspecified && !expectedTransfers.IsStar &&
+		len(ae.checkOutputTransfers(txIndex, expectedTransfers, output)) == 0 {
+		return expectedTransfers
+	}
+
+	recordedTransfers := scenmodel.OutputTransferList{}
+	for i, actualTransfer := range actualTransfers {
+		if !expectedTransfers.IsStar && i < len(expectedTransfers.List) &&
+			ae.checkOutputTransfer(txIndex, i, expectedTransfers.List[i], actualTransfer) == nil {
+			recordedTransfers.List = append(recordedTransfers.List, expectedTransfers.List[i])
+			continue
+		}
+		recordedTransfers.List = append(recordedTransfers.List, ae.convertOutputTransferToTestFormat(actualTransfer))
+	}
+	return recordedTransfers
+}
+
+// recordCheckState makes a checkState step describe the current state of the world.
+// If the step allows more accounts than the ones listed, no accounts are added to it.
+func (ae *ScenarioExecutor) recordCheckState(step *scenmodel.CheckStateStep) error {
+	checkAccounts := step.CheckAccounts
+	var recordedAccounts []*scenmodel.CheckAccount
+
+	for _, expectedAcct := range checkAccounts.Accounts {
+		matchingAcct, isMatch := ae.World.AcctMap[string(expectedAcct.Address.Value)]
+		if !isMatch {
+			continue
+		}
+
+		recordedAcct, err := ae.recordCheckAccount(expectedAcct, matchingAcct)
+		if err != nil {
+			return err
+		}
+		recordedAccounts = append(recordedAccounts, recordedAcct)
+	}
+
+	if !checkAccounts.MoreAccountsAllowed {
+		for _, worldAcct := range ae.sortedWorldAccounts() {
+			if scenmodel.FindCheckAccount(checkAccounts.Accounts, worldAcct.Address) != nil {
+				continue
+			}
+
+			recordedAcct, err := ae.recordCheckAccount(newRecordedCheckAccount(worldAcct), worldAcct)
+			if err != nil {
+				return err
+			}
+			recordedAccounts = append(recordedAccounts, recordedAcct)
+		}
+	}
+
+	checkAccounts.Accounts = recordedAccounts
+	return nil
+}
+
+// newRecordedCheckStateStep generates a checkState step that covers all accounts in the world.
+func (ae *ScenarioExecutor) newRecordedCheckStateStep() (*scenmodel.CheckStateStep, error) {
+	step := &scenmodel.CheckStateStep{
+		CheckAccounts: &scenmodel.CheckAccounts{},
+	}
+	err := ae.recordCheckState(step)
+	if err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
+
+// needsRecordedCheckState is true if the scenario changes the state after its last checkState step.
+func needsRecordedCheckState(scenario *scenmodel.Scenario) bool {
+	for i := len(scenario.Steps) - 1; i >= 0; i-- {
+		switch scenario.Steps[i].(type) {
+		case *scenmodel.CheckStateStep:
+			return false
+		case *scenmodel.ExternalStepsStep, *scenmodel.SetStateStep, *scenmodel.TxStep:
+			return true
+		}
+	}
+	return false
+}
+
+func (ae *ScenarioExecutor) sortedWorldAccounts() []*worldmock.Account {
+	var addresses []string
+	for address := range ae.World.AcctMap {
+		if !bytes.Equal([]byte(address), vmcommon.SystemAccountAddress) {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	accounts := make([]*worldmock.Account, len(addresses))
+	for i, address := range addresses {
+		accounts[i] = ae.World.AcctMap[address]
+	}
+	return accounts
+}
+
+func newRecordedCheckAccount(account *worldmock.Account) *scenmodel.CheckAccount {
+	return &scenmodel.CheckAccount{
+		Address:         scenmodel.JSONBytesFromString{Value: account.Address},
+		Nonce:           scenmodel.JSONCheckUint64Unspecified(),
+		Balance:         scenmodel.JSONCheckBigIntUnspecified(),
+		Username:        scenmodel.JSONCheckBytesUnspecified(),
+		IgnoreStorage:   true,
+		Code:            scenmodel.JSONCheckBytesUnspecified(),
+		CodeMetadata:    scenmodel.JSONCheckBytesUnspecified(),
+		Owner:           scenmodel.JSONCheckBytesUnspecified(),
+		AsyncCallData:   scenmodel.JSONCheckBytesUnspecified(),
+		DeveloperReward: scenmodel.JSONCheckBigIntUnspecified(),
+	}
+}
+
+// recordCheckAccount yields a copy of the expected account, with all failing or missing checks
+// replaced by the values found in the world.
+func (ae *ScenarioExecutor) recordCheckAccount(
+	expectedAcct *scenmodel.CheckAccount,
+	account *worldmock.Account,
+) (*scenmodel.CheckAccount, error) {
+	recordedAcct := *expectedAcct
+	if len(recordedAcct.Address.Original) == 0 {
+		recordedAcct.Address.Original = ae.recordExpression(account.Address, er.AddressHint)
+	}
+
+	if recordedAcct.Nonce.IsUnspecified() || !recordedAcct.Nonce.Check(account.Nonce) {
+		recordedAcct.Nonce = scenmodel.JSONCheckUint64{
+			Value:    account.Nonce,
+			Original: ae.exprReconstructor.ReconstructFromUint64(account.Nonce),
+		}
+	}
+
+	if recordedAcct.Balance.IsUnspecified() || !recordedAcct.Balance.Check(account.Balance) {
+		recordedAcct.Balance = ae.recordCheckBigInt(account.Balance)
+	}
+
+	if !recordedAcct.Username.Check(account.Username) {
+		recordedAcct.Username = ae.recordCheckBytes(account.Username, er.StrHint)
+	}
+
+	// the code is normally loaded from a file, which cannot be inferred from the bytes
+	if !recordedAcct.Code.Check(account.Code) {
+		recordedAcct.Code = scenmodel.JSONCheckBytesStar()
+		if len(account.Code) == 0 {
+			recordedAcct.Code = scenmodel.JSONCheckBytesUnspecified()
+		}
+	}
+
+	if !recordedAcct.CodeMetadata.IsUnspecified() && !recordedAcct.CodeMetadata.Check(account.CodeMetadata) {
+		recordedAcct.CodeMetadata = ae.recordCheckBytes(account.CodeMetadata, er.HexHint)
+	}
+
+	if (recordedAcct.Owner.IsUnspecified() && len(account.OwnerAddress) > 0) ||
+		(!recordedAcct.Owner.IsUnspecified() && !recordedAcct.Owner.Check(account.OwnerAddress)) {
+		recordedAcct.Owner = ae.recordCheckBytes(account.OwnerAddress, er.AddressHint)
+	}
+
+	if !recordedAcct.AsyncCallData.IsUnspecified() && !recordedAcct.AsyncCallData.Check([]byte(account.AsyncCallData)) {
+		recordedAcct.AsyncCallData = ae.recordCheckBytes([]byte(account.AsyncCallData), er.StrHint)
+	}
+
+	storageIsStar := expectedAcct.ExplicitStorage && expectedAcct.IgnoreStorage
+	if !storageIsStar && (!expectedAcct.ExplicitStorage || ae.checkAccountStorage(expectedAcct, account) != nil) {
+		recordedAcct.ExplicitStorage = true
+		recordedAcct.IgnoreStorage = false
+		recordedAcct.MoreStorageAllowed = false
+		recordedAcct.CheckStorage = ae.recordStorage(expectedAcct.CheckStorage, account)
+	}
+
+	if !expectedAcct.IgnoreDCDT {
+		dcdtMismatch, err := ae.checkAccountDCDT(expectedAcct, account)
+		if err != nil {
+			return nil, err
+		}
+		if dcdtMismatch != nil {
+			recordedAcct.MoreDCDTTokensAllowed = false
+			recordedAcct.CheckDCDTData, err = ae.recordDCDT(account)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &recordedAcct, nil
+}
+
+func (ae *ScenarioExecutor) recordStorage(
+	expectedStorage []*scenmodel.CheckStorageKeyValuePair,
+	account *worldmock.Account,
+) []*scenmodel.CheckStorageKeyValuePair {
+	var storageKeys []string
+	for storageKey, storageValue := range account.Storage {
+		if len(storageValue) > 0 && !strings.HasPrefix(storageKey, core.ProtectedKeyPrefix) {
+			storageKeys = append(storageKeys, storageKey)
+		}
+	}
+	sort.Strings(storageKeys)
+
+	var recordedStorage []*scenmodel.CheckStorageKeyValuePair
+	for _, storageKey := range storageKeys {
+		storageValue := account.Storage[storageKey]
+		recordedKvp := findCheckStorageKey(expectedStorage, []byte(storageKey))
+		if recordedKvp == nil || !recordedKvp.CheckValue.Check(storageValue) {
+			keyExpr, found := ae.abiStorageKey(account.Address, []byte(storageKey))
+			if !found {
+				keyExpr = ae.recordExpression([]byte(storageKey), er.StrHint)
+			}
+			checkValue := ae.recordCheckBytes(storageValue, er.NoHint)
+			if valueTree := ae.storageValueTree(account.Address, []byte(storageKey), storageValue); valueTree != nil {
+				checkValue = scenmodel.JSONCheckBytes{
+					Value:    storageValue,
+					Original: valueTree,
+				}
+			}
+			recordedKvp = &scenmodel.CheckStorageKeyValuePair{
+				Key: scenmodel.JSONBytesFromString{
+					Value:    []byte(storageKey),
+					Original: keyExpr,
+				},
+				CheckValue: checkValue,
+			}
+		}
+		recordedStorage = append(recordedStorage, recordedKvp)
+	}
+
+	return recordedStorage
+}
+
+func findCheckStorageKey(storage []*scenmodel.CheckStorageKeyValuePair, key []byte) *scenmodel.CheckStorageKeyValuePair {
+	for _, kvp := range storage {
+		if bytes.Equal(kvp.Key.Value, key) {
+			return kvp
+		}
+	}
+	return nil
+}
+
+func (ae *ScenarioExecutor) recordDCDT(account *worldmock.Account) ([]*scenmodel.CheckDCDTData, error) {
+	systemAccStorage := make(map[string][]byte)
+	systemAcc, exists := ae.World.AcctMap[string(vmcommon.SystemAccountAddress)]
+	if exists {
+		systemAccStorage = systemAcc.Storage
+	}
+	tokenData, err := dcdtconvert.GetFullMockDCDTData(account.Storage, systemAccStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokenNames []string
+	for tokenName := range tokenData {
+		tokenNames = append(tokenNames, tokenName)
+	}
+	sort.Strings(tokenNames)
+
+	var recordedTokens []*scenmodel.CheckDCDTData
+	for _, tokenName := range tokenNames {
+		token := tokenData[tokenName]
+		recordedToken := &scenmodel.CheckDCDTData{
+			TokenIdentifier: scenmodel.JSONBytesFromString{
+				Value:    token.TokenIdentifier,
+				Original: ae.recordExpression(token.TokenIdentifier, er.StrHint),
+			},
+			LastNonce: scenmodel.JSONCheckUint64Unspecified(),
+			Frozen:    scenmodel.JSONCheckUint64Unspecified(),
+		}
+		if token.LastNonce > 0 {
+			recordedToken.LastNonce = scenmodel.JSONCheckUint64{
+				Value:    token.LastNonce,
+				Original: ae.exprReconstructor.ReconstructFromUint64(token.LastNonce),
+			}
+		}
+		for _, role := range token.Roles {
+			recordedToken.Roles = append(recordedToken.Roles, string(role))
+		}
+
+		// a single fungible instance is written in the compact form, without a nonce
+		compact := len(token.Instances) == 1 && len(token.Roles) == 0
+		for _, tokenInstance := range token.Instances {
+			metaData := tokenInstance.TokenMetaData
+			recordedInstance := scenmodel.NewCheckDCDTInstance()
+			recordedInstance.Nonce = scenmodel.JSONUint64{Value: metaData.Nonce}
+			if !compact || metaData.Nonce > 0 {
+				recordedInstance.Nonce.Original = ae.exprReconstructor.ReconstructFromUint64(metaData.Nonce)
+			}
+			recordedInstance.Balance = ae.recordCheckBigInt(tokenInstance.Value)
+			if len(metaData.Creator) > 0 {
+				recordedInstance.Creator = ae.recordCheckBytes(metaData.Creator, er.AddressHint)
+			}
+			if metaData.Royalties > 0 {
+				recordedInstance.Royalties = scenmodel.JSONCheckUint64{
+					Value:    uint64(metaData.Royalties),
+					Original: ae.exprReconstructor.ReconstructFromUint64(uint64(metaData.Royalties)),
+				}
+			}
+			if len(metaData.Hash) > 0 {
+				recordedInstance.Hash = ae.recordCheckBytes(metaData.Hash, er.NoHint)
+			}
+			if len(metaData.URIs) > 0 {
+				recordedInstance.Uris = scenmodel.JSONCheckValueList{}
+				for _, uri := range metaData.URIs {
+					recordedInstance.Uris.Values = append(recordedInstance.Uris.Values, ae.recordCheckBytes(uri, er.StrHint))
+				}
+			}
+			if len(metaData.Attributes) > 0 {
+				recordedInstance.Attributes = ae.recordCheckBytes(metaData.Attributes, er.NoHint)
+			}
+			recordedToken.Instances = append(recordedToken.Instances, recordedInstance)
+		}
+
+		recordedTokens = append(recordedTokens, recordedToken)
+	}
+
+	return recordedTokens, nil
+}
+
+// recordCheckValueList keeps the expected list if it holds.
+// Otherwise, it yields a list with the actual values, reusing the expected items that still hold.
+func (ae *ScenarioExecutor) recordCheckValueList(expected scenmodel.JSONCheckValueList, actual [][]byte) scenmodel.JSONCheckValueList {
+	if !expected.IsUnspecified() && !expected.IsStar && expected.CheckList(actual) {
+		return expected
+	}
+
+	recorded := scenmodel.JSONCheckValueList{
+		Values: make([]scenmodel.JSONCheckBytes, len(actual)),
+	}
+	for i, value := range actual {
+		if !expected.IsStar && i < len(expected.Values) && expected.Values[i].Check(value) {
+			recorded.Values[i] = expected.Values[i]
+			continue
+		}
+		recorded.Values[i] = ae.recordCheckBytes(value, er.NoHint)
+	}
+	return recorded
+}
+
+func (ae *ScenarioExecutor) recordCheckBytes(value []byte, hint er.ExprReconstructorHint) scenmodel.JSONCheckBytes {
+	return scenmodel.JSONCheckBytesReconstructed(value, ae.recordExpression(value, hint))
+}
+
+func (ae *ScenarioExecutor) recordCheckBigInt(value *big.Int) scenmodel.JSONCheckBigInt {
+	return scenmodel.JSONCheckBigInt{
+		Value:    big.NewInt(0).Set(value),
+		Original: ae.exprReconstructor.ReconstructFromBigInt(value),
+	}
+}
+
+// recordExpression produces a scenario expression that evaluates to the given value.
+// Unlike the reconstructor output, which is meant for humans, the result can always be parsed back.
+// Readable forms are tried first, hexadecimal is the fallback.
+func (ae *ScenarioExecutor) recordExpression(value []byte, hint er.ExprReconstructorHint) string {
+	if len(value) == 0 {
+		return ""
+	}
+
+	var candidates []string
+	switch hint {
+	case er.NoHint:
+		if len(value) > 1 && isPrintable(value) {
+			candidates = append(candidates, "str:"+string(value))
+		}
+		if len(value) == 32 {
+			candidates = append(candidates, ae.exprReconstructor.Reconstruct(value, er.AddressHint))
+		}
+		if len(value) < maxBytesRecordedAsNumber {
+			candidates = append(candidates, ae.exprReconstructor.Reconstruct(value, er.NumberHint))
+		}
+	case er.StrHint:
+		if isPrintable(value) {
+			candidates = append(candidates, "str:"+string(value))
+		}
+	default:
+		candidates = append(candidates, ae.exprReconstructor.Reconstruct(value, hint))
+	}
+
+	interpreter := ei.ExprInterpreter{VMType: ae.GetVMType()}
+	for _, candidate := range candidates {
+		interpreted, err := interpreter.InterpretString(candidate)
+		if err == nil && bytes.Equal(interpreted, value) {
+			return candidate
+		}
+	}
+
+	return "0x" + hex.EncodeToString(value)
+}
+
+func isPrintable(value []byte) bool {
+	for _, b := range value {
+		if b < 32 || b > 126 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package scenexec
+
+import (
+	"fmt"
+
+	scenjparse "github.com/kalyan3104/k-chain-scenario-go/scenario/json/parse"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+// captureTxOutputs saves the values captured by a tx step into variables, for the steps after it.
+func (ae *ScenarioExecutor) captureTxOutputs(step *scenmodel.TxStep, output *vmcommon.VMOutput) error {
+	if len(step.Captures) == 0 {
+		return nil
+	}
+	if ae.constants == nil {
+		ae.constants = make(map[string][]byte)
+	}
+
+	for _, capture := range step.Captures {
+		switch capture.Source {
+		case scenmodel.CaptureAddress:
+			ae.constants[capture.Variable] = append([]byte{}, ae.World.LastCreatedContractAddress...)
+		case scenmodel.CaptureOut:
+			if capture.OutIndex >= len(output.ReturnData) {
+				return fmt.Errorf("cannot capture %s[%d] into %s, the transaction returned %d values",
+					scenmodel.CaptureOutPrefix, capture.OutIndex, capture.Variable, len(output.ReturnData))
+			}
+			ae.constants[capture.Variable] = append([]byte{}, output.ReturnData[capture.OutIndex]...)
+		}
+		ae.capturedVariables = append(ae.capturedVariables, capture.Variable)
+		log.Trace("captured variable", "name", capture.Variable, "value", ae.constants[capture.Variable])
+	}
+	return nil
+}
+
+// parseDeferredStep parses a step that references captured variables, now that their values are known.
+func (ae *ScenarioExecutor) parseDeferredStep(step *scenmodel.DeferredStep) (scenmodel.Step, error) {
+	parser := scenjparse.NewParser(ae.fileResolver, ae.GetVMType())
+	parser.ExprInterpreter.Constants = ae.constants
+	parser.ExprInterpreter.NegativeConstants = ae.negativeConstants
+	return parser.ParseScenarioStepObject(step.JSON)
+}
@@ -0,0 +1,80 @@
+package scenexec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenjparse "github.com/kalyan3104/k-chain-scenario-go/scenario/json/parse"
+	scenjwrite "github.com/kalyan3104/k-chain-scenario-go/scenario/json/write"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// executeGoldenCheckStateStep compares the world with the accounts stored in the golden file of the step,
+// or regenerates the golden file, if configured so.
+func (ae *ScenarioExecutor) executeGoldenCheckStateStep(step *scenmodel.CheckStateStep) error {
+	goldenPath := ae.fileResolver.ResolveAbsolutePath(step.GoldenPath)
+	if ae.updateGolden || ae.recordMode {
+		return ae.writeGoldenFile(goldenPath)
+	}
+
+	checkAccounts, err := ae.loadGoldenFile(goldenPath)
+	if err != nil {
+		return err
+	}
+
+	baseErrMsg := checkStateBaseErrorMsg(step)
+	return ae.checkAccounts(baseErrMsg, checkAccounts)
+}
+
+// writeGoldenFile saves all accounts in the world, converted the same way as in a state dump.
+// Contract code is not copied, it is written as "*".
+func (ae *ScenarioExecutor) writeGoldenFile(goldenPath string) error {
+	var scenAccounts []*scenmodel.Account
+	for _, account := range ae.sortedWorldAccounts() {
+		scenAccount, err := ae.convertMockAccountToScenarioFormat(account, ae.recordExpression)
+		if err != nil {
+			return err
+		}
+		if len(account.Code) > 0 {
+			scenAccount.Code = scenmodel.JSONBytesFromString{
+				Value:    account.Code,
+				Original: "*",
+			}
+		}
+		scenAccounts = append(scenAccounts, scenAccount)
+	}
+
+	err := os.MkdirAll(filepath.Dir(goldenPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	jsonString := oj.JSONString(scenjwrite.AccountsToOJ(scenAccounts))
+	return os.WriteFile(goldenPath, []byte(jsonString+"\n"), 0644)
+}
+
+// loadGoldenFile reads the account checks from a golden file.
+// Golden files are complete snapshots, so accounts without storage are expected to have none.
+func (ae *ScenarioExecutor) loadGoldenFile(goldenPath string) (*scenmodel.CheckAccounts, error) {
+	contents, err := os.ReadFile(goldenPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read golden file: %w", err)
+	}
+
+	parser := scenjparse.NewParser(ae.fileResolver, ae.GetVMType())
+	checkAccounts, err := parser.ParseCheckAccountsFile(contents)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing golden file %s: %w", goldenPath, err)
+	}
+
+	for _, checkAccount := range checkAccounts.Accounts {
+		if !checkAccount.ExplicitStorage {
+			checkAccount.ExplicitStorage = true
+			checkAccount.IgnoreStorage = false
+		}
+	}
+
+	return checkAccounts, nil
+}
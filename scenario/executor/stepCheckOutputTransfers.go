@@ -0,0 +1,191 @@
+package scenexec
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	scenjwrite "github.com/kalyan3104/k-chain-scenario-go/scenario/json/write"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
+
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-common-go/parsers"
+)
+
+// sentTransfer is a transfer or call sent out by a contract, with the DCDT transfers decoded from its call data.
+type sentTransfer struct {
+	index    uint32
+	from     []byte
+	to       []byte
+	value    *big.Int
+	dcdt     []*vmcommon.DCDTTransfer
+	data     []byte
+	gasLimit uint64
+	callType vm.CallType
+}
+
+// collectOutputTransfers gathers the transfers of all output accounts, in the order they were sent.
+func collectOutputTransfers(output *vmcommon.VMOutput) []*sentTransfer {
+	var transfers []*sentTransfer
+	for _, outAcc := range output.OutputAccounts {
+		for _, outTransfer := range outAcc.OutputTransfers {
+			transfer := &sentTransfer{
+				index:    outTransfer.Index,
+				from:     outTransfer.SenderAddress,
+				to:       outAcc.Address,
+				value:    outTransfer.Value,
+				data:     outTransfer.Data,
+				gasLimit: outTransfer.GasLimit,
+				callType: outTransfer.CallType,
+			}
+			if transfer.value == nil {
+				transfer.value = big.NewInt(0)
+			}
+			transfer.decodeDCDTTransfers()
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	sort.SliceStable(transfers, func(i, j int) bool {
+		if transfers[i].index != transfers[j].index {
+			return transfers[i].index < transfers[j].index
+		}
+		return bytes.Compare(transfers[i].to, transfers[j].to) < 0
+	})
+	return transfers
+}
+
+// decodeDCDTTransfers fills in the tokens sent via the DCDT transfer built-in functions.
+// For NFT and multi-transfers, the actual recipient is also taken from the call data.
+func (transfer *sentTransfer) decodeDCDTTransfers() {
+	function, args, err := parsers.NewCallArgsParser().ParseData(string(transfer.data))
+	if err != nil {
+		return
+	}
+	dcdtParser, err := parsers.NewDCDTTransferParser(worldmock.WorldMarshalizer)
+	if err != nil {
+		return
+	}
+	parsed, err := dcdtParser.ParseDCDTTransfers(transfer.from, transfer.to, function, args)
+	if err != nil {
+		return
+	}
+	transfer.dcdt = parsed.DCDTTransfers
+	transfer.to = parsed.RcvAddr
+}
+
+func (ae *ScenarioExecutor) checkOutputTransfers(
+	txIndex string,
+	expectedTransfers scenmodel.OutputTransferList,
+	output *vmcommon.VMOutput,
+) []error {
+	// "outputTransfers": "*" means any value is accepted, also the default
+	if expectedTransfers.IsStar {
+		return nil
+	}
+
+	actualTransfers := collectOutputTransfers(output)
+	var mismatches []error
+	if len(actualTransfers) < len(expectedTransfers.List) {
+		mismatches = append(mismatches, fmt.Errorf("too few output transfers. Tx '%s'. Want:%d. Got:%d",
+			txIndex,
+			len(expectedTransfers.List),
+			len(actualTransfers)))
+	}
+
+	for i, actualTransfer := range actualTransfers {
+		if i < len(expectedTransfers.List) {
+			err := ae.checkOutputTransfer(txIndex, i, expectedTransfers.List[i], actualTransfer)
+			if err != nil {
+				mismatches = append(mismatches, err)
+			}
+		} else if !expectedTransfers.MoreAllowedAtEnd {
+			mismatches = append(mismatches, fmt.Errorf("unexpected output transfer. Tx '%s'. Transfer index: %d. Transfer:\n%s",
+				txIndex,
+				i,
+				scenjwrite.OutputTransferToString(ae.convertOutputTransferToTestFormat(actualTransfer)),
+			))
+		}
+	}
+
+	return mismatches
+}
+
+func (ae *ScenarioExecutor) checkOutputTransfer(
+	txIndex string,
+	transferIndex int,
+	expected *scenmodel.CheckOutputTransfer,
+	actual *sentTransfer) error {
+	// unlike other checks, unspecified fields are ignored
+	var badField string
+	switch {
+	case !expected.From.IsUnspecified() && !expected.From.Check(actual.from):
+		badField = "sender"
+	case !expected.To.IsUnspecified() && !expected.To.Check(actual.to):
+		badField = "recipient"
+	case !expected.Value.IsUnspecified() && !expected.Value.Check(actual.value):
+		badField = "value"
+	case expected.DCDT != nil && !checkDCDTTransfers(expected.DCDT, actual.dcdt):
+		badField = "DCDT"
+	case !expected.Data.IsUnspecified() && !expected.Data.Check(actual.data):
+		badField = "data"
+	case !expected.GasLimit.IsUnspecified() && !expected.GasLimit.Check(actual.gasLimit):
+		badField = "gas limit"
+	case len(expected.CallType) > 0 && expected.CallType != actual.callType.ToString():
+		badField = "call type"
+	default:
+		return nil
+	}
+
+	return fmt.Errorf("bad output transfer %s. Tx '%s'. Transfer index: %d. Want:\n%s\nGot:\n%s",
+		badField,
+		txIndex,
+		transferIndex,
+		scenjwrite.OutputTransferToString(expected),
+		scenjwrite.OutputTransferToString(ae.convertOutputTransferToTestFormat(actual)))
+}
+
+func checkDCDTTransfers(expected []*scenmodel.CheckDCDTTransfer, actual []*vmcommon.DCDTTransfer) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i, dcdtTransfer := range actual {
+		if (!expected[i].TokenIdentifier.IsUnspecified() && !expected[i].TokenIdentifier.Check(dcdtTransfer.DCDTTokenName)) ||
+			(!expected[i].Nonce.IsUnspecified() && !expected[i].Nonce.Check(dcdtTransfer.DCDTTokenNonce)) ||
+			(!expected[i].Value.IsUnspecified() && !expected[i].Value.Check(dcdtTransfer.DCDTValue)) {
+			return false
+		}
+	}
+	return true
+}
+
+// convertOutputTransferToTestFormat describes an actual transfer in scenario format, all fields specified.
+// Used in error messages and in record mode.
+func (ae *ScenarioExecutor) convertOutputTransferToTestFormat(transfer *sentTransfer) *scenmodel.CheckOutputTransfer {
+	testTransfer := &scenmodel.CheckOutputTransfer{
+		From:  ae.recordCheckBytes(transfer.from, er.AddressHint),
+		To:    ae.recordCheckBytes(transfer.to, er.AddressHint),
+		Value: ae.recordCheckBigInt(transfer.value),
+		Data:  ae.recordCheckBytes(transfer.data, er.StrHint),
+		GasLimit: scenmodel.JSONCheckUint64{
+			Value:    transfer.gasLimit,
+			Original: ae.exprReconstructor.ReconstructFromUint64(transfer.gasLimit),
+		},
+		CallType: transfer.callType.ToString(),
+	}
+	for _, dcdtTransfer := range transfer.dcdt {
+		testTransfer.DCDT = append(testTransfer.DCDT, &scenmodel.CheckDCDTTransfer{
+			TokenIdentifier: ae.recordCheckBytes(dcdtTransfer.DCDTTokenName, er.StrHint),
+			Nonce: scenmodel.JSONCheckUint64{
+				Value:    dcdtTransfer.DCDTTokenNonce,
+				Original: ae.exprReconstructor.ReconstructFromUint64(dcdtTransfer.DCDTTokenNonce),
+			},
+			Value: ae.recordCheckBigInt(dcdtTransfer.DCDTValue),
+		})
+	}
+	return testTransfer
+}
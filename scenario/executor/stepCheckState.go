@@ -3,11 +3,14 @@ package scenexec
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 
 	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenabi "github.com/kalyan3104/k-chain-scenario-go/scenario/abi"
 	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
@@ -24,6 +27,14 @@ func (ae *ScenarioExecutor) ExecuteCheckStateStep(step *scenmodel.CheckStateStep
 		log.Trace("CheckStateStep", "comment", step.Comment)
 	}
 
+	if len(step.GoldenPath) > 0 {
+		return ae.executeGoldenCheckStateStep(step)
+	}
+
+	if ae.recordMode {
+		return ae.recordCheckState(step)
+	}
+
 	baseErrMsg := checkStateBaseErrorMsg(step)
 	return ae.checkAccounts(baseErrMsg, step.CheckAccounts)
 }
@@ -36,15 +47,17 @@ func checkStateBaseErrorMsg(step *scenmodel.CheckStateStep) string {
 }
 
 func (ae *ScenarioExecutor) checkAccounts(baseErrMsg string, checkAccounts *scenmodel.CheckAccounts) error {
+	var mismatches []error
+	var mismatchedAccounts []mismatchedAccount
 	if !checkAccounts.MoreAccountsAllowed {
-		for worldAcctAddr := range ae.World.AcctMap {
-			postAcctMatch := scenmodel.FindCheckAccount(checkAccounts.Accounts, []byte(worldAcctAddr))
-			if postAcctMatch == nil && !bytes.Equal([]byte(worldAcctAddr), vmcommon.SystemAccountAddress) {
-				return fmt.Errorf("%s unexpected account address: %s",
-					baseErrMsg,
+		for _, worldAcct := range ae.sortedWorldAccounts() {
+			postAcctMatch := scenmodel.FindCheckAccount(checkAccounts.Accounts, worldAcct.Address)
+			if postAcctMatch == nil && !bytes.Equal(worldAcct.Address, vmcommon.SystemAccountAddress) {
+				mismatches = append(mismatches, fmt.Errorf("unexpected account address: %s",
 					ae.exprReconstructor.Reconstruct(
-						[]byte(worldAcctAddr),
-						er.AddressHint))
+						worldAcct.Address,
+						er.AddressHint)))
+				mismatchedAccounts = append(mismatchedAccounts, mismatchedAccount{actual: worldAcct})
 			}
 		}
 	}
@@ -52,101 +65,118 @@ func (ae *ScenarioExecutor) checkAccounts(baseErrMsg string, checkAccounts *scen
 	for _, expectedAcct := range checkAccounts.Accounts {
 		matchingAcct, isMatch := ae.World.AcctMap[string(expectedAcct.Address.Value)]
 		if !isMatch {
-			return fmt.Errorf("%s account %s expected but not found after running test",
-				baseErrMsg,
-				expectedAcct.Address.Original)
+			mismatches = append(mismatches, fmt.Errorf("account %s expected but not found after running test",
+				expectedAcct.Address.Original))
+			mismatchedAccounts = append(mismatchedAccounts, mismatchedAccount{expected: expectedAcct})
+			continue
 		}
 
 		if !bytes.Equal(matchingAcct.Address, expectedAcct.Address.Value) {
-			return fmt.Errorf("%s bad account address %s",
-				baseErrMsg,
+			mismatches = append(mismatches, fmt.Errorf("bad account address %s",
 				ae.exprReconstructor.Reconstruct(
 					matchingAcct.Address,
-					er.AddressHint))
+					er.AddressHint)))
+			continue
 		}
 
-		if !expectedAcct.Nonce.Check(matchingAcct.Nonce) {
-			return fmt.Errorf("%s bad account nonce. Account: %s. Want: \"%s\". Have: \"%d\"",
-				baseErrMsg,
-				expectedAcct.Address.Original,
-				expectedAcct.Nonce.Original,
-				matchingAcct.Nonce)
+		accountMismatches, err := ae.checkAccount(expectedAcct, matchingAcct)
+		if err != nil {
+			return err
 		}
-
-		if !expectedAcct.Balance.Check(matchingAcct.Balance) {
-			return fmt.Errorf("%s bad account balance. Account: %s. Want: \"%s\". Have: \"%s\"",
-				baseErrMsg,
-				expectedAcct.Address.Original,
-				expectedAcct.Balance.Original,
-				ae.exprReconstructor.ReconstructFromBigInt(matchingAcct.Balance))
+		mismatches = append(mismatches, accountMismatches...)
+		if len(accountMismatches) > 0 {
+			mismatchedAccounts = append(mismatchedAccounts, mismatchedAccount{
+				expected: expectedAcct,
+				actual:   matchingAcct,
+			})
 		}
+	}
 
-		if !expectedAcct.Username.Check(matchingAcct.Username) {
-			return fmt.Errorf("%s bad account username. Account: %s. Want: %s. Have: \"%s\"",
-				baseErrMsg,
-				expectedAcct.Address.Original,
-				oj.JSONString(expectedAcct.Username.Original),
-				ae.exprReconstructor.Reconstruct(
-					matchingAcct.Username,
-					er.StrHint))
-		}
+	return ae.withAccountDiffs(ae.mismatchError(baseErrMsg, mismatches), mismatchedAccounts)
+}
 
-		if !expectedAcct.Code.Check(matchingAcct.Code) {
-			return fmt.Errorf("%s bad account code. Account: %s. Want: %s. Have: \"%s\"",
-				baseErrMsg,
-				expectedAcct.Address.Original,
-				oj.JSONString(expectedAcct.Code.Original),
-				ae.exprReconstructor.Reconstruct(
-					matchingAcct.Code,
-					er.CodeHint))
-		}
+// checkAccount yields all differences between an expected account and the account found in the world.
+// The returned error is not a mismatch, it means the check could not be performed.
+func (ae *ScenarioExecutor) checkAccount(expectedAcct *scenmodel.CheckAccount, matchingAcct *worldmock.Account) ([]error, error) {
+	var mismatches []error
 
-		if !expectedAcct.CodeMetadata.IsUnspecified() &&
-			!expectedAcct.CodeMetadata.Check(matchingAcct.CodeMetadata) {
-			return fmt.Errorf("%s bad account code metadata. Account: %s. Want: %s. Have: \"%s\"",
-				baseErrMsg,
-				expectedAcct.Address.Original,
-				oj.JSONString(expectedAcct.CodeMetadata.Original),
-				ae.exprReconstructor.Reconstruct(
-					matchingAcct.CodeMetadata,
-					er.HexHint))
-		}
+	if !expectedAcct.Nonce.Check(matchingAcct.Nonce) {
+		mismatches = append(mismatches, fmt.Errorf("bad account nonce. Account: %s. Want: \"%s\". Have: \"%d\"",
+			expectedAcct.Address.Original,
+			expectedAcct.Nonce.Original,
+			matchingAcct.Nonce))
+	}
 
-		if !expectedAcct.Owner.IsUnspecified() && !bytes.Equal(matchingAcct.OwnerAddress, expectedAcct.Owner.Value) {
-			return fmt.Errorf("%s bad account owner. Account: %s. Want: %s. Have: \"%s\"",
-				baseErrMsg,
-				expectedAcct.Address.Original,
-				oj.JSONString(expectedAcct.Owner.Original),
-				ae.exprReconstructor.Reconstruct(
-					matchingAcct.OwnerAddress,
-					er.AddressHint))
-		}
+	if !expectedAcct.Balance.Check(matchingAcct.Balance) {
+		mismatches = append(mismatches, fmt.Errorf("bad account balance. Account: %s. Want: \"%s\". Have: \"%s\"",
+			expectedAcct.Address.Original,
+			expectedAcct.Balance.Original,
+			ae.exprReconstructor.ReconstructFromBigInt(matchingAcct.Balance)))
+	}
 
-		// currently ignoring asyncCallData that is unspecified in the json
-		if !expectedAcct.AsyncCallData.IsUnspecified() &&
-			!expectedAcct.AsyncCallData.Check([]byte(matchingAcct.AsyncCallData)) {
-			return fmt.Errorf("%s bad async call data. Account: %s. Want: [%s]. Have: [%s]",
-				baseErrMsg,
-				expectedAcct.Address.Original,
-				expectedAcct.AsyncCallData.Original,
-				matchingAcct.AsyncCallData)
-		}
+	if !expectedAcct.Username.Check(matchingAcct.Username) {
+		mismatches = append(mismatches, fmt.Errorf("bad account username. Account: %s. Want: %s. Have: \"%s\"",
+			expectedAcct.Address.Original,
+			oj.JSONString(expectedAcct.Username.Original),
+			ae.exprReconstructor.Reconstruct(
+				matchingAcct.Username,
+				er.StrHint)))
+	}
 
-		err := ae.checkAccountStorage(baseErrMsg, expectedAcct, matchingAcct)
-		if err != nil {
-			return err
-		}
+	if !expectedAcct.Code.Check(matchingAcct.Code) {
+		mismatches = append(mismatches, fmt.Errorf("bad account code. Account: %s. Want: %s. Have: \"%s\"",
+			expectedAcct.Address.Original,
+			oj.JSONString(expectedAcct.Code.Original),
+			ae.exprReconstructor.Reconstruct(
+				matchingAcct.Code,
+				er.CodeHint)))
+	}
 
-		err = ae.checkAccountDCDT(baseErrMsg, expectedAcct, matchingAcct)
-		if err != nil {
-			return err
-		}
+	if !expectedAcct.CodeMetadata.IsUnspecified() &&
+		!expectedAcct.CodeMetadata.Check(matchingAcct.CodeMetadata) {
+		mismatches = append(mismatches, fmt.Errorf("bad account code metadata. Account: %s. Want: %s. Have: \"%s\"",
+			expectedAcct.Address.Original,
+			oj.JSONString(expectedAcct.CodeMetadata.Original),
+			ae.exprReconstructor.Reconstruct(
+				matchingAcct.CodeMetadata,
+				er.HexHint)))
 	}
 
-	return nil
+	if !expectedAcct.Owner.IsUnspecified() && !bytes.Equal(matchingAcct.OwnerAddress, expectedAcct.Owner.Value) {
+		mismatches = append(mismatches, fmt.Errorf("bad account owner. Account: %s. Want: %s. Have: \"%s\"",
+			expectedAcct.Address.Original,
+			oj.JSONString(expectedAcct.Owner.Original),
+			ae.exprReconstructor.Reconstruct(
+				matchingAcct.OwnerAddress,
+				er.AddressHint)))
+	}
+
+	// currently ignoring asyncCallData that is unspecified in the json
+	if !expectedAcct.AsyncCallData.IsUnspecified() &&
+		!expectedAcct.AsyncCallData.Check([]byte(matchingAcct.AsyncCallData)) {
+		mismatches = append(mismatches, fmt.Errorf("bad async call data. Account: %s. Want: [%s]. Have: [%s]",
+			expectedAcct.Address.Original,
+			expectedAcct.AsyncCallData.Original,
+			matchingAcct.AsyncCallData))
+	}
+
+	storageMismatch := ae.checkAccountStorage(expectedAcct, matchingAcct)
+	if storageMismatch != nil {
+		mismatches = append(mismatches, storageMismatch)
+	}
+
+	dcdtMismatch, err := ae.checkAccountDCDT(expectedAcct, matchingAcct)
+	if err != nil {
+		return nil, err
+	}
+	if dcdtMismatch != nil {
+		mismatches = append(mismatches, dcdtMismatch)
+	}
+
+	return mismatches, nil
 }
 
-func (ae *ScenarioExecutor) checkAccountStorage(baseErrMsg string, expectedAcct *scenmodel.CheckAccount, matchingAcct *worldmock.Account) error {
+func (ae *ScenarioExecutor) checkAccountStorage(expectedAcct *scenmodel.CheckAccount, matchingAcct *worldmock.Account) error {
 	if expectedAcct.IgnoreStorage {
 		return nil
 	}
@@ -163,8 +193,13 @@ func (ae *ScenarioExecutor) checkAccountStorage(baseErrMsg string, expectedAcct
 	for k := range matchingAcct.Storage {
 		allKeys[k] = true
 	}
-	storageError := ""
+	sortedKeys := make([]string, 0, len(allKeys))
 	for k := range allKeys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+	storageError := ""
+	for _, k := range sortedKeys {
 		// ignore all reserved keys
 		if strings.HasPrefix(k, core.ProtectedKeyPrefix) {
 			continue
@@ -184,24 +219,32 @@ func (ae *ScenarioExecutor) checkAccountStorage(baseErrMsg string, expectedAcct
 		have := matchingAcct.StorageValue(k)
 
 		if !want.Check(have) {
+			keyExpr, found := ae.abiStorageKey(matchingAcct.Address, []byte(k))
+			if !found {
+				keyExpr = ae.exprReconstructor.Reconstruct([]byte(k), er.NoHint)
+			}
+			havePretty := fmt.Sprintf("\"%s\"", ae.exprReconstructor.Reconstruct(have, er.NoHint))
+			if haveTree := ae.storageValueTree(matchingAcct.Address, []byte(k), have); haveTree != nil {
+				havePretty = scenabi.SingleLineJSON(haveTree)
+			}
 			storageError += fmt.Sprintf(
-				"\n  for key %s: Want: %s. Have: \"%s\"",
-				ae.exprReconstructor.Reconstruct([]byte(k), er.NoHint),
+				"\n  for key %s: Want: %s. Have: %s",
+				keyExpr,
 				oj.JSONString(want.Original),
-				ae.exprReconstructor.Reconstruct(have, er.NoHint))
+				havePretty)
 		}
 	}
 	if len(storageError) > 0 {
-		return fmt.Errorf("%s wrong account storage for account \"%s\":%s",
-			baseErrMsg,
+		return fmt.Errorf("wrong account storage for account \"%s\":%s",
 			expectedAcct.Address.Original, storageError)
 	}
 	return nil
 }
 
-func (ae *ScenarioExecutor) checkAccountDCDT(baseErrMsg string, expectedAcct *scenmodel.CheckAccount, matchingAcct *worldmock.Account) error {
+// checkAccountDCDT returns a single mismatch error that lists all token differences.
+func (ae *ScenarioExecutor) checkAccountDCDT(expectedAcct *scenmodel.CheckAccount, matchingAcct *worldmock.Account) (error, error) {
 	if expectedAcct.IgnoreDCDT {
-		return nil
+		return nil, nil
 	}
 
 	systemAccStorage := make(map[string][]byte)
@@ -214,7 +257,7 @@ func (ae *ScenarioExecutor) checkAccountDCDT(baseErrMsg string, expectedAcct *sc
 	expectedTokens := getExpectedTokens(expectedAcct)
 	accountTokens, err := dcdtconvert.GetFullMockDCDTData(matchingAcct.Storage, systemAccStorage)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	allTokenNames := make(map[string]bool)
@@ -224,8 +267,13 @@ func (ae *ScenarioExecutor) checkAccountDCDT(baseErrMsg string, expectedAcct *sc
 	for tokenName := range accountTokens {
 		allTokenNames[tokenName] = true
 	}
-	var errs []error
+	sortedTokenNames := make([]string, 0, len(allTokenNames))
 	for tokenName := range allTokenNames {
+		sortedTokenNames = append(sortedTokenNames, tokenName)
+	}
+	sort.Strings(sortedTokenNames)
+	var errs []error
+	for _, tokenName := range sortedTokenNames {
 		expectedToken := expectedTokens[tokenName]
 		accountToken := accountTokens[tokenName]
 		if expectedToken == nil {
@@ -252,10 +300,10 @@ func (ae *ScenarioExecutor) checkAccountDCDT(baseErrMsg string, expectedAcct *sc
 
 	errorString := makeErrorString(errs)
 	if len(errorString) > 0 {
-		return fmt.Errorf("%s mismatch for account \"%s\":%s", baseErrMsg, accountAddress, errorString)
+		return fmt.Errorf("mismatch for account \"%s\":%s", accountAddress, errorString), nil
 	}
 
-	return nil
+	return nil, nil
 }
 
 func getExpectedTokens(expectedAcct *scenmodel.CheckAccount) map[string]*scenmodel.CheckDCDTData {
@@ -442,6 +490,33 @@ func checkTokenRoles(
 	return errors
 }
 
+// mismatchError builds the error returned by a check, out of all the mismatches found.
+// By default only the first mismatch is reported, unless the executor is set to collect all of them.
+func (ae *ScenarioExecutor) mismatchError(baseErrMsg string, mismatches []error) error {
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	if len(mismatches) == 1 || !ae.collectAllMismatches {
+		if len(baseErrMsg) == 0 {
+			return mismatches[0]
+		}
+		return fmt.Errorf("%s %w", baseErrMsg, mismatches[0])
+	}
+
+	header := fmt.Sprintf("%d mismatches found:", len(mismatches))
+	if len(baseErrMsg) > 0 {
+		header = baseErrMsg + " " + header
+	}
+	var sb strings.Builder
+	sb.WriteString(header)
+	for _, mismatch := range mismatches {
+		sb.WriteString("\n  ")
+		sb.WriteString(strings.ReplaceAll(mismatch.Error(), "\n", "\n  "))
+	}
+	return errors.New(sb.String())
+}
+
 func makeErrorString(errors []error) string {
 	errorString := ""
 	for _, err := range errors {
@@ -0,0 +1,132 @@
+package scenexec
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	er "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
+)
+
+// checkStateUnchanged compares the accounts before and after a tx.
+// Only the sender nonce increment and the gas fee are allowed.
+// Catches state that a failed tx leaves behind, including bugs in the mock rollback.
+func (ae *ScenarioExecutor) checkStateUnchanged(
+	txIndex string,
+	tx *scenmodel.Transaction,
+	stateBefore worldmock.AccountMap,
+) error {
+	// the state before, with the sender nonce and gas fee already applied
+	expectedBefore := stateBefore.Clone()
+	if tx.Type.HasSender() {
+		if sender := expectedBefore.GetAccount(tx.From.Value); sender != nil {
+			sender.Nonce++
+			gasPayment := big.NewInt(0).Mul(
+				big.NewInt(0).SetUint64(tx.GasLimit.Value),
+				big.NewInt(0).SetUint64(tx.GasPrice.Value))
+			sender.Balance.Sub(sender.Balance, gasPayment)
+		}
+	}
+
+	var mismatches []error
+	for _, address := range sortedAddresses(expectedBefore, ae.World.AcctMap) {
+		before, existedBefore := expectedBefore[address]
+		after, existsAfter := ae.World.AcctMap[address]
+		addressStr := ae.exprReconstructor.Reconstruct([]byte(address), er.AddressHint)
+		switch {
+		case !existedBefore:
+			mismatches = append(mismatches, fmt.Errorf("account created. Tx '%s'. Account: %s",
+				txIndex, addressStr))
+		case !existsAfter:
+			mismatches = append(mismatches, fmt.Errorf("account deleted. Tx '%s'. Account: %s",
+				txIndex, addressStr))
+		default:
+			for _, diff := range ae.accountChanges(before, after) {
+				mismatches = append(mismatches, fmt.Errorf("account %s changed. Tx '%s'. Account: %s. Want: %s. Have: %s",
+					diff.field, txIndex, addressStr, diff.before, diff.after))
+			}
+		}
+	}
+
+	return ae.mismatchError("", mismatches)
+}
+
+type accountChange struct {
+	field  string
+	before string
+	after  string
+}
+
+// accountChanges lists the fields that differ between two versions of the same account.
+func (ae *ScenarioExecutor) accountChanges(before, after *worldmock.Account) []accountChange {
+	var changes []accountChange
+	compareBigInt := func(field string, valueBefore, valueAfter *big.Int) {
+		if bigIntOrZero(valueBefore).Cmp(bigIntOrZero(valueAfter)) != 0 {
+			changes = append(changes, accountChange{field,
+				bigIntOrZero(valueBefore).String(), bigIntOrZero(valueAfter).String()})
+		}
+	}
+	compareBytes := func(field string, valueBefore, valueAfter []byte, hint er.ExprReconstructorHint) {
+		if !bytes.Equal(valueBefore, valueAfter) {
+			changes = append(changes, accountChange{field,
+				ae.exprReconstructor.Reconstruct(valueBefore, hint),
+				ae.exprReconstructor.Reconstruct(valueAfter, hint)})
+		}
+	}
+
+	if before.Nonce != after.Nonce {
+		changes = append(changes, accountChange{"nonce",
+			fmt.Sprint(before.Nonce), fmt.Sprint(after.Nonce)})
+	}
+	compareBigInt("balance", before.Balance, after.Balance)
+	compareBigInt("balance delta", before.BalanceDelta, after.BalanceDelta)
+	compareBigInt("developer reward", before.DeveloperReward, after.DeveloperReward)
+	compareBytes("code", before.Code, after.Code, er.CodeHint)
+	compareBytes("code metadata", before.CodeMetadata, after.CodeMetadata, er.NoHint)
+	compareBytes("owner", before.OwnerAddress, after.OwnerAddress, er.AddressHint)
+	compareBytes("username", before.Username, after.Username, er.StrHint)
+
+	keys := make(map[string]struct{})
+	for key := range before.Storage {
+		keys[key] = struct{}{}
+	}
+	for key := range after.Storage {
+		keys[key] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		field := "storage " + ae.exprReconstructor.Reconstruct([]byte(key), er.StrHint)
+		compareBytes(field, before.Storage[key], after.Storage[key], er.NoHint)
+	}
+
+	return changes
+}
+
+func sortedAddresses(maps ...worldmock.AccountMap) []string {
+	addressSet := make(map[string]struct{})
+	for _, accounts := range maps {
+		for address := range accounts {
+			addressSet[address] = struct{}{}
+		}
+	}
+	addresses := make([]string, 0, len(addressSet))
+	for address := range addressSet {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+func bigIntOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return big.NewInt(0)
+	}
+	return value
+}
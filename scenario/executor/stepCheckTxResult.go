@@ -14,83 +14,96 @@ import (
 
 func (ae *ScenarioExecutor) checkTxResults(
 	txIndex string,
+	tx *scenmodel.Transaction,
 	blResult *scenmodel.TransactionResult,
 	checkGas bool,
 	output *vmcommon.VMOutput,
 ) error {
 
+	var mismatches []error
 	if !blResult.Status.Check(big.NewInt(int64(output.ReturnCode))) {
-		return fmt.Errorf("result code mismatch. Tx '%s'. Want: %s. Have: %d (%s). Message: %s",
-			txIndex, blResult.Status.Original, int(output.ReturnCode), output.ReturnCode.String(), output.ReturnMessage)
+		mismatches = append(mismatches, fmt.Errorf("result code mismatch. Tx '%s'. Want: %s. Have: %d (%s). Message: %s",
+			txIndex, blResult.Status.Original, int(output.ReturnCode), output.ReturnCode.String(), output.ReturnMessage))
 	}
 
 	if !blResult.Message.Check([]byte(output.ReturnMessage)) {
-		return fmt.Errorf("result message mismatch. Tx '%s'. Want: %s. Have: %s",
-			txIndex, blResult.Message.Original, output.ReturnMessage)
+		mismatches = append(mismatches, fmt.Errorf("result message mismatch. Tx '%s'. Want: %s. Have: %s",
+			txIndex, blResult.Message.Original, output.ReturnMessage))
 	}
 
 	// check result
 	if !blResult.Out.CheckList(output.ReturnData) {
-		return fmt.Errorf("result mismatch. Tx '%s'. Want: %s. Have: %s",
+		mismatches = append(mismatches, fmt.Errorf("result mismatch. Tx '%s'. Want: %s. Have: %s",
 			txIndex,
-			checkBytesListPretty(blResult.Out),
-			ae.exprReconstructor.ReconstructList(output.ReturnData, er.NoHint))
+			expectedResultsPretty(tx, blResult.Out),
+			ae.resultsPretty(tx, output.ReturnData)))
 	}
 
 	// check refund
 	if !blResult.Refund.Check(output.GasRefund) {
-		return fmt.Errorf("result gas refund mismatch. Tx '%s'. Want: %s. Have: 0x%x",
-			txIndex, blResult.Refund.Original, output.GasRefund)
+		mismatches = append(mismatches, fmt.Errorf("result gas refund mismatch. Tx '%s'. Want: %s. Have: 0x%x",
+			txIndex, blResult.Refund.Original, output.GasRefund))
 	}
 
 	// check gas
 	// unlike other checks, if unspecified the remaining gas check is ignored
 	if checkGas && !blResult.Gas.IsUnspecified() && !blResult.Gas.Check(output.GasRemaining) {
-		return fmt.Errorf("result gas mismatch. Tx '%s'. Want: %s. Got: %d (0x%x)",
+		mismatches = append(mismatches, fmt.Errorf("result gas mismatch. Tx '%s'. Want: %s. Got: %d (0x%x)",
 			txIndex,
 			blResult.Gas.Original,
 			output.GasRemaining,
-			output.GasRemaining)
+			output.GasRemaining))
 	}
 
-	return ae.checkTxLogs(txIndex, blResult.Logs, output.Logs)
+	mismatches = append(mismatches, ae.checkTxLogs(txIndex, blResult.Logs, output.Logs)...)
+	mismatches = append(mismatches, ae.checkOutputTransfers(txIndex, blResult.OutputTransfers, output)...)
+
+	return ae.mismatchError("", mismatches)
 }
 
 func (ae *ScenarioExecutor) checkTxLogs(
 	txIndex string,
 	expectedLogs scenmodel.LogList,
 	actualLogs []*vmcommon.LogEntry,
-) error {
+) []error {
 	// "logs": "*" means any value is accepted, log check ignored
 	if expectedLogs.IsStar {
 		return nil
 	}
 
+	// logs with ignored identifiers are left out of the check
+	checkedIndexes := checkedLogIndexes(expectedLogs, actualLogs)
+	if expectedLogs.Unordered {
+		return ae.checkTxLogsUnordered(txIndex, expectedLogs, actualLogs, checkedIndexes)
+	}
+
 	// this is the real log check
-	if len(actualLogs) < len(expectedLogs.List) {
-		return fmt.Errorf("too few logs. Tx '%s'. Want:%d. Got:%d",
+	var mismatches []error
+	if len(checkedIndexes) < len(expectedLogs.List) {
+		mismatches = append(mismatches, fmt.Errorf("too few logs. Tx '%s'. Want:%d. Got:%d",
 			txIndex,
 			len(expectedLogs.List),
-			len(actualLogs))
+			len(checkedIndexes)))
 	}
 
-	for i, actualLog := range actualLogs {
-		if i < len(expectedLogs.List) {
-			testLog := expectedLogs.List[i]
+	for checkedIndex, i := range checkedIndexes {
+		actualLog := actualLogs[i]
+		if checkedIndex < len(expectedLogs.List) {
+			testLog := expectedLogs.List[checkedIndex]
 			err := ae.checkTxLog(txIndex, i, testLog, actualLog)
 			if err != nil {
-				return err
+				mismatches = append(mismatches, err)
 			}
 		} else if !expectedLogs.MoreAllowedAtEnd {
-			return fmt.Errorf("unexpected log. Tx '%s'. Log index: %d. Log:\n%s",
+			mismatches = append(mismatches, fmt.Errorf("unexpected log. Tx '%s'. Log index: %d. Log:\n%s",
 				txIndex,
 				i,
 				scenjwrite.LogToString(ae.convertLogToTestFormat(actualLog)),
-			)
+			))
 		}
 	}
 
-	return nil
+	return mismatches
 }
 
 func (ae *ScenarioExecutor) checkTxLog(
@@ -140,6 +153,12 @@ func checkBytesListPretty(jcbl scenmodel.JSONCheckValueList) string {
 
 		str += oj.JSONString(jcb.Original)
 	}
+	if jcbl.MoreAllowed {
+		if len(jcbl.Values) > 0 {
+			str += ", "
+		}
+		str += oj.JSONString(&oj.OJsonString{Value: scenmodel.JSONCheckValueListMore})
+	}
 	return str + "]"
 }
 
@@ -176,6 +195,10 @@ func (ae *ScenarioExecutor) convertLogToTestFormat(outputLog *vmcommon.LogEntry)
 		Data:   dataField,
 		Topics: topics,
 	}
+	if topicTrees, dataTrees, found := ae.logTrees(outputLog); found {
+		testLog.Topics = checkValueListFromTrees(outputLog.Topics, topicTrees)
+		testLog.Data = checkValueListFromTrees(outputLog.Data, dataTrees)
+	}
 
 	return &testLog
 }
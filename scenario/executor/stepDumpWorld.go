@@ -18,7 +18,13 @@ import (
 
 const includeProtectedStorage = false
 
-func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmock.Account) (*scenmodel.Account, error) {
+// exprReconstructFunc converts raw bytes to a scenario expression, as shown in the converted accounts.
+type exprReconstructFunc func(value []byte, hint er.ExprReconstructorHint) string
+
+func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(
+	account *worldmock.Account,
+	reconstruct exprReconstructFunc,
+) (*scenmodel.Account, error) {
 	var storageKeys []string
 	for storageKey := range account.Storage {
 		storageKeys = append(storageKeys, storageKey)
@@ -30,14 +36,22 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 		storageValue := account.Storage[storageKey]
 		includeKey := includeProtectedStorage || !strings.HasPrefix(storageKey, core.ProtectedKeyPrefix)
 		if includeKey && len(storageValue) > 0 {
+			keyExpr, found := ae.abiStorageKey(account.Address, []byte(storageKey))
+			if !found {
+				keyExpr = reconstruct([]byte(storageKey), er.NoHint)
+			}
+			valueTree := ae.storageValueTree(account.Address, []byte(storageKey), storageValue)
+			if valueTree == nil {
+				valueTree = &oj.OJsonString{Value: reconstruct(storageValue, er.NoHint)}
+			}
 			storageKvps = append(storageKvps, &scenmodel.StorageKeyValuePair{
 				Key: scenmodel.JSONBytesFromString{
 					Value:    []byte(storageKey),
-					Original: ae.exprReconstructor.Reconstruct([]byte(storageKey), er.NoHint),
+					Original: keyExpr,
 				},
 				Value: scenmodel.JSONBytesFromTree{
 					Value:    storageValue,
-					Original: &oj.OJsonString{Value: ae.exprReconstructor.Reconstruct(storageValue, er.NoHint)},
+					Original: valueTree,
 				},
 			})
 		}
@@ -72,7 +86,7 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 			if len(mockInstance.TokenMetaData.Creator) > 0 {
 				creator = scenmodel.JSONBytesFromString{
 					Value:    mockInstance.TokenMetaData.Creator,
-					Original: ae.exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Creator, er.AddressHint),
+					Original: reconstruct(mockInstance.TokenMetaData.Creator, er.AddressHint),
 				}
 			}
 
@@ -88,7 +102,7 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 			if len(mockInstance.TokenMetaData.Hash) > 0 {
 				hash = scenmodel.JSONBytesFromString{
 					Value:    mockInstance.TokenMetaData.Hash,
-					Original: ae.exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Hash, er.NoHint),
+					Original: reconstruct(mockInstance.TokenMetaData.Hash, er.NoHint),
 				}
 			}
 
@@ -96,7 +110,7 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 			for _, uri := range mockInstance.TokenMetaData.URIs {
 				jsonUris = append(jsonUris, scenmodel.JSONBytesFromString{
 					Value:    uri,
-					Original: ae.exprReconstructor.Reconstruct(uri, er.StrHint),
+					Original: reconstruct(uri, er.StrHint),
 				})
 			}
 
@@ -104,7 +118,7 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 			if len(mockInstance.TokenMetaData.Attributes) > 0 {
 				attributes = scenmodel.JSONBytesFromTree{
 					Value:    mockInstance.TokenMetaData.Attributes,
-					Original: &oj.OJsonString{Value: ae.exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Attributes, er.NoHint)},
+					Original: &oj.OJsonString{Value: reconstruct(mockInstance.TokenMetaData.Attributes, er.NoHint)},
 				}
 			}
 
@@ -128,7 +142,7 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 		scenDCDT = append(scenDCDT, &scenmodel.DCDTData{
 			TokenIdentifier: scenmodel.JSONBytesFromString{
 				Value:    dcdtObj.TokenIdentifier,
-				Original: ae.exprReconstructor.Reconstruct(dcdtObj.TokenIdentifier, er.StrHint),
+				Original: reconstruct(dcdtObj.TokenIdentifier, er.StrHint),
 			},
 			Instances: scenInstances,
 			LastNonce: scenmodel.JSONUint64{
@@ -142,7 +156,7 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 	return &scenmodel.Account{
 		Address: scenmodel.JSONBytesFromString{
 			Value:    account.Address,
-			Original: ae.exprReconstructor.Reconstruct(account.Address, er.AddressHint),
+			Original: reconstruct(account.Address, er.AddressHint),
 		},
 		Nonce: scenmodel.JSONUint64{
 			Value:    account.Nonce,
@@ -152,22 +166,26 @@ func (ae *ScenarioExecutor) convertMockAccountToScenarioFormat(account *worldmoc
 			Value:    account.Balance,
 			Original: ae.exprReconstructor.ReconstructFromBigInt(account.Balance),
 		},
+		Username: scenmodel.JSONBytesFromString{
+			Value:    account.Username,
+			Original: reconstruct(account.Username, er.StrHint),
+		},
 		Storage:  storageKvps,
 		DCDTData: scenDCDT,
 		Owner: scenmodel.JSONBytesFromString{
 			Value:    account.OwnerAddress,
-			Original: ae.exprReconstructor.Reconstruct(account.OwnerAddress, er.AddressHint),
+			Original: reconstruct(account.OwnerAddress, er.AddressHint),
 		},
 	}, nil
 }
 
-// DumpWorld prints the state of the MockWorld to stdout.
+// DumpWorld prints the state of the MockWorld to the executor output.
 func (ae *ScenarioExecutor) DumpWorld() error {
-	fmt.Print("world state dump:\n")
+	fmt.Fprint(ae.output, "world state dump:\n")
 	var scenAccounts []*scenmodel.Account
 
 	for _, account := range ae.World.AcctMap {
-		scenAccount, err := ae.convertMockAccountToScenarioFormat(account)
+		scenAccount, err := ae.convertMockAccountToScenarioFormat(account, ae.exprReconstructor.Reconstruct)
 		if err != nil {
 			return err
 		}
@@ -176,7 +194,7 @@ func (ae *ScenarioExecutor) DumpWorld() error {
 
 	ojAccount := scenjwrite.AccountsToOJ(scenAccounts)
 	s := oj.JSONString(ojAccount)
-	fmt.Println(s)
+	fmt.Fprintln(ae.output, s)
 
 	return nil
 }
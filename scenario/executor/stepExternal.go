@@ -13,18 +13,36 @@ func (ae *ScenarioExecutor) ExecuteExternalStep(step *scenmodel.ExternalStepsSte
 	}
 
 	fileResolverBackup := ae.fileResolver
+	defer func() {
+		ae.fileResolver = fileResolverBackup
+	}()
 	clonedFileResolver := ae.fileResolver.Clone()
 	externalStepsRunner := scenio.NewScenarioController(ae, clonedFileResolver, ae.vmBuilder.GetVMType())
+	externalStepsRunner.Parser.ExprInterpreter.Constants = ae.constants
+	externalStepsRunner.Parser.ExprInterpreter.NegativeConstants = ae.negativeConstants
 
 	extAbsPth := ae.fileResolver.ResolveAbsolutePath(step.Path)
 	setExternalStepGasTracing(ae, step)
 
-	err := externalStepsRunner.RunSingleJSONScenario(extAbsPth, scenio.DefaultRunScenarioOptions())
+	options := scenio.DefaultRunScenarioOptions()
+	options.Record = ae.recordMode
+	constantsBackup := ae.constants
+	negativeConstantsBackup := ae.negativeConstants
+	capturedBefore := len(ae.capturedVariables)
+	ae.externalStepsDepth++
+	err := externalStepsRunner.RunSingleJSONScenario(extAbsPth, options)
+	ae.externalStepsDepth--
+	externalConstants := ae.constants
+	ae.constants = constantsBackup
+	ae.negativeConstants = negativeConstantsBackup
 	if err != nil {
 		return err
 	}
 
-	ae.fileResolver = fileResolverBackup
+	// the variables captured by the external steps are available to the steps after them
+	for _, name := range ae.capturedVariables[capturedBefore:] {
+		ae.constants[name] = externalConstants[name]
+	}
 
 	return nil
 }
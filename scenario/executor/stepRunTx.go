@@ -10,9 +10,16 @@ import (
 	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/data/vm"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 )
 
+// UpgradeFunctionName is the contract function the protocol calls to upgrade a contract,
+// the counterpart of core.SCDeployInitFunctionName for deploys.
+// An upgrade transaction has the data "upgradeContract@<code>@<codeMetadata>@<arguments...>"
+// and gets executed as a regular contract call, the VM recognizing the function and replacing the code.
+const UpgradeFunctionName = "upgradeContract"
+
 // ExecuteTxStep executes a TxStep.
 func (ae *ScenarioExecutor) ExecuteTxStep(step *scenmodel.TxStep) (*vmcommon.VMOutput, error) {
 	log.Trace("ExecuteTxStep", "id", step.TxIdent)
@@ -24,6 +31,11 @@ func (ae *ScenarioExecutor) ExecuteTxStep(step *scenmodel.TxStep) (*vmcommon.VMO
 		SetLoggingForTests()
 	}
 
+	var stateBefore worldmock.AccountMap
+	if !ae.recordMode && step.ExpectedResult != nil && step.ExpectedResult.StateUnchanged {
+		stateBefore = ae.World.AcctMap.Clone()
+	}
+
 	output, err := ae.executeTx(step.TxIdent, step.Tx)
 	if err != nil {
 		return nil, err
@@ -33,14 +45,36 @@ func (ae *ScenarioExecutor) ExecuteTxStep(step *scenmodel.TxStep) (*vmcommon.VMO
 		DisableLoggingForTests()
 	}
 
+	ae.recordCoverage(step.Tx, output)
+	ae.registerContractABI(step.Tx, output)
+
 	// check results
-	if step.ExpectedResult != nil {
-		err = ae.checkTxResults(step.TxIdent, step.ExpectedResult, ae.checkGas, output)
+	if ae.recordMode {
+		ae.recordTxResults(step, output)
+	} else if step.ExpectedResult != nil {
+		err = ae.checkTxResults(step.TxIdent, step.Tx, step.ExpectedResult, ae.checkGas, output)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if stateBefore != nil {
+		err = ae.checkStateUnchanged(step.TxIdent, step.Tx, stateBefore)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	err = checkGasBaseline(ae, step, output)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ae.captureTxOutputs(step, output)
+	if err != nil {
+		return nil, err
+	}
+
 	return output, nil
 }
 
@@ -98,8 +132,16 @@ func (ae *ScenarioExecutor) executeTx(txIndex string, tx *scenmodel.Transaction)
 			if err != nil {
 				return nil, err
 			}
-			if ae.PeekTraceGas() {
-				fmt.Println("\nIn txID:", txIndex, ", step type:Deploy", ", total gas used:", gasForExecution-output.GasRemaining)
+			if ae.PeekTraceGas() && ae.gasProfile == nil {
+				fmt.Fprintln(ae.output, "\nIn txID:", txIndex, ", step type:Deploy", ", total gas used:", gasForExecution-output.GasRemaining)
+			}
+		case scenmodel.ScUpgrade:
+			output, err = ae.scUpgrade(txIndex, tx, gasForExecution)
+			if err != nil {
+				return nil, err
+			}
+			if ae.PeekTraceGas() && ae.gasProfile == nil {
+				fmt.Fprintln(ae.output, "\nIn txID:", txIndex, ", step type:Upgrade", ", total gas used:", gasForExecution-output.GasRemaining)
 			}
 		case scenmodel.ScQuery:
 			// imitates the behaviour of the protocol
@@ -114,8 +156,8 @@ func (ae *ScenarioExecutor) executeTx(txIndex string, tx *scenmodel.Transaction)
 			if err != nil {
 				return nil, err
 			}
-			if ae.PeekTraceGas() {
-				fmt.Println("\nIn txID:", txIndex, ", step type:ScCall, function:", tx.Function, ", total gas used:", gasForExecution-output.GasRemaining)
+			if ae.PeekTraceGas() && ae.gasProfile == nil {
+				fmt.Fprintln(ae.output, "\nIn txID:", txIndex, ", step type:ScCall, function:", tx.Function, ", total gas used:", gasForExecution-output.GasRemaining)
 			}
 		case scenmodel.Transfer:
 			output = ae.simpleTransferOutput(tx)
@@ -277,6 +319,22 @@ func (ae *ScenarioExecutor) scCall(txIndex string, tx *scenmodel.Transaction, ga
 	return ae.vm.RunSmartContractCall(input)
 }
 
+// scUpgrade calls UpgradeFunctionName on the contract, with the new code and code metadata
+// before the upgrade arguments, the way the protocol does.
+func (ae *ScenarioExecutor) scUpgrade(txIndex string, tx *scenmodel.Transaction, gasLimit uint64) (*vmcommon.VMOutput, error) {
+	codeMetadata := tx.CodeMetadata.Value
+	if tx.CodeMetadata.Unspecified {
+		codeMetadata = DefaultCodeMetadata
+	}
+	upgradeTx := *tx
+	upgradeTx.Function = UpgradeFunctionName
+	upgradeTx.Arguments = append([]scenmodel.JSONBytesFromTree{
+		{Value: tx.Code.Value},
+		{Value: codeMetadata},
+	}, tx.Arguments...)
+	return ae.scCall(txIndex, &upgradeTx, gasLimit)
+}
+
 func (ae *ScenarioExecutor) directDCDTTransferFromTx(tx *scenmodel.Transaction) (uint64, error) {
 	nrTransfers := len(tx.DCDTValue)
 
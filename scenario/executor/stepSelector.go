@@ -22,9 +22,11 @@ func (ae *ScenarioExecutor) ExecuteStep(generalStep scenmodel.Step) error {
 		_, err = ae.ExecuteTxStep(step)
 	case *scenmodel.DumpStateStep:
 		err = ae.DumpWorld()
+	case *scenmodel.SetGasScheduleStep:
+		err = ae.ExecuteSetGasScheduleStep(step)
 	}
 
-	logGasTrace(ae)
+	logGasTrace(ae, generalStep)
 
 	return err
 }
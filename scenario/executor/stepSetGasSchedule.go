@@ -0,0 +1,29 @@
+package scenexec
+
+import (
+	"errors"
+
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// ExecuteSetGasScheduleStep executes a SetGasScheduleStep.
+// The VM and the builtin functions keep the new gas schedule until the end of the scenario.
+func (ae *ScenarioExecutor) ExecuteSetGasScheduleStep(step *scenmodel.SetGasScheduleStep) error {
+	if len(step.Comment) > 0 {
+		log.Trace("SetGasScheduleStep", "comment", step.Comment)
+	}
+
+	if ae.vm == nil {
+		return errors.New("cannot change the gas schedule before the VM is initialized")
+	}
+
+	gasSchedule, err := ae.gasScheduleMap(step.GasSchedule, step.GasSchedulePath, step.GasScheduleOverrides)
+	if err != nil {
+		return err
+	}
+
+	ae.changeGasSchedule(gasSchedule)
+	ae.gasScheduleChanged = true
+
+	return nil
+}
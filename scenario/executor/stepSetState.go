@@ -20,6 +20,7 @@ func (ae *ScenarioExecutor) ExecuteSetStateStep(step *scenmodel.SetStateStep) er
 	}
 
 	for _, scenAccount := range step.Accounts {
+		ae.registerCodePath(scenAccount.Code)
 		if scenAccount.Update {
 			err := ae.UpdateAccount(scenAccount)
 			if err != nil {
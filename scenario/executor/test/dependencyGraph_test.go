@@ -0,0 +1,41 @@
+package executortest
+
+import (
+	"path/filepath"
+	"testing"
+
+	scenio "github.com/kalyan3104/k-chain-scenario-go/scenario/io"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyGraphExternalSteps(t *testing.T) {
+	dir := filepath.Join(getTestRoot(), "scenarios-self-test/external_steps")
+	scenarioPath := filepath.Join(dir, "external_steps.scen.json")
+
+	graph := scenio.NewDependencyGraph(scenio.NewDefaultFileResolver())
+	require.Nil(t, graph.Update(scenarioPath))
+
+	require.True(t, graph.Contains(filepath.Join(dir, "nested/external_step_1.step.json")))
+	require.True(t, graph.DependsOn(scenarioPath, map[string]bool{
+		filepath.Join(dir, "external_step_2.step.json"): true,
+	}))
+	require.False(t, graph.DependsOn(scenarioPath, map[string]bool{
+		filepath.Join(dir, "external_steps.err.json"): true,
+	}))
+}
+
+func TestDependencyGraphFileValues(t *testing.T) {
+	dir := filepath.Join(getTestRoot(), "scenarios-self-test/set-check")
+	scenarioPath := filepath.Join(dir, "set-check-code.scen.json")
+
+	graph := scenio.NewDependencyGraph(scenio.NewDefaultFileResolver())
+	require.Nil(t, graph.Update(scenarioPath))
+
+	require.Equal(t, []string{
+		filepath.Join(dir, "set-check-code.scen.json"),
+		filepath.Join(dir, "set-check-dcdt.scen.json"),
+	}, graph.Files())
+	require.True(t, graph.DependsOn(scenarioPath, map[string]bool{
+		filepath.Join(dir, "set-check-dcdt.scen.json"): true,
+	}))
+}
@@ -1,7 +1,20 @@
 package executortest
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+
+	scenexec "github.com/kalyan3104/k-chain-scenario-go/scenario/executor"
+	scenio "github.com/kalyan3104/k-chain-scenario-go/scenario/io"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+	"github.com/stretchr/testify/require"
 )
 
 // Tests Scenarios consistency, no smart contracts.
@@ -15,6 +28,772 @@ import (
 //		CheckNoError()
 //}
 
+func TestScenariosSetCheckParallel(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		Jobs(4).
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosParallelFailure(t *testing.T) {
+	runWithJobs := func(jobs int) string {
+		return captureStdout(t, func() {
+			ScenariosTest(t).
+				Folder("scenarios-self-test").
+				Jobs(jobs).
+				Run().
+				RequireError("some tests failed")
+		})
+	}
+
+	sequentialOutput := runWithJobs(1)
+	parallelOutput := runWithJobs(4)
+	require.Contains(t, sequentialOutput, "FAIL:")
+	require.Contains(t, sequentialOutput, "Done. Passed: ")
+	require.Equal(t, sequentialOutput, parallelOutput)
+}
+
+func TestScenariosParallelGasTrace(t *testing.T) {
+	output := captureStdout(t, func() {
+		ScenariosTest(t).
+			Folder("scenarios-self-test/gas-trace").
+			EchoVM().
+			Jobs(3).
+			Run().
+			CheckNoError()
+	})
+
+	// each gas trace is printed along with the result of its scenario
+	for _, name := range []string{"a", "b", "c"} {
+		header := "Scenario: scenarios-self-test/gas-trace/" + name + ".scen.json ... "
+		start := strings.Index(output, header)
+		require.True(t, start >= 0, output)
+		scenarioOutput := output[start+len(header):]
+		end := strings.Index(scenarioOutput, "Scenario: ")
+		if end < 0 {
+			end = strings.Index(scenarioOutput, "Done.")
+		}
+		scenarioOutput = scenarioOutput[:end]
+		require.Contains(t, scenarioOutput, "In txID: "+name+"-call , step type:ScCall, function: "+name)
+		require.Equal(t, 1, strings.Count(scenarioOutput, "In txID:"), output)
+	}
+}
+
+func TestFindScenarioFilesMissingFolder(t *testing.T) {
+	_, err := scenio.FindScenarioFiles(filepath.Join(getTestRoot(), "scenarios-self-test/missing"), ".scen.json")
+	require.True(t, os.IsNotExist(err), err)
+}
+
+func TestScenariosMissingFolder(t *testing.T) {
+	output := captureStdout(t, func() {
+		runner := ScenariosTest(t).
+			Folder("scenarios-self-test/missing").
+			Run()
+		require.True(t, os.IsNotExist(runner.currentError), runner.currentError)
+	})
+	require.NotContains(t, output, "Done.")
+}
+
+// captureStdout yields what the function prints to stdout.
+func captureStdout(t *testing.T, f func()) string {
+	reader, writer, err := os.Pipe()
+	require.Nil(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	captured := make(chan string)
+	go func() {
+		output, _ := io.ReadAll(reader)
+		captured <- string(output)
+	}()
+
+	f()
+	_ = writer.Close()
+	return <-captured
+}
+
+func TestScenariosJSONReport(t *testing.T) {
+	var output bytes.Buffer
+	ScenariosTest(t).
+		Folder("scenarios-self-test").
+		Jobs(2).
+		Reporter(scenio.NewJSONLinesReporter(&output)).
+		Run().
+		RequireError("some tests failed")
+
+	reports := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var report map[string]interface{}
+		require.Nil(t, json.Unmarshal([]byte(line), &report))
+		reports[report["path"].(string)] = report
+	}
+
+	passed := reports["scenarios-self-test/transfer-rewa.scen.json"]
+	require.Equal(t, "passed", passed["status"])
+	require.NotContains(t, passed, "failedStepIndex")
+
+	failed := reports["scenarios-self-test/dcdt-zero-balance-check-err.scen.json"]
+	require.Equal(t, "failed", failed["status"])
+	require.Equal(t, float64(1), failed["failedStepIndex"])
+	require.Equal(t, "check-1", failed["failedStepId"])
+	require.Contains(t, failed["message"], "Bad balance")
+}
+
+func TestScenariosExternalStepsErrorLocation(t *testing.T) {
+	root := getTestRoot()
+	ScenariosTest(t).
+		Folder("scenarios-self-test/external_steps").
+		File("external_steps.err.json").
+		Run().
+		RequireError(`Check state "check-nonce": bad account nonce. Account: address:an_account. Want: "4". Have: "3"`).
+		RequireStepTrace(
+			path.Join(root, "scenarios-self-test/external_steps/external_steps.err.json")+":4:9",
+			path.Join(root, "scenarios-self-test/external_steps/nested/external_step_err.step.json")+":13:9")
+}
+
+func TestScenariosCheckFirstMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		File("set-check-multiple.err.json").
+		Run().
+		RequireError(
+			"Check state \"check-1\": bad account nonce. Account: address:the-address. Want: \"1002\". Have: \"1001\"")
+}
+
+func TestScenariosCheckOperatorsMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		File("set-check-operators.err.json").
+		CollectAllMismatches().
+		Run().
+		RequireError(
+			`Check state "check-1": 4 mismatches found:
+  bad account nonce. Account: address:the-address. Want: ">5". Have: "5"
+  bad account balance. Account: address:the-address. Want: "approx:999,000±0.05%". Have: "1000001"
+  bad account username. Account: address:the-address. Want: "regex:^ERR_.*". Have: "str:OK"
+  wrong account storage for account "address:the-address":
+    for key 0x636f756e746572 (str:counter): Want: "0..1000". Have: "0x05dc (1500)"`)
+}
+
+func TestScenariosCheckAllMismatches(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		File("set-check-multiple.err.json").
+		CollectAllMismatches().
+		Run().
+		RequireError(
+			`Check state "check-1": 4 mismatches found:
+  bad account nonce. Account: address:the-address. Want: "1002". Have: "1001"
+  bad account balance. Account: address:the-address. Want: "200". Have: "100"
+  wrong account storage for account "address:the-address":
+    for key 0x6b6579 (str:key): Want: "str:another-value". Have: "0x76616c7565 (str:value)"
+  account address:missing-address expected but not found after running test`)
+}
+
+func TestScenariosCheckUnexpectedAccountsSorted(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		File("set-check-unexpected.err.json").
+		CollectAllMismatches().
+		Run().
+		RequireError(
+			`Check state "check-1": 3 mismatches found:
+  unexpected account address: address:a
+  unexpected account address: address:c
+  unexpected account address: address:d`)
+}
+
+func TestScenariosAccountDiff(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		File("set-check-multiple.err.json").
+		CollectAllMismatches().
+		AccountDiff(scenexec.PlainAccountDiff).
+		Run().
+		RequireError(
+			`Check state "check-1": 4 mismatches found:
+  bad account nonce. Account: address:the-address. Want: "1002". Have: "1001"
+  bad account balance. Account: address:the-address. Want: "200". Have: "100"
+  wrong account storage for account "address:the-address":
+    for key 0x6b6579 (str:key): Want: "str:another-value". Have: "0x76616c7565 (str:value)"
+  account address:missing-address expected but not found after running test
+  "address:the-address": {
+-     "nonce": "1002"
++     "nonce": "1001"
+-     "balance": "200"
++     "balance": "100"
+      "storage": {
+-         "str:key": "str:another-value"
++         "str:key": "str:value"
+      }
+      "code": ""
+  }
+- "address:missing-address": {
+-     "nonce": "0"
+- }`)
+}
+
+func TestScenariosCoverage(t *testing.T) {
+	coverage := scenio.NewCoverageCollector()
+	ScenariosTest(t).
+		Folder("scenarios-self-test/coverage").
+		Coverage(coverage).
+		EchoVM().
+		Run().
+		CheckNoError()
+
+	report, err := coverage.Report(filepath.Join(getTestRoot(), "scenarios-self-test/coverage"))
+	require.Nil(t, err)
+	require.Equal(t, `Coverage:
+  output/adder.wasm (sc:adder): deploys: 1, upgrades: 1
+    add: calls: 3, status codes: 0 (x3)
+    getSum: never called
+`, report)
+}
+
+func TestScenariosUpgrade(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/upgrade").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosGasProfile(t *testing.T) {
+	gasProfile := scenio.NewGasProfile()
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-profile").
+		File("gas-profile.scen.json").
+		EchoVM().
+		VMGasCost(map[string]uint64{
+			"add":    100,
+			"getSum": 20,
+		}).
+		GasProfile(gasProfile).
+		Run().
+		CheckNoError()
+
+	var folded bytes.Buffer
+	err := gasProfile.Write(&folded, scenio.GasProfileFolded, getTestRoot())
+	require.Nil(t, err)
+	require.Equal(t, `scenarios-self-test/gas-profile/gas-profile.scen.json;add;sc:adder;add 100
+scenarios-self-test/gas-profile/gas-profile.scen.json;add-again;sc:adder;add 100
+scenarios-self-test/gas-profile/gas-profile.scen.json;sum;sc:adder;getSum 20
+`, folded.String())
+
+	functions := gasProfile.Functions()
+	require.Len(t, functions, 2)
+	require.Equal(t, scenio.FunctionGasProfile{Address: "sc:adder", Function: "add", Calls: 2, GasUsed: 200}, *functions[0])
+	require.Equal(t, scenio.FunctionGasProfile{Address: "sc:adder", Function: "getSum", Calls: 1, GasUsed: 20}, *functions[1])
+}
+
+func TestScenariosGasBaseline(t *testing.T) {
+	scenarioContents, err := os.ReadFile(path.Join(getTestRoot(), "scenarios-self-test/gas-baseline/gas-baseline.scen.json"))
+	require.Nil(t, err)
+	baselineDir, err := filepath.Rel(getTestRoot(), t.TempDir())
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(path.Join(getTestRoot(), baselineDir, "gas-baseline.scen.json"), scenarioContents, 0644))
+	baselinePath := path.Join(getTestRoot(), baselineDir, "gas-baseline.json")
+
+	gasBaseline, err := scenio.LoadGasBaseline(baselinePath)
+	require.Nil(t, err)
+	gasBaseline.Update = true
+	ScenariosTest(t).
+		Folder(baselineDir).
+		File("gas-baseline.scen.json").
+		GasBaseline(gasBaseline).
+		Run().
+		CheckNoError()
+	require.Nil(t, gasBaseline.Save())
+
+	recorded, err := os.ReadFile(baselinePath)
+	require.Nil(t, err)
+	require.Equal(t, `{
+    "gas-baseline.scen.json": {
+        "tx-1": 1000,
+        "tx-2": 2000
+    }
+}
+`, string(recorded))
+
+	// tx-1 is 11% above the baseline, tx-2 is 2.5% above
+	lowered := `{"gas-baseline.scen.json": {"tx-1": 900, "tx-2": 1950}}`
+	require.Nil(t, os.WriteFile(baselinePath, []byte(lowered), 0644))
+
+	gasBaseline, err = scenio.LoadGasBaseline(baselinePath)
+	require.Nil(t, err)
+	gasBaseline.MaxIncreasePercent = 10
+	ScenariosTest(t).
+		Folder(baselineDir).
+		File("gas-baseline.scen.json").
+		GasBaseline(gasBaseline).
+		Run().
+		RequireError("gas regression: gas-baseline.scen.json, tx 'tx-1': gas used 1000, baseline 900 (+11.11%), more than the 10.00% allowed")
+
+	gasBaseline.WarnOnly = true
+	ScenariosTest(t).
+		Folder(baselineDir).
+		File("gas-baseline.scen.json").
+		GasBaseline(gasBaseline).
+		Run().
+		CheckNoError()
+	regressions := gasBaseline.Regressions()
+	require.Len(t, regressions, 1)
+	require.Equal(t, "gas-baseline.scen.json, tx 'tx-1': gas used 1000, baseline 900 (+11.11%)", regressions[0].String())
+}
+
+func TestScenariosGasBaselineDuplicateTxID(t *testing.T) {
+	gasBaseline, err := scenio.LoadGasBaseline(path.Join(getTestRoot(), "scenarios-self-test/gas-baseline/gas-baseline.json"))
+	require.Nil(t, err)
+	gasBaseline.Update = true
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-baseline").
+		File("gas-baseline-duplicate.scen.json").
+		GasBaseline(gasBaseline).
+		Run().
+		RequireError("gas baseline: tx id 'tx-1' is used more than once in gas-baseline-duplicate.scen.json")
+}
+
+func TestScenariosGasScheduleFile(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-schedule").
+		File("gas-schedule-file.scen.json").
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosGasScheduleOverride(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-schedule").
+		File("gas-schedule-override.scen.json").
+		Run().
+		RequireError("not enough gas was sent in the transaction")
+}
+
+func TestScenariosGasScheduleChange(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-schedule/change").
+		File("a-gas-schedule-change.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosGasScheduleChangeRestored(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-schedule/change").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosGasScheduleMixedFolder(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-schedule/mixed").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosGasScheduleMixedFolderParallel(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/gas-schedule/mixed").
+		EchoVM().
+		Jobs(3).
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosConstants(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/constants").
+		File("constants.scen.json").
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosCapture(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/capture").
+		File("capture.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosCaptureExternalSteps(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/capture").
+		File("capture-external.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosMatchers(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/matchers").
+		File("matchers.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosMatchersMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/matchers").
+		File("matchers.err.json").
+		EchoVM().
+		Run().
+		RequireError(`result mismatch. Tx 'echo-wrong'. Want: ["mask:u32:5|any:8", "..."]. ` +
+			`Have: ["0x00000006000000006553f100 (110680464443957309696)"]`)
+}
+
+func TestScenariosLogsUnordered(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/logs").
+		File("logs-unordered.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosLogsUnorderedMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/logs").
+		File("logs-unordered.err.json").
+		EchoVM().
+		CollectAllMismatches().
+		Run().
+		RequireError(
+			`2 mismatches found:
+  no matching log for expected log. Tx 'unordered-wrong'. Expected log index: 1. Want:
+  {
+      "address": "sc:echo",
+      "endpoint": "str:third",
+      "topics": [],
+      "data": []
+  }
+  unmatched log. Tx 'unordered-wrong'. Log index: 0. Log:
+  {
+      "address": "sc:echo",
+      "endpoint": "str:second",
+      "topics": [],
+      "data": []
+  }`)
+}
+
+func TestScenariosOutputTransfers(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/output-transfers").
+		File("output-transfers.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosOutputTransfersMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/output-transfers").
+		File("output-transfers.err.json").
+		EchoVM().
+		Run().
+		RequireError(`bad output transfer DCDT. Tx 'dcdt-wrong'. Transfer index: 0. Want:
+{
+    "to": "sc:other",
+    "dcdt": [
+        {
+            "tokenIdentifier": "str:TOKEN-123",
+            "value": "11"
+        }
+    ]
+}
+Got:
+{
+    "from": "sc:echo",
+    "to": "sc:other",
+    "value": "0",
+    "dcdt": [
+        {
+            "tokenIdentifier": "str:TOKEN-123",
+            "nonce": "0",
+            "value": "10"
+        }
+    ],
+    "data": "str:DCDTTransfer@544f4b454e2d313233@0a",
+    "gasLimit": "500000",
+    "callType": "asynchronousCall"
+}`)
+}
+
+func TestScenariosStateUnchanged(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/state-unchanged").
+		File("state-unchanged.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosStateUnchangedMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/state-unchanged").
+		File("state-unchanged.err.json").
+		EchoVM().
+		CollectAllMismatches().
+		Run().
+		RequireError(
+			`2 mismatches found:
+  account balance changed. Tx 'pay'. Account: sc:echo. Want: 0. Have: 10
+  account balance changed. Tx 'pay'. Account: address:owner. Want: 900. Have: 890`)
+}
+
+func TestScenariosABI(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/abi").
+		File("abi.scen.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosABIResultMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/abi").
+		File("abi.err.json").
+		EchoVM().
+		Run().
+		RequireError(`result mismatch. Tx 'echo-wrong'. ` +
+			`Want: [{"id": 2, "name": "str:first", "owner": "address:owner", "tags": []}, "Nothing"]. ` +
+			`Have: [{"id": 1, "name": "str:first", "owner": "address:owner", "tags": []}, "Nothing"]`)
+}
+
+func TestScenariosABIRecord(t *testing.T) {
+	recordDir, err := filepath.Rel(getTestRoot(), t.TempDir())
+	require.Nil(t, err)
+	for _, file := range []string{"abi-record.unchecked.json", "echo.abi.json"} {
+		contents, err := os.ReadFile(path.Join(getTestRoot(), "scenarios-self-test/abi", file))
+		require.Nil(t, err)
+		require.Nil(t, os.WriteFile(path.Join(getTestRoot(), recordDir, file), contents, 0644))
+	}
+	recordPath := path.Join(getTestRoot(), recordDir, "abi-record.unchecked.json")
+
+	ScenariosTest(t).
+		Folder(recordDir).
+		File("abi-record.unchecked.json").
+		EchoVM().
+		Record().
+		Run().
+		CheckNoError()
+
+	// the recorded expectations hold
+	ScenariosTest(t).
+		Folder(recordDir).
+		File("abi-record.unchecked.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+
+	recorded, err := os.ReadFile(recordPath)
+	require.Nil(t, err)
+	require.Contains(t, string(recorded), `"out": [
+                    {
+                        "id": 1,
+                        "name": "str:first",
+                        "owner": "address:owner",
+                        "tags": [
+                            3
+                        ]
+                    },
+                    {
+                        "Send": {
+                            "to": "sc:echo",
+                            "amount": 2000000000000000000
+                        }
+                    },
+                    7
+                ]`)
+}
+
+func TestScenariosABIStateRecord(t *testing.T) {
+	recordDir, err := filepath.Rel(getTestRoot(), t.TempDir())
+	require.Nil(t, err)
+	for _, file := range []string{"abi-state-record.unchecked.json", "echo.abi.json"} {
+		contents, err := os.ReadFile(path.Join(getTestRoot(), "scenarios-self-test/abi", file))
+		require.Nil(t, err)
+		require.Nil(t, os.WriteFile(path.Join(getTestRoot(), recordDir, file), contents, 0644))
+	}
+	recordPath := path.Join(getTestRoot(), recordDir, "abi-state-record.unchecked.json")
+
+	ScenariosTest(t).
+		Folder(recordDir).
+		File("abi-state-record.unchecked.json").
+		EchoVM().
+		Record().
+		Run().
+		CheckNoError()
+
+	// the recorded expectations hold
+	ScenariosTest(t).
+		Folder(recordDir).
+		File("abi-state-record.unchecked.json").
+		EchoVM().
+		Run().
+		CheckNoError()
+
+	recorded, err := os.ReadFile(recordPath)
+	require.Nil(t, err)
+	require.Contains(t, string(recorded), `"topics": [
+                            "str:itemAdded",
+                            {
+                                "owner": "address:owner"
+                            }
+                        ],
+                        "data": [
+                            {
+                                "item": [
+                                    {
+                                        "id": "u32:2"
+                                    },
+                                    {
+                                        "name": "nested:str:second"
+                                    },
+                                    {
+                                        "owner": "sc:echo"
+                                    },
+                                    {
+                                        "tags": [
+                                            "u32:0"
+                                        ]
+                                    }
+                                ]
+                            }
+                        ]`)
+	require.Contains(t, string(recorded), `"str:lastItem": [
+                            {
+                                "id": "u32:1"
+                            },
+                            {
+                                "name": "nested:str:first"
+                            },
+                            {
+                                "owner": "address:owner"
+                            },
+                            {
+                                "tags": [
+                                    "u32:1",
+                                    "u16:3"
+                                ]
+                            }
+                        ],
+                        "str:other": "str:not annotated"`)
+}
+
+func TestScenariosABIStorageMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/abi").
+		File("abi-storage.err.json").
+		EchoVM().
+		Run().
+		RequireError(`Check state "check": wrong account storage for account "sc:echo":
+  for key str:lastItem: Want: "u32:2|nested:str:first|address:owner|u32:0". ` +
+			`Have: [{"id": "u32:1"}, {"name": "nested:str:first"}, {"owner": "address:owner"}, {"tags": ["u32:0"]}]`)
+}
+
+// statusReporter records the status of each scenario, by path
+type statusReporter map[string]scenio.ScenarioStatus
+
+func (sr statusReporter) ReportScenario(report *scenio.ScenarioReport) error {
+	sr[report.Path] = report.Status
+	return nil
+}
+
+func (sr statusReporter) Finish() error {
+	return nil
+}
+
+func TestScenariosFilterAll(t *testing.T) {
+	statuses := statusReporter{}
+	ScenariosTest(t).
+		Folder("scenarios-self-test/filter").
+		Reporter(statuses).
+		Run().
+		RequireError("some tests failed")
+	require.Equal(t, scenio.ScenarioFailed, statuses["scenarios-self-test/filter/beta.scen.json"])
+}
+
+func TestScenariosFilterSkipTags(t *testing.T) {
+	statuses := statusReporter{}
+	ScenariosTest(t).
+		Folder("scenarios-self-test/filter").
+		Filter(&scenio.ScenarioFilter{SkipTags: []string{"slow"}}).
+		Reporter(statuses).
+		Run().
+		CheckNoError()
+	require.Equal(t, statusReporter{
+		"scenarios-self-test/filter/alpha.scen.json": scenio.ScenarioPassed,
+		"scenarios-self-test/filter/beta.scen.json":  scenio.ScenarioSkipped,
+		"scenarios-self-test/filter/gamma.scen.json": scenio.ScenarioPassed,
+	}, statuses)
+}
+
+func TestScenariosFilterTags(t *testing.T) {
+	statuses := statusReporter{}
+	ScenariosTest(t).
+		Folder("scenarios-self-test/filter").
+		Filter(&scenio.ScenarioFilter{Tags: []string{"fast"}}).
+		Reporter(statuses).
+		Run().
+		CheckNoError()
+	require.Equal(t, statusReporter{
+		"scenarios-self-test/filter/alpha.scen.json": scenio.ScenarioPassed,
+		"scenarios-self-test/filter/beta.scen.json":  scenio.ScenarioSkipped,
+		"scenarios-self-test/filter/gamma.scen.json": scenio.ScenarioSkipped,
+	}, statuses)
+}
+
+func TestScenariosFilterName(t *testing.T) {
+	statuses := statusReporter{}
+	ScenariosTest(t).
+		Folder("scenarios-self-test/filter").
+		Filter(&scenio.ScenarioFilter{NameRegex: regexp.MustCompile("^(alpha|gamma)$")}).
+		Reporter(statuses).
+		Run().
+		CheckNoError()
+	require.Equal(t, scenio.ScenarioSkipped, statuses["scenarios-self-test/filter/beta.scen.json"])
+}
+
+func TestScenariosFilterInclude(t *testing.T) {
+	statuses := statusReporter{}
+	ScenariosTest(t).
+		Folder("scenarios-self-test").
+		Filter(&scenio.ScenarioFilter{IncludePatterns: []string{"scenarios-self-test/filter/[ag]*.scen.json"}}).
+		Reporter(statuses).
+		Run().
+		CheckNoError()
+	require.Equal(t, scenio.ScenarioPassed, statuses["scenarios-self-test/filter/alpha.scen.json"])
+	require.Equal(t, scenio.ScenarioSkipped, statuses["scenarios-self-test/filter/beta.scen.json"])
+	require.Equal(t, scenio.ScenarioSkipped, statuses["scenarios-self-test/dcdt-zero-balance-check-err.scen.json"])
+}
+
+func TestScenariosFilterBadPattern(t *testing.T) {
+	statuses := statusReporter{}
+	output := captureStdout(t, func() {
+		ScenariosTest(t).
+			Folder("scenarios-self-test/filter").
+			Filter(&scenio.ScenarioFilter{IncludePatterns: []string{"scenarios-self-test/filter/[a"}}).
+			Reporter(statuses).
+			Run().
+			RequireError("some tests failed")
+	})
+	require.Equal(t, scenio.ScenarioFailed, statuses["scenarios-self-test/filter/alpha.scen.json"])
+	require.Contains(t, output, `invalid file pattern "scenarios-self-test/filter/[a": syntax error in pattern`)
+}
+
 func TestSetAccountAddressLengthErr1(t *testing.T) {
 	ScenariosTest(t).
 		Folder("scenarios-self-test/set-check").
@@ -173,6 +952,19 @@ func TestScenariosCheckStorageErr5(t *testing.T) {
 				"  for key 0x6b65792d62 (str:key-b): Want: \"str:another-b\". Have: \"0x76616c75652d62 (str:value-b)\"")
 }
 
+func TestScenariosCheckStorageErrSorted(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		File("set-check-storage.err6.json").
+		Run().
+		RequireError(
+			"Check state \"check-1\": wrong account storage for account \"address:the-address\":\n" +
+				"  for key 0x6b65792d61 (str:key-a): Want: \"str:another-a\". Have: \"0x76616c75652d61 (str:value-a)\"\n" +
+				"  for key 0x6b65792d62 (str:key-b): Want: \"str:another-b\". Have: \"0x76616c75652d62 (str:value-b)\"\n" +
+				"  for key 0x6b65792d63 (str:key-c): Want: \"\". Have: \"0x76616c75652d63 (str:value-c)\"\n" +
+				"  for key 0x6b65792d64 (str:key-d): Want: \"str:another-d\". Have: \"0x76616c75652d64 (str:value-d)\"")
+}
+
 func TestScenariosCheckDCDTErr1(t *testing.T) {
 	ScenariosTest(t).
 		Folder("scenarios-self-test/set-check").
@@ -188,6 +980,19 @@ func TestScenariosCheckDCDTErr1(t *testing.T) {
   for token: NFT-123456, nonce: 1: Bad attributes. Want: "0x227374723a6f746865725f6174747269627574657322 ("str:other_attributes")". Have: "0x73657269616c697a65645f61747472696275746573 (str:serialized_attributes)"`)
 }
 
+func TestScenariosCheckDCDTErrSorted(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/set-check").
+		File("set-check-dcdt.err2.json").
+		Run().
+		RequireError(
+			`Check state "check-1": mismatch for account "address:the-address":
+  for token: TOKA-123456, nonce: 0: Bad balance. Want: "10". Have: "1"
+  for token: TOKB-123456, nonce: 0: Bad balance. Want: "20". Have: "2"
+  for token: TOKC-123456, nonce: 0: Bad balance. Want: "". Have: "3"
+  for token: TOKD-123456, nonce: 0: Bad balance. Want: "40". Have: "4"`)
+}
+
 func TestScenariosDcdtZeroBalance(t *testing.T) {
 	ScenariosTest(t).
 		Folder("scenarios-self-test").
@@ -207,3 +1012,102 @@ func TestScenariosDcdtNonZeroBalance(t *testing.T) {
 			`Check state "check-1": mismatch for account "address:B":
   for token: TOK-123456, nonce: 0: Bad balance. Want: "100". Have: "0"`)
 }
+
+func TestScenariosRecord(t *testing.T) {
+	fixture, err := os.ReadFile(path.Join(getTestRoot(), "scenarios-self-test/record/record.unchecked.json"))
+	require.Nil(t, err)
+	recordDir, err := filepath.Rel(getTestRoot(), t.TempDir())
+	require.Nil(t, err)
+	recordPath := path.Join(getTestRoot(), recordDir, "record.scen.json")
+	require.Nil(t, os.WriteFile(recordPath, fixture, 0644))
+
+	ScenariosTest(t).
+		Folder(recordDir).
+		File("record.scen.json").
+		Record().
+		Run().
+		CheckNoError()
+	recorded, err := os.ReadFile(recordPath)
+	require.Nil(t, err)
+
+	// the recorded expectations hold
+	ScenariosTest(t).
+		Folder(recordDir).
+		File("record.scen.json").
+		Run().
+		CheckNoError()
+
+	// recording again changes nothing
+	ScenariosTest(t).
+		Folder(recordDir).
+		File("record.scen.json").
+		Record().
+		Run().
+		CheckNoError()
+	rerecorded, err := os.ReadFile(recordPath)
+	require.Nil(t, err)
+	require.Equal(t, string(recorded), string(rerecorded))
+
+	scenario, err := scenio.ParseScenariosScenarioDefaultParser(recordPath)
+	require.Nil(t, err)
+	require.Len(t, scenario.Steps, 6)
+
+	txResult := scenario.Steps[2].(*scenmodel.TxStep).ExpectedResult
+	require.Equal(t, "7", txResult.Status.Original)
+	require.Equal(t, "0", txResult.Gas.Original)
+	require.False(t, txResult.Logs.IsStar)
+	require.Empty(t, txResult.Logs.List)
+
+	// checks that hold are kept as they are
+	checkAccounts := scenario.Steps[3].(*scenmodel.CheckStateStep).CheckAccounts.Accounts
+	require.Len(t, checkAccounts, 2)
+	require.Equal(t, "*", checkAccounts[0].Nonce.Original)
+	require.Equal(t, "150", checkAccounts[0].Balance.Original)
+	require.Equal(t, "address:B", checkAccounts[1].Address.Original)
+
+	finalCheck := scenario.Steps[5].(*scenmodel.CheckStateStep)
+	require.Equal(t, "50", finalCheck.CheckAccounts.Accounts[1].Balance.Original)
+}
+
+func TestScenariosGolden(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/golden").
+		File("golden.scen.json").
+		Run().
+		CheckNoError()
+}
+
+func TestScenariosGoldenMismatch(t *testing.T) {
+	ScenariosTest(t).
+		Folder("scenarios-self-test/golden").
+		File("golden-mismatch.err.json").
+		Run().
+		RequireError(`Check state "check-golden": mismatch for account "address:A":
+  for token: TOK-123456, nonce: 0: Bad balance. Want: "50". Have: "60"`)
+}
+
+func TestScenariosUpdateGolden(t *testing.T) {
+	scenarioContents, err := os.ReadFile(path.Join(getTestRoot(), "scenarios-self-test/golden/golden.scen.json"))
+	require.Nil(t, err)
+	expectedGolden, err := os.ReadFile(path.Join(getTestRoot(), "scenarios-self-test/golden/golden-state.json"))
+	require.Nil(t, err)
+	goldenDir, err := filepath.Rel(getTestRoot(), t.TempDir())
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(path.Join(getTestRoot(), goldenDir, "golden.scen.json"), scenarioContents, 0644))
+
+	ScenariosTest(t).
+		Folder(goldenDir).
+		File("golden.scen.json").
+		UpdateGolden().
+		Run().
+		CheckNoError()
+	generatedGolden, err := os.ReadFile(path.Join(getTestRoot(), goldenDir, "golden-state.json"))
+	require.Nil(t, err)
+	require.Equal(t, string(expectedGolden), string(generatedGolden))
+
+	ScenariosTest(t).
+		Folder(goldenDir).
+		File("golden.scen.json").
+		Run().
+		CheckNoError()
+}
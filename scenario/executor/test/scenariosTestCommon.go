@@ -31,6 +31,18 @@ type ScenariosTestBuilder struct {
 	folder       string
 	singleFile   string
 	exclusions   []string
+	jobs         int
+	reporter     scenio.Reporter
+	allMismatch  bool
+	record       bool
+	updateGolden bool
+	accountDiff  scenexec.AccountDiffStyle
+	coverage     *scenio.CoverageCollector
+	gasProfile   *scenio.GasProfile
+	vmGasCost    map[string]uint64
+	gasBaseline  *scenio.GasBaseline
+	filter       *scenio.ScenarioFilter
+	echoVM       bool
 	currentError error
 }
 
@@ -61,10 +73,100 @@ func (mtb *ScenariosTestBuilder) Exclude(path string) *ScenariosTestBuilder {
 	return mtb
 }
 
+// Jobs sets the number of scenarios to run in parallel
+func (mtb *ScenariosTestBuilder) Jobs(jobs int) *ScenariosTestBuilder {
+	mtb.jobs = jobs
+	return mtb
+}
+
+// Reporter sets a reporter that receives the outcome of each scenario
+func (mtb *ScenariosTestBuilder) Reporter(reporter scenio.Reporter) *ScenariosTestBuilder {
+	mtb.reporter = reporter
+	return mtb
+}
+
+// CollectAllMismatches makes checks report all differences instead of the first one
+func (mtb *ScenariosTestBuilder) CollectAllMismatches() *ScenariosTestBuilder {
+	mtb.allMismatch = true
+	return mtb
+}
+
+// Record makes the run fill in the expectations and rewrite the scenario files
+func (mtb *ScenariosTestBuilder) Record() *ScenariosTestBuilder {
+	mtb.record = true
+	return mtb
+}
+
+// UpdateGolden makes the run regenerate the golden files of checkState steps
+func (mtb *ScenariosTestBuilder) UpdateGolden() *ScenariosTestBuilder {
+	mtb.updateGolden = true
+	return mtb
+}
+
+// AccountDiff makes failed checkState steps also display a diff of the mismatching accounts
+func (mtb *ScenariosTestBuilder) AccountDiff(style scenexec.AccountDiffStyle) *ScenariosTestBuilder {
+	mtb.accountDiff = style
+	return mtb
+}
+
+// Coverage sets a collector that records the contract endpoints called
+func (mtb *ScenariosTestBuilder) Coverage(coverage *scenio.CoverageCollector) *ScenariosTestBuilder {
+	mtb.coverage = coverage
+	return mtb
+}
+
+// GasProfile forces gas tracing and aggregates the gas traces in a profile
+func (mtb *ScenariosTestBuilder) GasProfile(gasProfile *scenio.GasProfile) *ScenariosTestBuilder {
+	mtb.gasProfile = gasProfile
+	return mtb
+}
+
+// VMGasCost sets the gas used by the contract calls to each function, in the DummyVM
+func (mtb *ScenariosTestBuilder) VMGasCost(gasCost map[string]uint64) *ScenariosTestBuilder {
+	mtb.vmGasCost = gasCost
+	return mtb
+}
+
+// GasBaseline sets a baseline that records or checks the gas used by every tx step
+func (mtb *ScenariosTestBuilder) GasBaseline(gasBaseline *scenio.GasBaseline) *ScenariosTestBuilder {
+	mtb.gasBaseline = gasBaseline
+	return mtb
+}
+
+// Filter sets the scenario filter
+func (mtb *ScenariosTestBuilder) Filter(filter *scenio.ScenarioFilter) *ScenariosTestBuilder {
+	mtb.filter = filter
+	return mtb
+}
+
+// EchoVM makes the DummyVM accept calls, which return their arguments, and deploys
+func (mtb *ScenariosTestBuilder) EchoVM() *ScenariosTestBuilder {
+	mtb.echoVM = true
+	return mtb
+}
+
 // Run will start the testing process
 func (mtb *ScenariosTestBuilder) Run() *ScenariosTestBuilder {
-	vmBuilder := &DummyVMBuilder{}
+	vmBuilder := &DummyVMBuilder{GasCost: mtb.vmGasCost, Echo: mtb.echoVM}
+	options := scenio.DefaultRunScenarioOptions()
+	if mtb.jobs > 0 {
+		options.Jobs = mtb.jobs
+	}
+	options.Reporter = mtb.reporter
+	options.Filter = mtb.filter
+	options.Record = mtb.record
+	options.Coverage = mtb.coverage
+	options.ForceTraceGas = mtb.gasProfile != nil
+	executorOptions := scenexec.ExecutorOptions{
+		CollectAllMismatches: mtb.allMismatch,
+		UpdateGolden:         mtb.updateGolden,
+		AccountDiff:          mtb.accountDiff,
+		GasProfile:           mtb.gasProfile,
+		GasBaseline:          mtb.gasBaseline,
+	}
+
 	executor := scenexec.NewScenarioExecutor(vmBuilder)
+	executor.ApplyOptions(executorOptions, options)
 	defer executor.Close()
 
 	runner := scenio.NewScenarioController(
@@ -72,6 +174,11 @@ func (mtb *ScenariosTestBuilder) Run() *ScenariosTestBuilder {
 		scenio.NewDefaultFileResolver(),
 		vmBuilder.GetVMType(),
 	)
+	runner.NewExecutor = func() scenio.ScenarioRunner {
+		workerExecutor := scenexec.NewScenarioExecutor(vmBuilder)
+		workerExecutor.ApplyOptions(executorOptions, options)
+		return workerExecutor
+	}
 
 	if len(mtb.singleFile) > 0 {
 		fullPath := path.Join(getTestRoot(), mtb.folder)
@@ -79,14 +186,14 @@ func (mtb *ScenariosTestBuilder) Run() *ScenariosTestBuilder {
 
 		mtb.currentError = runner.RunSingleJSONScenario(
 			fullPath,
-			scenio.DefaultRunScenarioOptions())
+			options)
 	} else {
 		mtb.currentError = runner.RunAllJSONScenariosInDirectory(
 			getTestRoot(),
 			mtb.folder,
 			".scen.json",
 			mtb.exclusions,
-			scenio.DefaultRunScenarioOptions())
+			options)
 	}
 
 	return mtb
@@ -105,3 +212,14 @@ func (mtb *ScenariosTestBuilder) RequireError(expectedErrorMsg string) *Scenario
 	require.EqualError(mtb.t, mtb.currentError, expectedErrorMsg)
 	return mtb
 }
+
+// RequireStepTrace checks where the failed step and the external steps leading to it are located
+func (mtb *ScenariosTestBuilder) RequireStepTrace(expectedLocations ...string) *ScenariosTestBuilder {
+	chain := scenio.StepErrorChain(mtb.currentError)
+	locations := make([]string, len(chain))
+	for i, stepErr := range chain {
+		locations[i] = stepErr.Location()
+	}
+	require.Equal(mtb.t, expectedLocations, locations)
+	return mtb
+}
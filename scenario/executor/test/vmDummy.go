@@ -2,12 +2,14 @@ package executortest
 
 import (
 	"errors"
+	"math/big"
 
 	scenarioexec "github.com/kalyan3104/k-chain-scenario-go/scenario/executor"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 	worldmock "github.com/kalyan3104/k-chain-scenario-go/worldmock"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
+	vmdata "github.com/kalyan3104/k-chain-core-go/data/vm"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 )
 
@@ -16,16 +18,129 @@ var _ scenarioexec.VMBuilder = (*DummyVMBuilder)(nil)
 
 // DummyVM is a VM stand-in that can never be called.
 // Used for tests that do not require a VM.
-type DummyVM struct{}
+// In echo mode, it can be called: contract calls return their arguments,
+// and deploys create a contract with the given code, at a new address from the world mock.
+// Contract calls use the gas configured for their function, if any, which is traced while gas tracing is enabled.
+// Calls to "log" also log their arguments: the last one as data, the others as topics, the first being the identifier.
+// Calls to "logs" emit one log per argument, with the argument as identifier.
+// Calls to "send" make an async call to the first argument, with the second one as call data, forwarding the call value.
+// Upgrades replace the code and code metadata of the contract and return the upgrade arguments.
+type DummyVM struct {
+	world             *worldmock.MockWorld
+	vmType            []byte
+	echo              bool
+	gasCost           map[string]uint64
+	gasTrace          map[string]map[string][]uint64
+	gasTracingEnabled bool
+}
 
 // RunSmartContractCreate -
-func (*DummyVM) RunSmartContractCreate(input *vmcommon.ContractCreateInput) (*vmcommon.VMOutput, error) {
-	return nil, errors.New("cannot call the DummyVM")
+func (vm *DummyVM) RunSmartContractCreate(input *vmcommon.ContractCreateInput) (*vmcommon.VMOutput, error) {
+	if !vm.echo {
+		return nil, errors.New("cannot call the DummyVM")
+	}
+
+	creator := vm.world.AcctMap.GetAccount(input.CallerAddr)
+	newAddress, err := vm.world.NewAddress(input.CallerAddr, creator.Nonce, vm.vmType)
+	if err != nil {
+		return nil, err
+	}
+	output := echoOutput(&input.VMInput, newAddress)
+	output.OutputAccounts[string(newAddress)].Code = input.ContractCode
+	output.OutputAccounts[string(newAddress)].CodeMetadata = input.ContractCodeMetadata
+	output.OutputAccounts[string(newAddress)].CodeDeployerAddress = input.CallerAddr
+	return output, nil
 }
 
 // RunSmartContractCall -
-func (*DummyVM) RunSmartContractCall(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, error) {
-	return nil, errors.New("cannot call the DummyVM")
+func (vm *DummyVM) RunSmartContractCall(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, error) {
+	if !vm.echo {
+		return nil, errors.New("cannot call the DummyVM")
+	}
+	output := echoOutput(&input.VMInput, input.RecipientAddr)
+	vm.useGas(input, output)
+	if input.Function == scenarioexec.UpgradeFunctionName && len(input.Arguments) >= 2 {
+		output.ReturnData = input.Arguments[2:]
+		output.OutputAccounts[string(input.RecipientAddr)].Code = input.Arguments[0]
+		output.OutputAccounts[string(input.RecipientAddr)].CodeMetadata = input.Arguments[1]
+	}
+	if input.Function == "log" && len(input.Arguments) > 0 {
+		lastArg := len(input.Arguments) - 1
+		output.Logs = append(output.Logs, &vmcommon.LogEntry{
+			Identifier: input.Arguments[0],
+			Address:    input.RecipientAddr,
+			Topics:     input.Arguments[:lastArg],
+			Data:       input.Arguments[lastArg:],
+		})
+	}
+	if input.Function == "send" && len(input.Arguments) > 1 {
+		recipient := input.Arguments[0]
+		output.OutputAccounts[string(recipient)] = &vmcommon.OutputAccount{
+			Address:      recipient,
+			BalanceDelta: big.NewInt(0),
+			OutputTransfers: []vmcommon.OutputTransfer{
+				{
+					Index:         1,
+					Value:         big.NewInt(0).Set(input.CallValue),
+					GasLimit:      input.GasProvided / 2,
+					Data:          input.Arguments[1],
+					CallType:      vmdata.AsynchronousCall,
+					SenderAddress: input.RecipientAddr,
+				},
+			},
+		}
+	}
+	if input.Function == "logs" {
+		for _, arg := range input.Arguments {
+			output.Logs = append(output.Logs, &vmcommon.LogEntry{
+				Identifier: arg,
+				Address:    input.RecipientAddr,
+			})
+		}
+	}
+	return output, nil
+}
+
+// useGas consumes the gas configured for the function called, and traces it.
+func (vm *DummyVM) useGas(input *vmcommon.ContractCallInput, output *vmcommon.VMOutput) {
+	gasUsed, found := vm.gasCost[input.Function]
+	if !found {
+		return
+	}
+	if gasUsed > output.GasRemaining {
+		gasUsed = output.GasRemaining
+	}
+	output.GasRemaining -= gasUsed
+
+	if !vm.gasTracingEnabled {
+		return
+	}
+	contractTrace, found := vm.gasTrace[string(input.RecipientAddr)]
+	if !found {
+		contractTrace = make(map[string][]uint64)
+		vm.gasTrace[string(input.RecipientAddr)] = contractTrace
+	}
+	contractTrace[input.Function] = append(contractTrace[input.Function], gasUsed)
+}
+
+// echoOutput returns the arguments, and transfers the call value to the recipient, without consuming any gas.
+func echoOutput(input *vmcommon.VMInput, recipient []byte) *vmcommon.VMOutput {
+	outputAccounts := make(map[string]*vmcommon.OutputAccount)
+	outputAccounts[string(recipient)] = &vmcommon.OutputAccount{
+		Address:      recipient,
+		BalanceDelta: big.NewInt(0).Set(input.CallValue),
+	}
+
+	return &vmcommon.VMOutput{
+		ReturnData:      input.Arguments,
+		ReturnCode:      vmcommon.Ok,
+		GasRemaining:    input.GasProvided,
+		GasRefund:       big.NewInt(0),
+		OutputAccounts:  outputAccounts,
+		DeletedAccounts: make([][]byte, 0),
+		TouchedAccounts: make([][]byte, 0),
+		Logs:            make([]*vmcommon.LogEntry, 0),
+	}
 }
 
 // GasScheduleChange -
@@ -49,18 +164,32 @@ func (*DummyVM) Close() error {
 // Reset -
 func (*DummyVM) Reset() {}
 
-// SetGasTracing -
-func (*DummyVM) SetGasTracing(enableGasTracing bool) {}
+// SetGasTracing starts a new gas trace, when enabled.
+func (vm *DummyVM) SetGasTracing(enableGasTracing bool) {
+	vm.gasTracingEnabled = enableGasTracing
+	if enableGasTracing {
+		vm.gasTrace = make(map[string]map[string][]uint64)
+	}
+}
 
 // GetGasTrace -
-func (*DummyVM) GetGasTrace() map[string]map[string][]uint64 {
-	return make(map[string]map[string][]uint64)
+func (vm *DummyVM) GetGasTrace() map[string]map[string][]uint64 {
+	if !vm.gasTracingEnabled || vm.gasTrace == nil {
+		return make(map[string]map[string][]uint64)
+	}
+	return vm.gasTrace
 }
 
 // DummyVMBuilder is the builder for a DummyVM.
 // Also provides a minimal gas schedule for running the builtin functions.
 // Used for tests that do not require a VM.
-type DummyVMBuilder struct{}
+type DummyVMBuilder struct {
+	// GasCost is the gas used by contract calls, by function.
+	GasCost map[string]uint64
+
+	// Echo makes the VM accept calls and deploys, see DummyVM.
+	Echo bool
+}
 
 // NewMockWorld defines how the MockWorld is initialized.
 func (*DummyVMBuilder) NewMockWorld() *worldmock.MockWorld {
@@ -80,8 +209,13 @@ func (*DummyVMBuilder) GetVMType() []byte {
 }
 
 // NewVM creates the execution VM host with references to the world mock and gas schedule.
-func (*DummyVMBuilder) NewVM(world *worldmock.MockWorld, gasSchedule map[string]map[string]uint64) (scenarioexec.VMInterface, error) {
-	return &DummyVM{}, nil
+func (builder *DummyVMBuilder) NewVM(world *worldmock.MockWorld, gasSchedule map[string]map[string]uint64) (scenarioexec.VMInterface, error) {
+	return &DummyVM{
+		world:   world,
+		vmType:  builder.GetVMType(),
+		echo:    builder.Echo,
+		gasCost: builder.GasCost,
+	}, nil
 }
 
 func fillGasMapInternal(gasMap map[string]map[string]uint64, value uint64) map[string]map[string]uint64 {
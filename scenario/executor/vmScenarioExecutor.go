@@ -1,6 +1,9 @@
 package scenexec
 
 import (
+	"io"
+	"os"
+
 	"github.com/kalyan3104/k-chain-core-go/core/check"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	fr "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/fileresolver"
@@ -18,13 +21,30 @@ var TestVMType = []byte{0, 0}
 
 // ScenarioExecutor parses, interprets and executes both .test.json tests and .scen.json scenarios with VM.
 type ScenarioExecutor struct {
-	World             *worldmock.MockWorld
-	vmBuilder         VMBuilder
-	vm                VMInterface
-	checkGas          bool
-	scenarioTraceGas  []bool
-	fileResolver      fr.FileResolver
-	exprReconstructor er.ExprReconstructor
+	World                *worldmock.MockWorld
+	vmBuilder            VMBuilder
+	vm                   VMInterface
+	vmGasSchedule        worldmock.GasScheduleMap
+	gasScheduleChanged   bool
+	checkGas             bool
+	collectAllMismatches bool
+	recordMode           bool
+	updateGolden         bool
+	accountDiffStyle     AccountDiffStyle
+	coverage             *scenio.CoverageCollector
+	codePaths            map[string]string
+	gasProfile           *scenio.GasProfile
+	gasBaseline          *scenio.GasBaseline
+	scenarioPath         string
+	externalStepsDepth   int
+	constants            map[string][]byte
+	negativeConstants    map[string]bool
+	capturedVariables    []string
+	contractABIs         map[string]*scenmodel.ABI
+	scenarioTraceGas     []bool
+	fileResolver         fr.FileResolver
+	exprReconstructor    er.ExprReconstructor
+	output               io.Writer
 }
 
 var _ scenio.ScenarioRunner = (*ScenarioExecutor)(nil)
@@ -39,8 +59,10 @@ func NewScenarioExecutor(vmBuilder VMBuilder) *ScenarioExecutor {
 		vmBuilder:         vmBuilder,
 		checkGas:          true,
 		scenarioTraceGas:  make([]bool, 0),
+		codePaths:         make(map[string]string),
 		fileResolver:      nil,
 		exprReconstructor: er.ExprReconstructor{},
+		output:            os.Stdout,
 	}
 }
 
@@ -56,12 +78,17 @@ func (ae *ScenarioExecutor) InitVM(scenGasSchedule scenmodel.GasSchedule) error
 		return err
 	}
 
-	err = ae.World.InitBuiltinFunctions(gasSchedule)
+	return ae.initVMWithGasSchedule(gasSchedule)
+}
+
+func (ae *ScenarioExecutor) initVMWithGasSchedule(gasSchedule worldmock.GasScheduleMap) error {
+	err := ae.World.InitBuiltinFunctions(gasSchedule)
 	if err != nil {
 		return err
 	}
 
 	ae.vm, err = ae.vmBuilder.NewVM(ae.World, gasSchedule)
+	ae.vmGasSchedule = gasSchedule
 
 	return err
 }
@@ -83,6 +110,7 @@ func (ae *ScenarioExecutor) Reset() {
 		ae.vm.Reset()
 	}
 	ae.World.Clear()
+	ae.contractABIs = nil
 }
 
 // Close will simply close the VM
@@ -92,6 +120,89 @@ func (ae *ScenarioExecutor) Close() {
 	}
 }
 
+// SetOutput redirects what the executor prints while running scenarios, such as gas traces and world dumps.
+// By default it prints to stdout.
+func (ae *ScenarioExecutor) SetOutput(output io.Writer) {
+	ae.output = output
+}
+
+// SetCollectAllMismatches configures how checkState and tx result checks report failures.
+// By default they stop at the first mismatch.
+// If set, they evaluate every expected field and return one error listing all differences.
+func (ae *ScenarioExecutor) SetCollectAllMismatches(collectAllMismatches bool) {
+	ae.collectAllMismatches = collectAllMismatches
+}
+
+// SetRecordMode configures the executor to record actual results instead of checking them.
+// Tx results and checkState steps are then updated in place, to match the execution.
+// A checkState step is also added at the end of scenarios that change the state after their last check.
+func (ae *ScenarioExecutor) SetRecordMode(recordMode bool) {
+	ae.recordMode = recordMode
+}
+
+// SetUpdateGolden configures the executor to regenerate the golden files of checkState steps,
+// from the state of the world, instead of checking against them.
+// Golden files are also regenerated in record mode.
+func (ae *ScenarioExecutor) SetUpdateGolden(updateGolden bool) {
+	ae.updateGolden = updateGolden
+}
+
+// SetAccountDiffStyle configures failed checkState steps to also display
+// a diff between the expected and the actual JSON of each mismatching account.
+func (ae *ScenarioExecutor) SetAccountDiffStyle(accountDiffStyle AccountDiffStyle) {
+	ae.accountDiffStyle = accountDiffStyle
+}
+
+// SetCoverage configures the executor to record the contract deploys, upgrades and endpoint calls.
+// The same collector can be shared by several executors.
+func (ae *ScenarioExecutor) SetCoverage(coverage *scenio.CoverageCollector) {
+	ae.coverage = coverage
+}
+
+// SetGasProfile configures the executor to aggregate the gas traces in a profile,
+// instead of printing them. The same profile can be shared by several executors.
+func (ae *ScenarioExecutor) SetGasProfile(gasProfile *scenio.GasProfile) {
+	ae.gasProfile = gasProfile
+}
+
+// SetGasBaseline configures the executor to record or check the gas used by every tx step in a baseline.
+// The same baseline can be shared by several executors.
+func (ae *ScenarioExecutor) SetGasBaseline(gasBaseline *scenio.GasBaseline) {
+	ae.gasBaseline = gasBaseline
+}
+
+// ExecutorOptions are the settings of an executor that do not come from the scenario run options.
+type ExecutorOptions struct {
+	// CollectAllMismatches makes checks report all differences, instead of stopping at the first one.
+	CollectAllMismatches bool
+
+	// UpdateGolden makes checkState steps regenerate their golden files, instead of checking against them.
+	UpdateGolden bool
+
+	// AccountDiff makes failed checkState steps also display a diff of the mismatching accounts.
+	AccountDiff AccountDiffStyle
+
+	// GasProfile, if set, aggregates the gas traces of all scenarios, instead of printing them after each step.
+	GasProfile *scenio.GasProfile
+
+	// GasBaseline, if set, records or checks the gas used by every tx step.
+	GasBaseline *scenio.GasBaseline
+}
+
+// ApplyOptions configures the executor, so that all the executors of a run, including parallel workers,
+// are set up the same way. Record mode and coverage come from the scenario run options, if any.
+func (ae *ScenarioExecutor) ApplyOptions(options ExecutorOptions, runOptions *scenio.RunScenarioOptions) {
+	ae.SetCollectAllMismatches(options.CollectAllMismatches)
+	ae.SetUpdateGolden(options.UpdateGolden)
+	ae.SetAccountDiffStyle(options.AccountDiff)
+	ae.SetGasProfile(options.GasProfile)
+	ae.SetGasBaseline(options.GasBaseline)
+	if runOptions != nil {
+		ae.SetRecordMode(runOptions.Record)
+		ae.SetCoverage(runOptions.Coverage)
+	}
+}
+
 // PeekTraceGas returns the last position from the scenarioTraceGas, if existing
 func (ae *ScenarioExecutor) PeekTraceGas() bool {
 	length := len(ae.scenarioTraceGas)
@@ -15,6 +15,8 @@ var errNoStepsProvided = errors.New("no steps were provided")
 
 var errScAccountMustHaveOwner = errors.New("scAccount must have owner")
 
+var errDeferredStep = errors.New("steps that reference captured variables cannot be exported")
+
 var okStatus = big.NewInt(0)
 
 // ScAddressPrefix is the smart contract address prefix
@@ -158,6 +160,8 @@ func getAccountsAndTransactionsFromSteps(steps []scenmodel.Step) (stateAndBenchm
 			stateAndBenchmarkInfo.DeployedAccs = append(stateAndBenchmarkInfo.DeployedAccs, externalStateAndBenchmarkInfo.DeployedAccs...)
 			stateAndBenchmarkInfo.Txs = append(stateAndBenchmarkInfo.Txs, externalStateAndBenchmarkInfo.Txs...)
 			stateAndBenchmarkInfo.DeployTxs = append(stateAndBenchmarkInfo.DeployTxs, externalStateAndBenchmarkInfo.DeployTxs...)
+		case *scenmodel.DeferredStep:
+			return getInvalidScenarioWithBenchmark(), errDeferredStep
 		default:
 			steps = append(steps[:i], steps[i+1:]...)
 			i--
@@ -2,12 +2,14 @@ package scenjsontest
 
 import (
 	"encoding/hex"
+	"math/big"
 	"testing"
 
 	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
 	fr "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/fileresolver"
 	mei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
 	mer "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/reconstructor"
+	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
 	"github.com/stretchr/testify/require"
 )
 
@@ -190,7 +192,7 @@ func TestSCBech32(t *testing.T) {
 	}
 
 	result, err := ei.InterpretString("bech32:moa1yvesqqqqqqqqqqqqqqqqqqqqqqqqyvesqqqqqqqqqqqqqqqqqqqsqxnugj")
-	require.Nil(t, err) 
+	require.Nil(t, err)
 	require.Equal(t, []byte("\x23\x33\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02\x33\x30\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01"), result)
 	require.Equal(t, "bech32:moa1yvesqqqqqqqqqqqqqqqqqqqqqqqqyvesqqqqqqqqqqqqqqqqqqqsqxnugj", er.Reconstruct(result, mer.AddressHint))
 }
@@ -665,3 +667,229 @@ func TestInterpretSubTree2(t *testing.T) {
 	expected = append(expected, []byte("field2elem3b")...)
 	require.Equal(t, expected, result)
 }
+
+func TestErrorPosition(t *testing.T) {
+	ei := interpreter()
+	_, err := ei.InterpretString("u32:5|biguint:xx")
+	require.EqualError(t, err, `error at col 14 in "u32:5|biguint:xx": could not parse base 10 value: xx`)
+
+	var exprErr *mei.ExprError
+	require.ErrorAs(t, err, &exprErr)
+	require.Equal(t, 14, exprErr.Col)
+
+	_, err = ei.InterpretString("nested:keccak256:str:a|u8:300")
+	require.EqualError(t, err, `error at col 26 in "nested:keccak256:str:a|u8:300": representation of 300 does not fit in 1 bytes`)
+
+	_, err = ei.InterpretString("i8:")
+	require.EqualError(t, err, `error at col 3 in "i8:": missing number`)
+}
+
+func TestPrefixScope(t *testing.T) {
+	ei := interpreter()
+
+	// keccak256 at the start applies to the entire concatenation
+	result, err := ei.InterpretString("keccak256:str:a|u8:1")
+	require.Nil(t, err)
+	expected, _ := mei.Keccak256([]byte{'a', 0x01})
+	require.Equal(t, expected, result)
+
+	// further in, it only applies to its own part
+	result, err = ei.InterpretString("u8:1|keccak256:str:a|u8:2")
+	require.Nil(t, err)
+	hashA, _ := mei.Keccak256([]byte{'a'})
+	require.Equal(t, append(append([]byte{0x01}, hashA...), 0x02), result)
+
+	// prefix arguments can look like other prefixes
+	result, err = ei.InterpretString("str:u8:1|nested:str:a")
+	require.Nil(t, err)
+	require.Equal(t, []byte{'u', '8', ':', '1', 0, 0, 0, 1, 'a'}, result)
+}
+
+func TestArithmetic(t *testing.T) {
+	ei := interpreter()
+	result, err := ei.InterpretString("1000000000000000000 * 5 - 300")
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(0).Sub(big.NewInt(5000000000000000000), big.NewInt(300)).Bytes(), result)
+
+	result, err = ei.InterpretString("2 + 3 * 4")
+	require.Nil(t, err)
+	require.Equal(t, []byte{14}, result)
+
+	result, err = ei.InterpretString("(2 + 3) * 4 % 7")
+	require.Nil(t, err)
+	require.Equal(t, []byte{6}, result)
+
+	result, err = ei.InterpretString("-7/2")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xfd}, result) // -3, truncated towards zero
+
+	result, err = ei.InterpretString("pow(10, 18) + 0x01")
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(1000000000000000001).Bytes(), result)
+
+	result, err = ei.InterpretString("5-10")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xfb}, result)
+
+	result, err = ei.InterpretString("u8:1|2*3|nested:1+1")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x06, 0x00, 0x00, 0x00, 0x01, 0x02}, result)
+
+	_, err = ei.InterpretString("1 / (2 - 2)")
+	require.EqualError(t, err, `error at col 2 in "1 / (2 - 2)": division by zero`)
+
+	_, err = ei.InterpretString("(1 + 2")
+	require.EqualError(t, err, `unexpected token at col 6 in "(1 + 2": expected ")"`)
+
+	_, err = ei.InterpretString("1 + xyz")
+	require.EqualError(t, err, `error at col 4 in "1 + xyz": could not parse base 10 value: xyz`)
+
+	_, err = ei.InterpretString("pow(10, 100000000)")
+	require.EqualError(t, err, `error at col 0 in "pow(10, 100000000)": exponent larger than 4096`)
+
+	// floats with an exponent are not arithmetic
+	result, err = ei.InterpretString("1.5e+3")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x0a, 0x00, 0x00, 0x00, 0x35, 0x00, 0x00, 0x00, 0x0b, 0xbb, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, result)
+
+	result, err = ei.InterpretString("1.5E+3")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x0a, 0x00, 0x00, 0x00, 0x35, 0x00, 0x00, 0x00, 0x0b, 0xbb, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, result)
+
+	result, err = ei.InterpretString("1.5e-3")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x0a, 0x00, 0x00, 0x00, 0x35, 0xff, 0xff, 0xff, 0xf7, 0xc4, 0x9b, 0xa5, 0xe3, 0x53, 0xf7, 0xd0, 0x00}, result)
+
+	result, err = ei.InterpretString("-1.5e+3")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x0b, 0x00, 0x00, 0x00, 0x35, 0x00, 0x00, 0x00, 0x0b, 0xbb, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, result)
+}
+
+func TestAmounts(t *testing.T) {
+	ei := interpreter()
+	result, err := ei.InterpretString("rewa:1.5")
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(1500000000000000000).Bytes(), result)
+
+	result, err = ei.InterpretString("dec18:1.5")
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(1500000000000000000).Bytes(), result)
+
+	result, err = ei.InterpretString("dec6:0.25")
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(250000).Bytes(), result)
+
+	result, err = ei.InterpretString("rewa:1 - rewa:0.000000000000000001")
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(999999999999999999).Bytes(), result)
+
+	_, err = ei.InterpretString("dec2:0.001")
+	require.EqualError(t, err, `error at col 0 in "dec2:0.001": amount 0.001 has more than 2 decimals`)
+
+	_, err = ei.InterpretString("dec1000000000:1")
+	require.EqualError(t, err, `error at col 0 in "dec1000000000:1": denomination dec1000000000: has more than 255 decimals`)
+}
+
+func TestReconstructAmount(t *testing.T) {
+	er := reconstructor()
+	require.Equal(t, "rewa:1.5", er.Reconstruct(big.NewInt(1500000000000000000).Bytes(), mer.RewaHint))
+	require.Equal(t, "rewa:0", er.Reconstruct([]byte{}, mer.RewaHint))
+	require.Equal(t, "rewa:0.000000000000000001", er.ReconstructAmount(big.NewInt(1), 18))
+	require.Equal(t, "dec6:12", er.ReconstructAmount(big.NewInt(12000000), 6))
+	require.Equal(t, "-dec6:0.25", er.ReconstructAmount(big.NewInt(-250000), 6))
+
+	// positive amounts are unsigned, like all numbers, negative ones are in two's complement
+	ei := interpreter()
+	value, err := ei.InterpretString("dec6:12")
+	require.Nil(t, err)
+	require.Equal(t, "dec6:12", er.ReconstructAmount(big.NewInt(0).SetBytes(value), 6))
+
+	value, err = ei.InterpretString("-dec6:0.25")
+	require.Nil(t, err)
+	require.Equal(t, "-dec6:0.25", er.ReconstructAmount(twos.FromBytes(value), 6))
+}
+
+func TestConstants(t *testing.T) {
+	ei := interpreter()
+	ei.Constants = map[string][]byte{
+		"OWNER":    []byte("owner___________________________"),
+		"TOKEN_ID": []byte("TOK-123456"),
+		"AMOUNT":   {0x64},
+	}
+
+	result, err := ei.InterpretString("$OWNER")
+	require.Nil(t, err)
+	require.Equal(t, []byte("owner___________________________"), result)
+
+	result, err = ei.InterpretString("nested:${TOKEN_ID}|u8:1|$AMOUNT")
+	require.Nil(t, err)
+	require.Equal(t, append(append([]byte{0, 0, 0, 10}, "TOK-123456"...), 0x01, 0x64), result)
+
+	// in arithmetic, constants are unsigned numbers, unless their value is negative
+	result, err = ei.InterpretString("$AMOUNT * 2 + ${AMOUNT}")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x2c}, result)
+
+	result, err = ei.InterpretString("-$AMOUNT")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x9c}, result)
+
+	ei.Constants["LARGE"] = []byte{0x96}
+	result, err = ei.InterpretString("$LARGE - 100")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x32}, result)
+
+	require.True(t, ei.IsNegativeNumber("-5"))
+	require.True(t, ei.IsNegativeNumber("$AMOUNT - 200"))
+	require.False(t, ei.IsNegativeNumber("$AMOUNT - 50"))
+	require.False(t, ei.IsNegativeNumber("str:-5"))
+	ei.Constants["DEBT"], err = ei.InterpretString("-5")
+	require.Nil(t, err)
+	ei.NegativeConstants = map[string]bool{"DEBT": true}
+	result, err = ei.InterpretString("$DEBT + 7")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x02}, result)
+	require.True(t, ei.IsNegativeNumber("$DEBT"))
+
+	// prefix arguments are not resolved
+	result, err = ei.InterpretString("str:$OWNER")
+	require.Nil(t, err)
+	require.Equal(t, []byte("$OWNER"), result)
+
+	_, err = ei.InterpretString("u8:1|$MISSING")
+	require.EqualError(t, err, `error at col 5 in "u8:1|$MISSING": unknown constant MISSING`)
+
+	_, err = ei.InterpretString("1 + $MISSING")
+	require.EqualError(t, err, `error at col 4 in "1 + $MISSING": unknown constant MISSING`)
+}
+
+func TestPendingVariables(t *testing.T) {
+	ei := interpreter()
+	ei.Constants = map[string][]byte{"A": {0x05}}
+	ei.Variables = map[string]bool{"NONCE": true}
+
+	result, err := ei.InterpretString("$A + 1")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x06}, result)
+	require.False(t, ei.PendingVariableReferenced)
+
+	// the value is not known yet
+	result, err = ei.InterpretString("u8:1|$NONCE * 2")
+	require.Nil(t, err)
+	require.Equal(t, []byte{}, result)
+	require.True(t, ei.PendingVariableReferenced)
+
+	// once captured, it is a constant
+	ei.PendingVariableReferenced = false
+	ei.Constants["NONCE"] = []byte{0x07}
+	result, err = ei.InterpretString("u8:1|$NONCE * 2")
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x0e}, result)
+	require.False(t, ei.PendingVariableReferenced)
+
+	name, isReference := mei.ConstantReferenceName("${NONCE}")
+	require.True(t, isReference)
+	require.Equal(t, "NONCE", name)
+	_, isReference = mei.ConstantReferenceName("$NONCE + 1")
+	require.False(t, isReference)
+}
@@ -0,0 +1,380 @@
+package scenexpressioninterpreter
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
+)
+
+// RewaDecimals is the number of decimals of the REWA base unit.
+const RewaDecimals = 18
+
+const rewaPrefix = "rewa:"
+const decPrefix = "dec"
+const powFunction = "pow"
+
+// maxPowExponent and maxAmountDecimals keep expressions from allocating unbounded memory.
+const maxPowExponent = 4096
+const maxAmountDecimals = 255
+
+// numberWithExponent matches float literals such as "1.5e-3", where the exponent sign is not an operator.
+var numberWithExponent = regexp.MustCompile(`^[+-]?[0-9][0-9_,]*(\.[0-9_,]*)?[eE][+-]?[0-9]+$`)
+
+// denominationPrefix yields the "rewa:" or "decN:" prefix the text starts with, if any.
+func denominationPrefix(text string) (string, bool) {
+	if strings.HasPrefix(text, rewaPrefix) {
+		return rewaPrefix, true
+	}
+	if !strings.HasPrefix(text, decPrefix) {
+		return "", false
+	}
+	digits := 0
+	for len(decPrefix)+digits < len(text) && text[len(decPrefix)+digits] >= '0' && text[len(decPrefix)+digits] <= '9' {
+		digits++
+	}
+	end := len(decPrefix) + digits
+	if digits == 0 || end == len(text) || text[end] != ':' {
+		return "", false
+	}
+	return text[:end+1], true
+}
+
+// isArithmeticTerm decides whether a concatenation part without prefix is an arithmetic expression.
+// Plain numbers, including signed ones such as "-5" and floats such as "1.5e-3", are not,
+// and keep being interpreted as before.
+func isArithmeticTerm(text string) bool {
+	if numberWithExponent.MatchString(text) {
+		return false
+	}
+	unsigned := strings.TrimLeft(text, "+- ")
+	if _, found := denominationPrefix(unsigned); found {
+		return true
+	}
+	if _, _, found := constantReference(unsigned); found && len(unsigned) < len(text) {
+		return true
+	}
+	return len(text) > 1 && strings.ContainsAny(text[1:], "+-*/%(")
+}
+
+// arithmeticNode is a node in the syntax tree of an arithmetic expression, evaluated as a big integer.
+type arithmeticNode interface {
+	isArithmeticNode()
+}
+
+// arithmeticExprNode is an arithmetic expression used as a value, e.g. "rewa:1.5 - 1000".
+type arithmeticExprNode struct {
+	root arithmeticNode
+}
+
+// numberNode is an integer, an amount with its denomination, or a reference to a constant.
+type numberNode struct {
+	number token
+}
+
+// negateNode is "-" or "+" applied to an operand.
+type negateNode struct {
+	operator token
+	operand  arithmeticNode
+}
+
+// binaryNode is one of "+", "-", "*", "/", "%" applied to two operands.
+type binaryNode struct {
+	operator token
+	left     arithmeticNode
+	right    arithmeticNode
+}
+
+// powNode raises a base to a non-negative exponent.
+type powNode struct {
+	function token
+	base     arithmeticNode
+	exponent arithmeticNode
+}
+
+func (*arithmeticExprNode) isExprNode() {}
+
+func (*numberNode) isArithmeticNode() {}
+func (*negateNode) isArithmeticNode() {}
+func (*binaryNode) isArithmeticNode() {}
+func (*powNode) isArithmeticNode()    {}
+
+// parseArithmetic parses an arithmetic expression, up to the next "|".
+// Precedence is the usual one: "*", "/", "%" bind stronger than "+", "-".
+func (p *parser) parseArithmetic() (exprNode, error) {
+	p.advance()
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.current.kind != endToken {
+		return nil, p.unexpectedToken(p.current, "expected an operator")
+	}
+	return &arithmeticExprNode{root: root}, nil
+}
+
+// advance moves to the next arithmetic token.
+func (p *parser) advance() {
+	p.current = p.lex.nextArithmeticToken()
+}
+
+func (p *parser) parseSum() (arithmeticNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.kind == operatorToken && (p.current.text == "+" || p.current.text == "-") {
+		operator := p.current
+		p.advance()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (arithmeticNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.kind == operatorToken && (p.current.text == "*" || p.current.text == "/" || p.current.text == "%") {
+		operator := p.current
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (arithmeticNode, error) {
+	if p.current.kind == operatorToken && (p.current.text == "+" || p.current.text == "-") {
+		operator := p.current
+		p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negateNode{operator: operator, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (arithmeticNode, error) {
+	switch p.current.kind {
+	case numberToken, amountToken, referenceToken:
+		node := &numberNode{number: p.current}
+		p.advance()
+		return node, nil
+	case openParenToken:
+		p.advance()
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(closeParenToken, `expected ")"`)
+	case functionToken:
+		function := p.current
+		p.advance()
+		err := p.expect(openParenToken, `expected "(" after `+function.text)
+		if err != nil {
+			return nil, err
+		}
+		base, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(commaToken, `expected ","`)
+		if err != nil {
+			return nil, err
+		}
+		exponent, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return &powNode{function: function, base: base, exponent: exponent}, p.expect(closeParenToken, `expected ")"`)
+	default:
+		return nil, p.unexpectedToken(p.current, "expected a number")
+	}
+}
+
+func (p *parser) expect(kind tokenKind, message string) error {
+	if p.current.kind != kind {
+		return p.unexpectedToken(p.current, message)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) unexpectedToken(tok token, message string) error {
+	return &ExprError{Expr: p.lex.input, Col: tok.col, Err: errors.New(message)}
+}
+
+// evaluateArithmetic computes the value of an arithmetic expression,
+// encoded like numbers without prefix: minimal unsigned bytes, or two's complement if negative.
+func (ei *ExprInterpreter) evaluateArithmetic(expr string, node *arithmeticExprNode) ([]byte, error) {
+	result, err := ei.evaluateBigInt(expr, node.root)
+	if err != nil {
+		return []byte{}, err
+	}
+	if result.Sign() < 0 {
+		return twos.ToBytes(result), nil
+	}
+	return result.Bytes(), nil
+}
+
+func (ei *ExprInterpreter) evaluateBigInt(expr string, node arithmeticNode) (*big.Int, error) {
+	switch n := node.(type) {
+	case *numberNode:
+		result, err := ei.interpretArithmeticNumber(n.number)
+		if err != nil {
+			return nil, &ExprError{Expr: expr, Col: n.number.col, Err: err, Evaluation: true}
+		}
+		return result, nil
+	case *negateNode:
+		operand, err := ei.evaluateBigInt(expr, n.operand)
+		if err != nil {
+			return nil, err
+		}
+		if n.operator.text == "-" {
+			operand.Neg(operand)
+		}
+		return operand, nil
+	case *binaryNode:
+		left, err := ei.evaluateBigInt(expr, n.left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ei.evaluateBigInt(expr, n.right)
+		if err != nil {
+			return nil, err
+		}
+		return applyArithmeticOperator(expr, n.operator, left, right)
+	case *powNode:
+		base, err := ei.evaluateBigInt(expr, n.base)
+		if err != nil {
+			return nil, err
+		}
+		exponent, err := ei.evaluateBigInt(expr, n.exponent)
+		if err != nil {
+			return nil, err
+		}
+		if exponent.Sign() < 0 {
+			return nil, &ExprError{Expr: expr, Col: n.function.col, Err: errors.New("negative exponent"), Evaluation: true}
+		}
+		if exponent.Cmp(big.NewInt(maxPowExponent)) > 0 {
+			return nil, &ExprError{
+				Expr:       expr,
+				Col:        n.function.col,
+				Err:        fmt.Errorf("exponent larger than %d", maxPowExponent),
+				Evaluation: true,
+			}
+		}
+		return big.NewInt(0).Exp(base, exponent, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown arithmetic node: %T", node)
+	}
+}
+
+// applyArithmeticOperator computes a binary operation. Division and remainder truncate towards zero.
+func applyArithmeticOperator(expr string, operator token, left *big.Int, right *big.Int) (*big.Int, error) {
+	switch operator.text {
+	case "+":
+		return left.Add(left, right), nil
+	case "-":
+		return left.Sub(left, right), nil
+	case "*":
+		return left.Mul(left, right), nil
+	}
+
+	if right.Sign() == 0 {
+		return nil, &ExprError{Expr: expr, Col: operator.col, Err: errors.New("division by zero"), Evaluation: true}
+	}
+	if operator.text == "/" {
+		return left.Quo(left, right), nil
+	}
+	return left.Rem(left, right), nil
+}
+
+func (ei *ExprInterpreter) interpretArithmeticNumber(number token) (*big.Int, error) {
+	switch number.kind {
+	case amountToken:
+		return interpretAmount(number.text)
+	case referenceToken:
+		name, _, _ := constantReference(number.text)
+		value, err := ei.constantValue(name)
+		if err != nil {
+			return nil, err
+		}
+		if ei.NegativeConstants[name] {
+			return twos.FromBytes(value), nil
+		}
+		return big.NewInt(0).SetBytes(value), nil
+	}
+	numberBytes, err := ei.interpretUnsignedNumber(number.text)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(0).SetBytes(numberBytes), nil
+}
+
+// IsNegativeNumber checks whether an expression is a negative number, such as "-5", "i8:-1" or "$A - 10",
+// whose value is encoded in two's complement.
+func (ei *ExprInterpreter) IsNegativeNumber(strRaw string) bool {
+	tree, err := parseExpression(strRaw)
+	if err != nil {
+		return false
+	}
+	switch n := tree.(type) {
+	case *literalNode:
+		switch n.prefix.text {
+		case "", i64Prefix, i32Prefix, i16Prefix, i8Prefix:
+			return strings.HasPrefix(n.arg.text, "-")
+		}
+	case *referenceNode:
+		return ei.NegativeConstants[n.name]
+	case *arithmeticExprNode:
+		result, err := ei.evaluateBigInt(strRaw, n.root)
+		return err == nil && result.Sign() < 0
+	}
+	return false
+}
+
+// interpretAmount scales a decimal amount such as "rewa:1.5" or "dec6:0.25" to its base unit.
+// Amounts with more decimals than the denomination has are rejected, rather than rounded.
+func interpretAmount(text string) (*big.Int, error) {
+	denomination, _ := denominationPrefix(text)
+	decimals := RewaDecimals
+	if denomination != rewaPrefix {
+		var err error
+		decimals, err = strconv.Atoi(denomination[len(decPrefix) : len(denomination)-1])
+		if err != nil {
+			return nil, err
+		}
+		if decimals > maxAmountDecimals {
+			return nil, fmt.Errorf("denomination %s has more than %d decimals", denomination, maxAmountDecimals)
+		}
+	}
+
+	amount := strings.ReplaceAll(text[len(denomination):], "_", "")
+	integerPart, fractionalPart, _ := strings.Cut(amount, ".")
+	if len(fractionalPart) > decimals {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", amount, decimals)
+	}
+	digits := integerPart + fractionalPart + strings.Repeat("0", decimals-len(fractionalPart))
+
+	result, ok := big.NewInt(0).SetString(digits, 10)
+	if !ok || len(integerPart) == 0 {
+		return nil, fmt.Errorf("could not parse amount: %s", amount)
+	}
+	return result, nil
+}
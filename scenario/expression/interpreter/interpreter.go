@@ -36,10 +36,27 @@ const bigFloatPrefix = "bigfloat:"
 const biguintPrefix = "biguint:"
 const nestedPrefix = "nested:"
 
+const constantReferencePrefix = "$"
+
 // ExprInterpreter provides context for computing scenario values.
 type ExprInterpreter struct {
 	FileResolver fr.FileResolver
 	VMType       []byte
+
+	// Constants are the values of the scenario constants, by name, which expressions reference as "$NAME" or "${NAME}".
+	Constants map[string][]byte
+
+	// NegativeConstants are the names of the constants whose value is a negative number, in two's complement.
+	// In arithmetic, these are decoded as signed numbers, all other constants as unsigned ones.
+	NegativeConstants map[string]bool
+
+	// Variables are the names of the variables captured from the outputs of steps, which are referenced like constants.
+	// Their values are only known once the scenario runs; until they are in Constants,
+	// expressions referencing them yield empty values, and set PendingVariableReferenced.
+	Variables map[string]bool
+
+	// PendingVariableReferenced is set when an expression references a variable whose value is not known yet.
+	PendingVariableReferenced bool
 }
 
 // InterpretSubTree attempts to produce a value based on a JSON subtree.
@@ -95,113 +112,167 @@ func (ei *ExprInterpreter) InterpretSubTree(obj oj.OJsonObject) ([]byte, error)
 // - "sc:..." (also an address)
 // - "file:..."
 // - "keccak256:..."
+// - "nested:...", "biguint:...", "bigfloat:..." (length-prefixed)
+// - arithmetic on big integers: "+", "-", "*", "/", "%", "pow(base, exponent)" and parentheses;
+// digits can only be grouped with "_" there, since "," separates function arguments
+// - references to scenario constants and variables, "$NAME" or "${NAME}", as a whole part or as an arithmetic operand;
+// in arithmetic, their value is decoded as a signed number if it was negative, otherwise as an unsigned one
+// - decimal amounts, scaled to the base unit: "rewa:1.5" (18 decimals), "dec6:1.5" (6 decimals)
 // - concatenation using |
+// Invalid values yield an *ExprError, with the column of the offending token.
 func (ei *ExprInterpreter) InterpretString(strRaw string) ([]byte, error) {
 	if len(strRaw) == 0 {
 		return []byte{}, nil
 	}
 
-	// file contents
-	// TODO: make this part of a proper parser
-	if strings.HasPrefix(strRaw, drtscPrefix) {
-		if ei.FileResolver == nil {
-			return []byte{}, errors.New("parser DrtscResolver not provided")
-		}
-		fileContents, err := ei.FileResolver.ResolveFileValue(strRaw[len(drtscPrefix):])
-		if err != nil {
-			return []byte{}, err
-		}
-
-		return ei.interpretDrtscJson(fileContents)
+	tree, err := parseExpression(strRaw)
+	if err != nil {
+		return []byte{}, err
 	}
 
-	// file contents
-	// TODO: make this part of a proper parser
-	if strings.HasPrefix(strRaw, filePrefix) {
-		if ei.FileResolver == nil {
-			return []byte{}, errors.New("parser FileResolver not provided")
-		}
-		fileContents, err := ei.FileResolver.ResolveFileValue(strRaw[len(filePrefix):])
-		if err != nil {
-			return []byte{}, err
-		}
-		return fileContents, nil
+	if ei.referencesPendingVariable(tree) {
+		ei.PendingVariableReferenced = true
+		return []byte{}, nil
 	}
 
-	// keccak256
-	// TODO: make this part of a proper parser
-	if strings.HasPrefix(strRaw, keccak256Prefix) {
-		arg, err := ei.InterpretString(strRaw[len(keccak256Prefix):])
-		if err != nil {
-			return []byte{}, fmt.Errorf("cannot parse keccak256 argument: %w", err)
-		}
-		hash, err := Keccak256(arg)
-		if err != nil {
-			return []byte{}, fmt.Errorf("error computing keccak256: %w", err)
-		}
-		return hash, nil
-	}
+	return ei.evaluate(strRaw, tree)
+}
 
-	// concatenate values of different formats
-	// TODO: make this part of a proper parser
-	parts := strings.Split(strRaw, "|")
-	if len(parts) > 1 {
+// evaluate computes the value of a node in the syntax tree of expr.
+func (ei *ExprInterpreter) evaluate(expr string, node exprNode) ([]byte, error) {
+	switch n := node.(type) {
+	case *concatNode:
 		concat := make([]byte, 0)
-		for _, part := range parts {
-			eval, err := ei.InterpretString(part)
+		for _, part := range n.parts {
+			eval, err := ei.evaluate(expr, part)
 			if err != nil {
 				return []byte{}, err
 			}
 			concat = append(concat, eval...)
 		}
 		return concat, nil
-	}
-
-	if strRaw == "false" {
-		return []byte{}, nil
-	}
-
-	if strRaw == "true" {
-		return []byte{0x01}, nil
-	}
-
-	// allow ascii strings, for readability
-	for _, strPrefix := range strPrefixes {
-		if strings.HasPrefix(strRaw, strPrefix) {
-			str := strRaw[len(strPrefix):]
-			return []byte(str), nil
+	case *unaryNode:
+		operand, err := ei.evaluate(expr, n.operand)
+		if err != nil {
+			return []byte{}, err
+		}
+		return ei.applyOperator(n.operator.text, operand)
+	case *arithmeticExprNode:
+		return ei.evaluateArithmetic(expr, n)
+	case *referenceNode:
+		value, err := ei.constantValue(n.name)
+		if err != nil {
+			return []byte{}, &ExprError{Expr: expr, Col: n.reference.col, Err: err, Evaluation: true}
+		}
+		return value, nil
+	case *literalNode:
+		switch n.prefix.text {
+		case drtscPrefix, filePrefix:
+			return ei.interpretFile(n.prefix.text, n.arg.text)
 		}
+		value, err := ei.interpretLiteral(n.prefix.text, n.arg.text)
+		if err != nil {
+			return []byte{}, &ExprError{Expr: expr, Col: n.arg.col, Err: err, Evaluation: true}
+		}
+		return value, nil
+	default:
+		return []byte{}, fmt.Errorf("unknown expression node: %T", node)
 	}
+}
 
-	// address
-	if strings.HasPrefix(strRaw, addrPrefix) {
-		addrArgument := strRaw[len(addrPrefix):]
-		return addressExpression(addrArgument)
+func (ei *ExprInterpreter) constantValue(name string) ([]byte, error) {
+	value, found := ei.Constants[name]
+	if !found {
+		return []byte{}, fmt.Errorf("unknown constant %s", name)
 	}
+	return append([]byte{}, value...), nil
+}
 
-	// bech32 address
-	if strings.HasPrefix(strRaw, bech32Prefix) {
-		addrArgument := strRaw[len(bech32Prefix):]
-		return bech32Decode(addrArgument)
+func (ei *ExprInterpreter) applyOperator(operator string, operand []byte) ([]byte, error) {
+	switch operator {
+	case keccak256Prefix:
+		hash, err := Keccak256(operand)
+		if err != nil {
+			return []byte{}, fmt.Errorf("error computing keccak256: %w", err)
+		}
+		return hash, nil
+	case nestedPrefix:
+		return encodeWithLength(operand), nil
+	default:
+		return []byte{}, fmt.Errorf("unknown operator: %s", operator)
 	}
+}
 
-	// smart contract address (different format)
-	if strings.HasPrefix(strRaw, scAddrPrefix) {
-		addrArgument := strRaw[len(scAddrPrefix):]
-		return ei.scExpression(addrArgument)
+func (ei *ExprInterpreter) interpretFile(prefix string, path string) ([]byte, error) {
+	if ei.FileResolver == nil {
+		if prefix == drtscPrefix {
+			return []byte{}, errors.New("parser DrtscResolver not provided")
+		}
+		return []byte{}, errors.New("parser FileResolver not provided")
 	}
-
-	// fixed width numbers
-	parsed, result, err := ei.tryInterpretFixedWidth(strRaw)
+	fileContents, err := ei.FileResolver.ResolveFileValue(path)
 	if err != nil {
-		return nil, err
+		return []byte{}, err
 	}
-	if parsed {
-		return result, nil
+	if prefix == drtscPrefix {
+		return ei.interpretDrtscJson(fileContents)
 	}
+	return fileContents, nil
+}
 
-	// general numbers, arbitrary length
-	return ei.interpretNumber(strRaw, 0)
+// interpretLiteral yields the value of a prefix argument, or of a number or keyword when the prefix is empty.
+func (ei *ExprInterpreter) interpretLiteral(prefix string, arg string) ([]byte, error) {
+	switch prefix {
+	case "":
+		switch arg {
+		case "":
+			return []byte{}, nil
+		case "false":
+			return []byte{}, nil
+		case "true":
+			return []byte{0x01}, nil
+		}
+		// general numbers, arbitrary length
+		return ei.interpretNumber(arg, 0)
+	case addrPrefix:
+		return addressExpression(arg)
+	case bech32Prefix:
+		return bech32Decode(arg)
+	case scAddrPrefix:
+		// smart contract address (different format)
+		return ei.scExpression(arg)
+	case u64Prefix:
+		return ei.interpretUnsignedNumberFixedWidth(arg, 8)
+	case u32Prefix:
+		return ei.interpretUnsignedNumberFixedWidth(arg, 4)
+	case u16Prefix:
+		return ei.interpretUnsignedNumberFixedWidth(arg, 2)
+	case u8Prefix:
+		return ei.interpretUnsignedNumberFixedWidth(arg, 1)
+	case i64Prefix:
+		return ei.interpretNumber(arg, 8)
+	case i32Prefix:
+		return ei.interpretNumber(arg, 4)
+	case i16Prefix:
+		return ei.interpretNumber(arg, 2)
+	case i8Prefix:
+		return ei.interpretNumber(arg, 1)
+	case biguintPrefix:
+		biBytes, err := ei.interpretUnsignedNumber(arg)
+		if err != nil {
+			return []byte{}, err
+		}
+		return encodeWithLength(biBytes), nil
+	case bigFloatPrefix:
+		bfBytes, err := ei.interpretFloatingPointNumber(arg)
+		if err != nil {
+			return []byte{}, err
+		}
+		return encodeWithLength(bfBytes), nil
+	default:
+		// ascii strings, for readability
+		return []byte(arg), nil
+	}
 }
 
 // GetVMType yields the configured VM type, which is used for generating SC addresses.
@@ -241,6 +312,10 @@ func (ei *ExprInterpreter) interpretFloatingPointNumber(strRaw string) ([]byte,
 
 // targetWidth = 0 means minimum length that can contain the result
 func (ei *ExprInterpreter) interpretNumber(strRaw string, targetWidth int) ([]byte, error) {
+	if len(strRaw) == 0 {
+		return []byte{}, errors.New("missing number")
+	}
+
 	if strings.Contains(strRaw, ".") {
 		bfBytes, err := ei.interpretFloatingPointNumber(strRaw[:])
 		return bfBytes, err
@@ -330,75 +405,11 @@ func (ei *ExprInterpreter) interpretUnsignedNumberFixedWidth(strRaw string, targ
 	return twos.CopyAlignRight(numberBytes, targetWidth), nil
 }
 
-func (ei *ExprInterpreter) tryInterpretFixedWidth(strRaw string) (bool, []byte, error) {
-	if strings.HasPrefix(strRaw, u64Prefix) {
-		r, err := ei.interpretUnsignedNumberFixedWidth(strRaw[len(u64Prefix):], 8)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, u32Prefix) {
-		r, err := ei.interpretUnsignedNumberFixedWidth(strRaw[len(u32Prefix):], 4)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, u16Prefix) {
-		r, err := ei.interpretUnsignedNumberFixedWidth(strRaw[len(u16Prefix):], 2)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, u8Prefix) {
-		r, err := ei.interpretUnsignedNumberFixedWidth(strRaw[len(u8Prefix):], 1)
-		return true, r, err
-	}
-
-	if strings.HasPrefix(strRaw, i64Prefix) {
-		r, err := ei.interpretNumber(strRaw[len(i64Prefix):], 8)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, i32Prefix) {
-		r, err := ei.interpretNumber(strRaw[len(i32Prefix):], 4)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, i16Prefix) {
-		r, err := ei.interpretNumber(strRaw[len(i16Prefix):], 2)
-		return true, r, err
-	}
-	if strings.HasPrefix(strRaw, i8Prefix) {
-		r, err := ei.interpretNumber(strRaw[len(i8Prefix):], 1)
-		return true, r, err
-	}
-
-	if strings.HasPrefix(strRaw, biguintPrefix) {
-		return ei.interpretExplicitBigUintNumber(strRaw)
-	}
-
-	if strings.HasPrefix(strRaw, bigFloatPrefix) {
-		return ei.interpretExplicitFloatingPointNumber(strRaw)
-	}
-
-	if strings.HasPrefix(strRaw, nestedPrefix) {
-		return ei.interpretNestedBytes(strRaw)
-	}
-
-	return false, []byte{}, nil
-}
-
-func (ei *ExprInterpreter) interpretExplicitFloatingPointNumber(strRaw string) (bool, []byte, error) {
-	bfBytes, err := ei.interpretFloatingPointNumber(strRaw[len(bigFloatPrefix):])
-	lengthBytes := big.NewInt(int64(len(bfBytes))).Bytes()
-	encodedLength := twos.CopyAlignRight(lengthBytes, 4)
-	return true, append(encodedLength, bfBytes...), err
-}
-
-func (ei *ExprInterpreter) interpretExplicitBigUintNumber(strRaw string) (bool, []byte, error) {
-	biBytes, err := ei.interpretUnsignedNumber(strRaw[len(biguintPrefix):])
-	lengthBytes := big.NewInt(int64(len(biBytes))).Bytes()
-	encodedLength := twos.CopyAlignRight(lengthBytes, 4)
-	return true, append(encodedLength, biBytes...), err
-}
-
-func (ei *ExprInterpreter) interpretNestedBytes(strRaw string) (bool, []byte, error) {
-	nestedBytes, err := ei.InterpretString(strRaw[len(nestedPrefix):])
-	lengthBytes := big.NewInt(int64(len(nestedBytes))).Bytes()
+// encodeWithLength prepends the 4 byte length of the value, as in nested encoding.
+func encodeWithLength(value []byte) []byte {
+	lengthBytes := big.NewInt(int64(len(value))).Bytes()
 	encodedLength := twos.CopyAlignRight(lengthBytes, 4)
-	return true, append(encodedLength, nestedBytes...), err
+	return append(encodedLength, value...)
 }
 
 func (ei *ExprInterpreter) interpretDrtscJson(fileContents []byte) ([]byte, error) {
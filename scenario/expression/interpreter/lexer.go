@@ -0,0 +1,212 @@
+package scenexpressioninterpreter
+
+import "strings"
+
+const concatSeparator = '|'
+
+// tokenKind classifies the tokens of a value expression.
+type tokenKind int
+
+const (
+	// prefixToken is a known prefix, such as "str:" or "u32:", which determines how what follows it is interpreted.
+	prefixToken tokenKind = iota
+
+	// textToken is the argument of a prefix, or a number or keyword when there is no prefix.
+	textToken
+
+	// pipeToken is the concatenation operator.
+	pipeToken
+
+	// endToken marks the end of the expression.
+	endToken
+
+	// numberToken is an integer in an arithmetic expression, in decimal, hex or binary.
+	numberToken
+
+	// amountToken is a decimal amount with its denomination, e.g. "rewa:1.5" or "dec6:0.25".
+	amountToken
+
+	// operatorToken is one of the arithmetic operators "+", "-", "*", "/", "%".
+	operatorToken
+
+	// functionToken is the name of a function in an arithmetic expression, e.g. "pow".
+	functionToken
+
+	// openParenToken, closeParenToken and commaToken group arithmetic expressions and function arguments.
+	openParenToken
+	closeParenToken
+	commaToken
+
+	// referenceToken is a reference to a scenario constant, "$NAME" or "${NAME}".
+	referenceToken
+)
+
+// token is a piece of a value expression, along with the column where it starts, counting from 0.
+type token struct {
+	kind tokenKind
+	text string
+	col  int
+}
+
+// lexer splits a value expression into tokens.
+// Prefix arguments can contain anything, including ":" and the prefixes themselves (e.g. "str:u8:"),
+// so the parser tells the lexer what kind of token it expects next.
+type lexer struct {
+	input string
+	pos   int
+}
+
+// nextPrefix consumes one of the given prefixes, if the input continues with it.
+func (l *lexer) nextPrefix(prefixes []string) (token, bool) {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(l.input[l.pos:], prefix) {
+			tok := token{kind: prefixToken, text: prefix, col: l.pos}
+			l.pos += len(prefix)
+			return tok, true
+		}
+	}
+	return token{}, false
+}
+
+// peekText yields the text up to the next concatenation operator, without consuming it.
+func (l *lexer) peekText() token {
+	end := strings.IndexByte(l.input[l.pos:], concatSeparator)
+	if end < 0 {
+		return token{kind: textToken, text: l.input[l.pos:], col: l.pos}
+	}
+	return token{kind: textToken, text: l.input[l.pos : l.pos+end], col: l.pos}
+}
+
+// nextText consumes the text up to the next concatenation operator,
+// or up to the end of the input, if toEnd is set.
+func (l *lexer) nextText(toEnd bool) token {
+	tok := token{kind: textToken, text: l.input[l.pos:], col: l.pos}
+	if !toEnd {
+		tok = l.peekText()
+	}
+	l.pos += len(tok.text)
+	return tok
+}
+
+// nextArithmeticToken consumes the next token of an arithmetic expression, skipping spaces.
+// The end of the concatenation part is yielded as endToken.
+func (l *lexer) nextArithmeticToken() token {
+	for l.pos < len(l.input) && l.input[l.pos] == ' ' {
+		l.pos++
+	}
+	if l.pos == len(l.input) || l.input[l.pos] == concatSeparator {
+		return token{kind: endToken, col: l.pos}
+	}
+
+	start := l.pos
+	if _, length, found := constantReference(l.input[l.pos:]); found {
+		l.pos += length
+		return token{kind: referenceToken, text: l.input[start:l.pos], col: start}
+	}
+	if denomination, found := denominationPrefix(l.input[l.pos:]); found {
+		l.pos += len(denomination)
+		l.scanWhile(isAmountChar)
+		return token{kind: amountToken, text: l.input[start:l.pos], col: start}
+	}
+
+	kind := textToken
+	switch l.input[l.pos] {
+	case '+', '-', '*', '/', '%':
+		kind = operatorToken
+	case '(':
+		kind = openParenToken
+	case ')':
+		kind = closeParenToken
+	case ',':
+		kind = commaToken
+	}
+	if kind != textToken {
+		l.pos++
+		return token{kind: kind, text: l.input[start:l.pos], col: start}
+	}
+
+	l.scanWhile(isNumberChar)
+	if l.pos == start {
+		// unexpected character, yielded on its own
+		l.pos++
+		return token{kind: textToken, text: l.input[start:l.pos], col: start}
+	}
+	text := l.input[start:l.pos]
+	if text == powFunction {
+		return token{kind: functionToken, text: text, col: start}
+	}
+	return token{kind: numberToken, text: text, col: start}
+}
+
+// constantReference yields the name of the constant referenced at the start of the text, as "$NAME" or "${NAME}",
+// and the length of the reference.
+func constantReference(text string) (name string, length int, found bool) {
+	if !strings.HasPrefix(text, constantReferencePrefix) {
+		return "", 0, false
+	}
+	if strings.HasPrefix(text, constantReferencePrefix+"{") {
+		end := strings.IndexByte(text, '}')
+		if end < 0 {
+			return "", 0, false
+		}
+		name = text[len(constantReferencePrefix)+1 : end]
+		if !IsConstantName(name) {
+			return "", 0, false
+		}
+		return name, end + 1, true
+	}
+	length = len(constantReferencePrefix)
+	for length < len(text) && isConstantNameChar(text[length], length == len(constantReferencePrefix)) {
+		length++
+	}
+	if length == len(constantReferencePrefix) {
+		return "", 0, false
+	}
+	return text[len(constantReferencePrefix):length], length, true
+}
+
+// IsConstantName returns true for valid scenario constant names: letters, digits and "_", not starting with a digit.
+func IsConstantName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isConstantNameChar(name[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isConstantNameChar(c byte, first bool) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || !first && c >= '0' && c <= '9'
+}
+
+func (l *lexer) scanWhile(accept func(c byte) bool) {
+	for l.pos < len(l.input) && accept(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+// isNumberChar accepts the characters of decimal, hex and binary numbers, including "_" to group digits.
+// "." is accepted too, so that decimals without denomination are reported as a whole.
+func isNumberChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '.'
+}
+
+func isAmountChar(c byte) bool {
+	return c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+// nextSeparator consumes the concatenation operator, or yields the end of the input.
+// Anything else is yielded as an unexpected text token.
+func (l *lexer) nextSeparator() token {
+	if l.pos == len(l.input) {
+		return token{kind: endToken, col: l.pos}
+	}
+	if l.input[l.pos] == concatSeparator {
+		l.pos++
+		return token{kind: pipeToken, text: string(concatSeparator), col: l.pos - 1}
+	}
+	return l.peekText()
+}
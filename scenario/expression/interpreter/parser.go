@@ -0,0 +1,160 @@
+package scenexpressioninterpreter
+
+import "fmt"
+
+// filePrefixes take the rest of their expression as a file path.
+var filePrefixes = []string{drtscPrefix, filePrefix}
+
+// valuePrefixes take the rest of their concatenation part as argument.
+var valuePrefixes = append(append([]string{}, strPrefixes...),
+	addrPrefix, bech32Prefix, scAddrPrefix,
+	u64Prefix, u32Prefix, u16Prefix, u8Prefix,
+	i64Prefix, i32Prefix, i16Prefix, i8Prefix,
+	biguintPrefix, bigFloatPrefix,
+)
+
+// ExprError is an error in a value expression, pointing to the offending token.
+type ExprError struct {
+	Expr string
+
+	// Col is where the offending token starts in Expr, counting from 0.
+	Col int
+
+	Err error
+
+	// Evaluation is set for errors in computing the value of a well formed expression, e.g. a division by zero,
+	// as opposed to syntax errors.
+	Evaluation bool
+}
+
+func (e *ExprError) Error() string {
+	if e.Evaluation {
+		return fmt.Sprintf("error at col %d in %q: %s", e.Col, e.Expr, e.Err.Error())
+	}
+	return fmt.Sprintf("unexpected token at col %d in %q: %s", e.Col, e.Expr, e.Err.Error())
+}
+
+// Unwrap yields the underlying error.
+func (e *ExprError) Unwrap() error {
+	return e.Err
+}
+
+// exprNode is a node in the syntax tree of a value expression.
+type exprNode interface {
+	isExprNode()
+}
+
+// literalNode is a prefixed value, e.g. "u32:5" or "str:abc", or a number or keyword without prefix.
+type literalNode struct {
+	prefix token // empty for numbers and keywords
+	arg    token
+}
+
+// unaryNode applies an operator such as "keccak256:" or "nested:" to the value of its operand.
+type unaryNode struct {
+	operator token
+	operand  exprNode
+}
+
+// referenceNode is a reference to a scenario constant, "$NAME" or "${NAME}".
+type referenceNode struct {
+	reference token
+	name      string
+}
+
+// concatNode concatenates the values of its parts, separated by "|".
+type concatNode struct {
+	parts []exprNode
+}
+
+func (*literalNode) isExprNode()   {}
+func (*unaryNode) isExprNode()     {}
+func (*referenceNode) isExprNode() {}
+func (*concatNode) isExprNode()    {}
+
+// parseExpression builds the syntax tree of a value expression.
+//
+// For backwards compatibility, "file:", "drtsc:" and "keccak256:" at the start of an expression
+// apply to all of it, including any "|", while all other prefixes only apply to their concatenation part.
+func parseExpression(expr string) (exprNode, error) {
+	p := &parser{lex: lexer{input: expr}}
+	return p.parseExpr(true)
+}
+
+type parser struct {
+	lex lexer
+
+	// current is the arithmetic token being parsed, arithmetic expressions being parsed with one token lookahead.
+	current token
+}
+
+// parseExpr parses up to the end of the input if toEnd is set, otherwise up to the next "|".
+func (p *parser) parseExpr(toEnd bool) (exprNode, error) {
+	if prefix, found := p.lex.nextPrefix(filePrefixes); found {
+		return &literalNode{prefix: prefix, arg: p.lex.nextText(toEnd)}, nil
+	}
+
+	if operator, found := p.lex.nextPrefix([]string{keccak256Prefix}); found {
+		operand, err := p.parseExpr(toEnd)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operator: operator, operand: operand}, nil
+	}
+
+	if !toEnd {
+		return p.parseTerm()
+	}
+
+	var parts []exprNode
+	for {
+		part, err := p.parseExpr(false)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+
+		separator := p.lex.nextSeparator()
+		switch separator.kind {
+		case endToken:
+			if len(parts) == 1 {
+				return parts[0], nil
+			}
+			return &concatNode{parts: parts}, nil
+		case pipeToken:
+		default:
+			return nil, &ExprError{
+				Expr: p.lex.input,
+				Col:  separator.col,
+				Err:  fmt.Errorf("expected %q or end of expression", concatSeparator),
+			}
+		}
+	}
+}
+
+// parseTerm parses a single concatenation part.
+func (p *parser) parseTerm() (exprNode, error) {
+	if prefix, found := p.lex.nextPrefix(valuePrefixes); found {
+		return &literalNode{prefix: prefix, arg: p.lex.nextText(false)}, nil
+	}
+
+	if operator, found := p.lex.nextPrefix([]string{nestedPrefix}); found {
+		operand, err := p.parseExpr(false)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operator: operator, operand: operand}, nil
+	}
+
+	text := p.lex.peekText()
+	if name, length, found := constantReference(text.text); found && length == len(text.text) {
+		p.lex.nextText(false)
+		return &referenceNode{reference: text, name: name}, nil
+	}
+
+	if isArithmeticTerm(text.text) {
+		return p.parseArithmetic()
+	}
+
+	return &literalNode{arg: p.lex.nextText(false)}, nil
+}
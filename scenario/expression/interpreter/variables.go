@@ -0,0 +1,55 @@
+package scenexpressioninterpreter
+
+// ConstantReferenceName yields the name of the constant or variable, if the entire text is a reference to one,
+// "$NAME" or "${NAME}".
+func ConstantReferenceName(text string) (string, bool) {
+	name, length, found := constantReference(text)
+	if !found || length != len(text) {
+		return "", false
+	}
+	return name, true
+}
+
+func (ei *ExprInterpreter) isPendingVariable(name string) bool {
+	if _, known := ei.Constants[name]; known {
+		return false
+	}
+	return ei.Variables[name]
+}
+
+// referencesPendingVariable checks whether an expression references a variable whose value is not known yet.
+func (ei *ExprInterpreter) referencesPendingVariable(node exprNode) bool {
+	switch n := node.(type) {
+	case *concatNode:
+		for _, part := range n.parts {
+			if ei.referencesPendingVariable(part) {
+				return true
+			}
+		}
+	case *unaryNode:
+		return ei.referencesPendingVariable(n.operand)
+	case *referenceNode:
+		return ei.isPendingVariable(n.name)
+	case *arithmeticExprNode:
+		return ei.arithmeticReferencesPendingVariable(n.root)
+	}
+	return false
+}
+
+func (ei *ExprInterpreter) arithmeticReferencesPendingVariable(node arithmeticNode) bool {
+	switch n := node.(type) {
+	case *numberNode:
+		if n.number.kind != referenceToken {
+			return false
+		}
+		name, _, _ := constantReference(n.number.text)
+		return ei.isPendingVariable(name)
+	case *negateNode:
+		return ei.arithmeticReferencesPendingVariable(n.operand)
+	case *binaryNode:
+		return ei.arithmeticReferencesPendingVariable(n.left) || ei.arithmeticReferencesPendingVariable(n.right)
+	case *powNode:
+		return ei.arithmeticReferencesPendingVariable(n.base) || ei.arithmeticReferencesPendingVariable(n.exponent)
+	}
+	return false
+}
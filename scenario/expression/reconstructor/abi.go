@@ -0,0 +1,99 @@
+package scenexpressionreconstructor
+
+import (
+	"encoding/binary"
+	"strings"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenabi "github.com/kalyan3104/k-chain-scenario-go/scenario/abi"
+)
+
+// ABIDecoder yields a decoder for the values that the ABI describes, showing addresses the way Reconstruct does.
+func (er *ExprReconstructor) ABIDecoder() *scenabi.Decoder {
+	return &scenabi.Decoder{
+		ABI: er.ABI,
+		FormatAddress: func(address []byte) string {
+			return addressPretty(address, er.Bech32Addr)
+		},
+	}
+}
+
+// ReconstructTyped shows a value of an ABI type as a typed value, on a single line, e.g. {"a": 5, "b": "str:x"}.
+// Falls back to Reconstruct without a hint if there is no ABI, or if the value cannot be decoded.
+func (er *ExprReconstructor) ReconstructTyped(value []byte, typeName string) string {
+	if er.ABI != nil {
+		decoded, err := er.ABIDecoder().DecodeTopLevel(typeName, value)
+		if err == nil {
+			return scenabi.SingleLineJSON(decoded)
+		}
+	}
+	return er.Reconstruct(value, NoHint)
+}
+
+// ReconstructTree shows a value of an ABI type as a value tree, annotated with the field names,
+// e.g. [{"a": "u32:5"}, {"b": "nested:str:x"}].
+// Yields nil if there is no ABI, or if the value cannot be decoded.
+func (er *ExprReconstructor) ReconstructTree(value []byte, typeName string) oj.OJsonObject {
+	if er.ABI == nil {
+		return nil
+	}
+	tree, err := er.ABIDecoder().DecodeTopLevelTree(typeName, value)
+	if err != nil {
+		return nil
+	}
+	return tree
+}
+
+// ReconstructStorageKey shows a storage key annotated in the ABI by its name, followed by the mapper keys,
+// e.g. "str:items.item|u32:1".
+// Yields false if there is no ABI, or if the key is not annotated.
+func (er *ExprReconstructor) ReconstructStorageKey(key []byte) (string, bool) {
+	if er.ABI == nil {
+		return "", false
+	}
+	_, name, found := scenabi.StorageValueType(er.ABI, key)
+	if !found || !canInterpretAsString(name) {
+		return "", false
+	}
+
+	parts := []string{"str:" + string(name)}
+	mapperKeys := key[len(name):]
+	switch {
+	case len(mapperKeys) == 0:
+	case strings.HasSuffix(string(name), ".item") && len(mapperKeys) == 4:
+		parts = append(parts, "u32:"+er.ReconstructFromUint64(uint64(binary.BigEndian.Uint32(mapperKeys))))
+	case len(mapperKeys) == 32 && !strings.Contains(addressPretty(mapperKeys, er.Bech32Addr), " "):
+		parts = append(parts, addressPretty(mapperKeys, er.Bech32Addr))
+	case canInterpretAsString(mapperKeys) && !strings.Contains(string(mapperKeys), "|"):
+		parts = append(parts, "str:"+string(mapperKeys))
+	default:
+		parts = append(parts, er.Reconstruct(mapperKeys, HexHint))
+	}
+	return strings.Join(parts, "|"), true
+}
+
+// ReconstructStorageValue shows a storage value as a value tree, if the ABI storage annotations give its type.
+// Yields nil otherwise.
+func (er *ExprReconstructor) ReconstructStorageValue(key []byte, value []byte) oj.OJsonObject {
+	if er.ABI == nil {
+		return nil
+	}
+	valueType, _, found := scenabi.StorageValueType(er.ABI, key)
+	if !found {
+		return nil
+	}
+	return er.ReconstructTree(value, valueType)
+}
+
+// ReconstructEvent shows the topics and data of a log as value trees, named after the inputs of the ABI event.
+// Yields false if there is no ABI, or if the log does not match any of its events.
+func (er *ExprReconstructor) ReconstructEvent(topics [][]byte, data [][]byte) ([]oj.OJsonObject, []oj.OJsonObject, bool) {
+	if er.ABI == nil {
+		return nil, nil, false
+	}
+	topicTrees, dataTrees, err := er.ABIDecoder().DecodeEventTrees(topics, data)
+	if err != nil {
+		return nil, nil, false
+	}
+	return topicTrees, dataTrees, true
+}
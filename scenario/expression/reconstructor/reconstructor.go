@@ -11,6 +11,7 @@ import (
 	"github.com/kalyan3104/k-chain-core-go/core"
 	pc "github.com/kalyan3104/k-chain-core-go/core/pubkeyConverter"
 	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 )
 
 // ExprReconstructorHint type definition
@@ -34,6 +35,9 @@ const (
 
 	// HexHint hints that value should be displayed simply as hex. Used for code metadata.
 	HexHint
+
+	// RewaHint hints that value should be a REWA amount, e.g. "rewa:1.5"
+	RewaHint
 )
 
 const maxBytesInterpretedAsNumber = 15
@@ -41,6 +45,9 @@ const maxBytesInterpretedAsNumber = 15
 // ExprReconstructor is a component that attempts to convert raw bytes to a human-readable format.
 type ExprReconstructor struct {
 	Bech32Addr bool
+
+	// ABI describes the values of a contract, so they can be shown decoded, by name and field.
+	ABI *scenmodel.ABI
 }
 
 // Reconstruct will return the string representation of the provided value
@@ -56,6 +63,8 @@ func (er *ExprReconstructor) Reconstruct(value []byte, hint ExprReconstructorHin
 		return codePretty(value)
 	case HexHint:
 		return fmt.Sprintf("0x%s", hex.EncodeToString(value))
+	case RewaHint:
+		return er.ReconstructAmount(big.NewInt(0).SetBytes(value), ei.RewaDecimals)
 	default:
 		return unknownByteArrayPretty(value)
 	}
@@ -71,6 +80,30 @@ func (er *ExprReconstructor) ReconstructFromUint64(value uint64) string {
 	return er.Reconstruct(big.NewInt(0).SetUint64(value).Bytes(), NumberHint)
 }
 
+// ReconstructAmount will return the amount in the denomination notation, e.g. "rewa:1.5" or "dec6:0.25".
+// The result is exact, trailing zero decimals are omitted.
+func (er *ExprReconstructor) ReconstructAmount(value *big.Int, decimals int) string {
+	denomination := fmt.Sprintf("dec%d:", decimals)
+	if decimals == ei.RewaDecimals {
+		denomination = "rewa:"
+	}
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	digits := big.NewInt(0).Abs(value).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integerPart := digits[:len(digits)-decimals]
+	fractionalPart := strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if len(fractionalPart) == 0 {
+		return sign + denomination + integerPart
+	}
+	return sign + denomination + integerPart + "." + fractionalPart
+}
+
 // ReconstructList will return the string of the provided values list
 func (er *ExprReconstructor) ReconstructList(values [][]byte, hint ExprReconstructorHint) string {
 	var strs []string
@@ -0,0 +1,223 @@
+package scenio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const wasmFileSuffix = ".wasm"
+const drtscFileSuffix = ".drtsc.json"
+const abiFileSuffix = ".abi.json"
+
+// EndpointCoverage counts the calls to one contract endpoint.
+type EndpointCoverage struct {
+	Calls int
+
+	// StatusCodes counts the calls by the status code they ended with.
+	StatusCodes map[uint64]int
+}
+
+// ContractCoverage gathers the deploys, upgrades and endpoint calls of one contract code file.
+// Contracts whose code does not come from a file are identified by their address instead.
+type ContractCoverage struct {
+	CodePath  string
+	Addresses []string
+	Deploys   int
+	Upgrades  int
+	Endpoints map[string]*EndpointCoverage
+}
+
+// CoverageCollector records which contract endpoints get called while running scenarios.
+// It is safe for concurrent use, so the executors of all parallel workers can share it.
+type CoverageCollector struct {
+	mutex     sync.Mutex
+	contracts map[string]*ContractCoverage
+}
+
+// NewCoverageCollector creates a new, empty CoverageCollector instance.
+func NewCoverageCollector() *CoverageCollector {
+	return &CoverageCollector{
+		contracts: make(map[string]*ContractCoverage),
+	}
+}
+
+// RecordDeploy counts a successful contract deploy.
+func (cc *CoverageCollector) RecordDeploy(codePath string, address string) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.getContract(codePath, address).Deploys++
+}
+
+// RecordUpgrade counts a successful contract upgrade. The code path is the one of the new code.
+func (cc *CoverageCollector) RecordUpgrade(codePath string, address string) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.getContract(codePath, address).Upgrades++
+}
+
+// RecordCall counts a call to a contract endpoint, from an scCall or an scQuery.
+func (cc *CoverageCollector) RecordCall(codePath string, address string, endpoint string, statusCode uint64) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	contract := cc.getContract(codePath, address)
+	endpointCoverage, found := contract.Endpoints[endpoint]
+	if !found {
+		endpointCoverage = &EndpointCoverage{
+			StatusCodes: make(map[uint64]int),
+		}
+		contract.Endpoints[endpoint] = endpointCoverage
+	}
+	endpointCoverage.Calls++
+	endpointCoverage.StatusCodes[statusCode]++
+}
+
+func (cc *CoverageCollector) getContract(codePath string, address string) *ContractCoverage {
+	key := codePath
+	if len(key) == 0 {
+		key = address
+	}
+
+	contract, found := cc.contracts[key]
+	if !found {
+		contract = &ContractCoverage{
+			CodePath:  codePath,
+			Endpoints: make(map[string]*EndpointCoverage),
+		}
+		cc.contracts[key] = contract
+	}
+
+	if len(address) > 0 {
+		index := sort.SearchStrings(contract.Addresses, address)
+		if index == len(contract.Addresses) || contract.Addresses[index] != address {
+			contract.Addresses = append(contract.Addresses, "")
+			copy(contract.Addresses[index+1:], contract.Addresses[index:])
+			contract.Addresses[index] = address
+		}
+	}
+
+	return contract
+}
+
+// Contracts yields the coverage of all contracts, sorted by code path, then by address.
+func (cc *CoverageCollector) Contracts() []*ContractCoverage {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	keys := make([]string, 0, len(cc.contracts))
+	for key := range cc.contracts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	contracts := make([]*ContractCoverage, 0, len(keys))
+	for _, key := range keys {
+		contracts = append(contracts, cc.contracts[key])
+	}
+	return contracts
+}
+
+// Report lists, for every contract, the endpoints called, how many times and with what status codes.
+// If an .abi.json file is found next to the contract code file, the endpoints never called are listed too.
+// Code paths are displayed relative to generalTestPath.
+func (cc *CoverageCollector) Report(generalTestPath string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("Coverage:\n")
+	for _, contract := range cc.Contracts() {
+		name := "<no code file>"
+		if len(contract.CodePath) > 0 {
+			name = shortenTestPath(contract.CodePath, generalTestPath)
+		}
+		sb.WriteString(fmt.Sprintf("  %s", name))
+		if len(contract.Addresses) > 0 {
+			sb.WriteString(fmt.Sprintf(" (%s)", strings.Join(contract.Addresses, ", ")))
+		}
+		sb.WriteString(fmt.Sprintf(": deploys: %d, upgrades: %d\n", contract.Deploys, contract.Upgrades))
+
+		endpointNames := make([]string, 0, len(contract.Endpoints))
+		for endpointName := range contract.Endpoints {
+			endpointNames = append(endpointNames, endpointName)
+		}
+		sort.Strings(endpointNames)
+		for _, endpointName := range endpointNames {
+			endpointCoverage := contract.Endpoints[endpointName]
+			sb.WriteString(fmt.Sprintf("    %s: calls: %d, status codes: %s\n",
+				endpointName,
+				endpointCoverage.Calls,
+				formatStatusCodes(endpointCoverage.StatusCodes)))
+		}
+
+		abiEndpoints, err := readAbiEndpoints(contract.CodePath)
+		if err != nil {
+			return "", err
+		}
+		for _, endpointName := range abiEndpoints {
+			if _, called := contract.Endpoints[endpointName]; !called {
+				sb.WriteString(fmt.Sprintf("    %s: never called\n", endpointName))
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+func formatStatusCodes(statusCodes map[uint64]int) string {
+	codes := make([]uint64, 0, len(statusCodes))
+	for code := range statusCodes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	formatted := make([]string, 0, len(codes))
+	for _, code := range codes {
+		formatted = append(formatted, fmt.Sprintf("%d (x%d)", code, statusCodes[code]))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// abiFile only holds the parts of a contract ABI needed for the coverage report.
+type abiFile struct {
+	Endpoints []struct {
+		Name string `json:"name"`
+	} `json:"endpoints"`
+}
+
+// readAbiEndpoints yields the sorted endpoint names from the ABI next to a contract code file.
+// Yields nothing if there is no such ABI.
+func readAbiEndpoints(codePath string) ([]string, error) {
+	var abiPath string
+	switch {
+	case strings.HasSuffix(codePath, wasmFileSuffix):
+		abiPath = strings.TrimSuffix(codePath, wasmFileSuffix) + abiFileSuffix
+	case strings.HasSuffix(codePath, drtscFileSuffix):
+		abiPath = strings.TrimSuffix(codePath, drtscFileSuffix) + abiFileSuffix
+	default:
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(abiPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var abi abiFile
+	err = json.Unmarshal(contents, &abi)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ABI file %s: %w", abiPath, err)
+	}
+
+	endpointNames := make([]string, 0, len(abi.Endpoints))
+	for _, endpoint := range abi.Endpoints {
+		endpointNames = append(endpointNames, endpoint.Name)
+	}
+	sort.Strings(endpointNames)
+	return endpointNames, nil
+}
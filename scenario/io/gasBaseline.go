@@ -0,0 +1,191 @@
+package scenio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// GasRegression is a tx step that used more gas than its baseline allows.
+type GasRegression struct {
+	ScenarioPath string
+	TxID         string
+	BaselineGas  uint64
+	GasUsed      uint64
+}
+
+// IncreasePercent is how much more gas the tx step used, relative to the baseline.
+func (regression *GasRegression) IncreasePercent() float64 {
+	if regression.BaselineGas == 0 {
+		return 100
+	}
+	return (float64(regression.GasUsed) - float64(regression.BaselineGas)) * 100 / float64(regression.BaselineGas)
+}
+
+func (regression *GasRegression) String() string {
+	return fmt.Sprintf("%s, tx '%s': gas used %d, baseline %d (+%.2f%%)",
+		regression.ScenarioPath,
+		regression.TxID,
+		regression.GasUsed,
+		regression.BaselineGas,
+		regression.IncreasePercent())
+}
+
+// GasBaseline holds the gas used by every tx step, by scenario and tx id, to detect gas regressions.
+// Scenario paths are stored relative to the baseline file, so the file can be committed with the scenarios.
+// It is safe for concurrent use, so the executors of all parallel workers can share it.
+type GasBaseline struct {
+	// Update makes the baseline record the gas used by the tx steps, instead of comparing against it.
+	Update bool
+
+	// MaxIncreasePercent is how much more gas than the baseline a tx step can use before it counts as a regression.
+	MaxIncreasePercent float64
+
+	// WarnOnly makes regressions get collected, instead of failing the tx steps.
+	WarnOnly bool
+
+	filePath    string
+	mutex       sync.Mutex
+	gasUsed     map[string]map[string]uint64
+	regressions []*GasRegression
+
+	// checkedTxIDs are the tx ids already seen in the current run of each scenario.
+	checkedTxIDs map[string]map[string]bool
+}
+
+// LoadGasBaseline reads a gas baseline file. A missing file yields an empty baseline.
+func LoadGasBaseline(filePath string) (*GasBaseline, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := &GasBaseline{
+		filePath:     absPath,
+		gasUsed:      make(map[string]map[string]uint64),
+		checkedTxIDs: make(map[string]map[string]bool),
+	}
+
+	contents, err := os.ReadFile(absPath)
+	if os.IsNotExist(err) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(contents, &baseline.gasUsed)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing gas baseline %s: %w", filePath, err)
+	}
+
+	return baseline, nil
+}
+
+// StartScenario needs to be called before each run of a scenario, so its tx ids can be checked for duplicates.
+func (b *GasBaseline) StartScenario(scenarioPath string) {
+	scenarioKey := b.scenarioKey(scenarioPath)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.checkedTxIDs[scenarioKey] = make(map[string]bool)
+}
+
+// CheckTxGas records the gas used by a tx step, in update mode, or compares it against the baseline.
+// Tx steps without an id, or missing from the baseline, are not compared.
+// Tx ids identify the tx steps in the baseline, so a tx id used twice in the same scenario is an error.
+// Otherwise, the returned error is the regression, if the baseline is neither in update mode, nor in warning mode.
+func (b *GasBaseline) CheckTxGas(scenarioPath string, txID string, gasUsed uint64) error {
+	if len(txID) == 0 {
+		return nil
+	}
+	scenarioKey := b.scenarioKey(scenarioPath)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	checkedTxIDs, found := b.checkedTxIDs[scenarioKey]
+	if !found {
+		checkedTxIDs = make(map[string]bool)
+		b.checkedTxIDs[scenarioKey] = checkedTxIDs
+	}
+	if checkedTxIDs[txID] {
+		return fmt.Errorf("gas baseline: tx id '%s' is used more than once in %s", txID, scenarioKey)
+	}
+	checkedTxIDs[txID] = true
+
+	if b.Update {
+		scenarioGas, found := b.gasUsed[scenarioKey]
+		if !found {
+			scenarioGas = make(map[string]uint64)
+			b.gasUsed[scenarioKey] = scenarioGas
+		}
+		scenarioGas[txID] = gasUsed
+		return nil
+	}
+
+	baselineGas, found := b.gasUsed[scenarioKey][txID]
+	if !found || gasUsed <= baselineGas {
+		return nil
+	}
+	regression := &GasRegression{
+		ScenarioPath: scenarioKey,
+		TxID:         txID,
+		BaselineGas:  baselineGas,
+		GasUsed:      gasUsed,
+	}
+	if regression.IncreasePercent() <= b.MaxIncreasePercent {
+		return nil
+	}
+
+	if b.WarnOnly {
+		b.regressions = append(b.regressions, regression)
+		return nil
+	}
+	return fmt.Errorf("gas regression: %s, more than the %.2f%% allowed",
+		regression.String(), b.MaxIncreasePercent)
+}
+
+// Regressions yields the regressions found in warning mode, sorted by scenario and tx id.
+func (b *GasBaseline) Regressions() []*GasRegression {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	regressions := make([]*GasRegression, len(b.regressions))
+	copy(regressions, b.regressions)
+	sort.SliceStable(regressions, func(i, j int) bool {
+		if regressions[i].ScenarioPath != regressions[j].ScenarioPath {
+			return regressions[i].ScenarioPath < regressions[j].ScenarioPath
+		}
+		return regressions[i].TxID < regressions[j].TxID
+	})
+	return regressions
+}
+
+// Save writes the baseline back to its file.
+func (b *GasBaseline) Save() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	contents, err := json.MarshalIndent(b.gasUsed, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(b.filePath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.filePath, append(contents, '\n'), 0644)
+}
+
+func (b *GasBaseline) scenarioKey(scenarioPath string) string {
+	relPath, err := filepath.Rel(filepath.Dir(b.filePath), scenarioPath)
+	if err != nil {
+		return filepath.ToSlash(scenarioPath)
+	}
+	return filepath.ToSlash(relPath)
+}
@@ -0,0 +1,200 @@
+package scenio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// GasProfileJSON exports the gas profile as a JSON document.
+	GasProfileJSON = "json"
+
+	// GasProfileFolded exports the gas profile in the folded stacks format, consumed by flame graph tools.
+	GasProfileFolded = "folded"
+)
+
+// FunctionGasProfile is the gas used by the calls to one function of a contract.
+type FunctionGasProfile struct {
+	Address  string `json:"address"`
+	Function string `json:"function"`
+	Calls    int    `json:"calls"`
+	GasUsed  uint64 `json:"gasUsed"`
+}
+
+// TxGasProfile is the gas used in one tx step, by contract function.
+type TxGasProfile struct {
+	TxID      string                `json:"txId"`
+	GasUsed   uint64                `json:"gasUsed"`
+	Functions []*FunctionGasProfile `json:"functions"`
+}
+
+// ScenarioGasProfile is the gas used in one scenario, by tx step.
+// The steps of external steps files are included.
+type ScenarioGasProfile struct {
+	Path    string          `json:"path"`
+	GasUsed uint64          `json:"gasUsed"`
+	Steps   []*TxGasProfile `json:"steps"`
+}
+
+// GasProfile aggregates the gas traces of the VM, per scenario, per tx step and per contract function.
+// It is safe for concurrent use, so the executors of all parallel workers can share it.
+type GasProfile struct {
+	mutex     sync.Mutex
+	scenarios map[string]*ScenarioGasProfile
+}
+
+// NewGasProfile creates a new, empty GasProfile instance.
+func NewGasProfile() *GasProfile {
+	return &GasProfile{
+		scenarios: make(map[string]*ScenarioGasProfile),
+	}
+}
+
+// IsGasProfileFormat returns true for the formats a GasProfile can be written in.
+func IsGasProfileFormat(format string) bool {
+	return format == GasProfileJSON || format == GasProfileFolded
+}
+
+// RecordTxGas adds the gas trace of a tx step to the profile.
+// The gas trace lists, by contract address and function, the gas used by each call.
+func (p *GasProfile) RecordTxGas(scenarioPath string, txID string, gasTrace map[string]map[string][]uint64) {
+	txProfile := &TxGasProfile{TxID: txID}
+	for address, functionTraces := range gasTrace {
+		for function, callsGasUsed := range functionTraces {
+			functionProfile := &FunctionGasProfile{
+				Address:  address,
+				Function: function,
+				Calls:    len(callsGasUsed),
+			}
+			for _, gasUsed := range callsGasUsed {
+				functionProfile.GasUsed += gasUsed
+			}
+			txProfile.GasUsed += functionProfile.GasUsed
+			txProfile.Functions = append(txProfile.Functions, functionProfile)
+		}
+	}
+	if len(txProfile.Functions) == 0 {
+		return
+	}
+	sortFunctionGasProfiles(txProfile.Functions)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	scenarioProfile, found := p.scenarios[scenarioPath]
+	if !found {
+		scenarioProfile = &ScenarioGasProfile{Path: scenarioPath}
+		p.scenarios[scenarioPath] = scenarioProfile
+	}
+	scenarioProfile.GasUsed += txProfile.GasUsed
+	scenarioProfile.Steps = append(scenarioProfile.Steps, txProfile)
+}
+
+// Scenarios yields the profiles of all scenarios, sorted by path.
+func (p *GasProfile) Scenarios() []*ScenarioGasProfile {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	scenarios := make([]*ScenarioGasProfile, 0, len(p.scenarios))
+	for _, scenarioProfile := range p.scenarios {
+		scenarios = append(scenarios, scenarioProfile)
+	}
+	sort.Slice(scenarios, func(i, j int) bool {
+		return scenarios[i].Path < scenarios[j].Path
+	})
+	return scenarios
+}
+
+// Functions yields the gas used by every contract function, over all scenarios.
+func (p *GasProfile) Functions() []*FunctionGasProfile {
+	totals := make(map[[2]string]*FunctionGasProfile)
+	for _, scenarioProfile := range p.Scenarios() {
+		for _, txProfile := range scenarioProfile.Steps {
+			for _, functionProfile := range txProfile.Functions {
+				key := [2]string{functionProfile.Address, functionProfile.Function}
+				total, found := totals[key]
+				if !found {
+					total = &FunctionGasProfile{
+						Address:  functionProfile.Address,
+						Function: functionProfile.Function,
+					}
+					totals[key] = total
+				}
+				total.Calls += functionProfile.Calls
+				total.GasUsed += functionProfile.GasUsed
+			}
+		}
+	}
+
+	functions := make([]*FunctionGasProfile, 0, len(totals))
+	for _, total := range totals {
+		functions = append(functions, total)
+	}
+	sortFunctionGasProfiles(functions)
+	return functions
+}
+
+// Write exports the profile in one of the formats GasProfileJSON or GasProfileFolded.
+// Scenario paths are written relative to generalTestPath.
+func (p *GasProfile) Write(w io.Writer, format string, generalTestPath string) error {
+	scenarios := p.Scenarios()
+	for i, scenarioProfile := range scenarios {
+		relativeProfile := *scenarioProfile
+		relativeProfile.Path = shortenTestPath(scenarioProfile.Path, generalTestPath)
+		scenarios[i] = &relativeProfile
+	}
+
+	switch format {
+	case GasProfileJSON:
+		document := struct {
+			Scenarios []*ScenarioGasProfile `json:"scenarios"`
+			Functions []*FunctionGasProfile `json:"functions"`
+		}{
+			Scenarios: scenarios,
+			Functions: p.Functions(),
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(document)
+	case GasProfileFolded:
+		for _, scenarioProfile := range scenarios {
+			for _, txProfile := range scenarioProfile.Steps {
+				for _, functionProfile := range txProfile.Functions {
+					_, err := fmt.Fprintf(w, "%s;%s;%s;%s %d\n",
+						foldedFrame(scenarioProfile.Path),
+						foldedFrame(txProfile.TxID),
+						foldedFrame(functionProfile.Address),
+						foldedFrame(functionProfile.Function),
+						functionProfile.GasUsed)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown gas profile format: %s", format)
+	}
+}
+
+func sortFunctionGasProfiles(functions []*FunctionGasProfile) {
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Address != functions[j].Address {
+			return functions[i].Address < functions[j].Address
+		}
+		return functions[i].Function < functions[j].Function
+	})
+}
+
+// foldedFrame makes a name usable as a frame in the folded stacks format, where ";" separates frames.
+func foldedFrame(name string) string {
+	if len(name) == 0 {
+		return "-"
+	}
+	return strings.ReplaceAll(name, ";", "_")
+}
@@ -0,0 +1,252 @@
+package scenio
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ScenarioStatus is the outcome of running a scenario.
+type ScenarioStatus string
+
+const (
+	// ScenarioPassed indicates that all steps of the scenario ran successfully.
+	ScenarioPassed ScenarioStatus = "passed"
+
+	// ScenarioFailed indicates that the scenario could not be parsed or one of its steps failed.
+	ScenarioFailed ScenarioStatus = "failed"
+
+	// ScenarioSkipped indicates that the scenario was excluded from the run.
+	ScenarioSkipped ScenarioStatus = "skipped"
+)
+
+// ScenarioReport describes the outcome of running a single scenario file.
+type ScenarioReport struct {
+	Path     string
+	Duration time.Duration
+	Status   ScenarioStatus
+
+	// FailedStepIndex is the index of the failed step, -1 if not applicable.
+	FailedStepIndex int
+	FailedStepID    string
+
+	// FailedStepLocation is the "path:line:column" of the failed step, which can be in an external steps file.
+	FailedStepLocation string
+
+	// StepTrace lists the failed step and the external steps that lead to it, one per line.
+	StepTrace string
+
+	Message string
+}
+
+// NewScenarioReport creates a report from the error returned by a scenario run.
+func NewScenarioReport(path string, duration time.Duration, err error) *ScenarioReport {
+	report := &ScenarioReport{
+		Path:            path,
+		Duration:        duration,
+		Status:          ScenarioPassed,
+		FailedStepIndex: -1,
+	}
+	if err == nil {
+		return report
+	}
+
+	report.Status = ScenarioFailed
+	report.Message = err.Error()
+	if stepErr := InnermostStepError(err); stepErr != nil {
+		report.FailedStepIndex = stepErr.StepIndex
+		report.FailedStepID = stepErr.StepID
+		report.FailedStepLocation = stepErr.Location()
+		report.StepTrace = FormatStepTrace(err)
+	}
+	return report
+}
+
+// NewSkippedScenarioReport creates a report for a scenario that was not run.
+func NewSkippedScenarioReport(path string) *ScenarioReport {
+	return &ScenarioReport{
+		Path:            path,
+		Status:          ScenarioSkipped,
+		FailedStepIndex: -1,
+	}
+}
+
+// Reporter receives the outcome of each scenario, to produce machine-readable test reports.
+type Reporter interface {
+	// ReportScenario is called once for every scenario, in file order.
+	ReportScenario(report *ScenarioReport) error
+
+	// Finish is called once, after all scenarios have been run.
+	Finish() error
+}
+
+// ReporterFormatJSON selects the JSON-lines reporter.
+const ReporterFormatJSON = "json"
+
+// ReporterFormatJUnit selects the JUnit XML reporter.
+const ReporterFormatJUnit = "junit"
+
+// NewReporter creates a reporter by format name.
+func NewReporter(format string, writer io.Writer) (Reporter, error) {
+	switch format {
+	case ReporterFormatJSON:
+		return NewJSONLinesReporter(writer), nil
+	case ReporterFormatJUnit:
+		return NewJUnitReporter(writer), nil
+	default:
+		return nil, fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// JSONLinesReporter writes one JSON object per scenario, each on its own line.
+type JSONLinesReporter struct {
+	writer io.Writer
+}
+
+var _ Reporter = (*JSONLinesReporter)(nil)
+
+// NewJSONLinesReporter creates a new JSONLinesReporter instance.
+func NewJSONLinesReporter(writer io.Writer) *JSONLinesReporter {
+	return &JSONLinesReporter{
+		writer: writer,
+	}
+}
+
+type jsonScenarioReport struct {
+	Path               string  `json:"path"`
+	Status             string  `json:"status"`
+	DurationSeconds    float64 `json:"durationSeconds"`
+	FailedStepIndex    *int    `json:"failedStepIndex,omitempty"`
+	FailedStepID       string  `json:"failedStepId,omitempty"`
+	FailedStepLocation string  `json:"failedStepLocation,omitempty"`
+	Message            string  `json:"message,omitempty"`
+}
+
+// ReportScenario writes the scenario report line.
+func (r *JSONLinesReporter) ReportScenario(report *ScenarioReport) error {
+	jsonReport := jsonScenarioReport{
+		Path:               report.Path,
+		Status:             string(report.Status),
+		DurationSeconds:    report.Duration.Seconds(),
+		FailedStepID:       report.FailedStepID,
+		FailedStepLocation: report.FailedStepLocation,
+		Message:            report.Message,
+	}
+	if report.FailedStepIndex >= 0 {
+		stepIndex := report.FailedStepIndex
+		jsonReport.FailedStepIndex = &stepIndex
+	}
+
+	line, err := json.Marshal(&jsonReport)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.writer, "%s\n", line)
+	return err
+}
+
+// Finish does nothing, all lines have already been written.
+func (r *JSONLinesReporter) Finish() error {
+	return nil
+}
+
+// JUnitReporter collects all scenario reports and writes them as a JUnit XML document when finished.
+type JUnitReporter struct {
+	writer  io.Writer
+	reports []*ScenarioReport
+}
+
+var _ Reporter = (*JUnitReporter)(nil)
+
+// NewJUnitReporter creates a new JUnitReporter instance.
+func NewJUnitReporter(writer io.Writer) *JUnitReporter {
+	return &JUnitReporter{
+		writer: writer,
+	}
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// ReportScenario saves the report, for writing at the end.
+func (r *JUnitReporter) ReportScenario(report *ScenarioReport) error {
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+// Finish writes the XML document.
+func (r *JUnitReporter) Finish() error {
+	suite := junitTestSuite{
+		Name: "scenarios",
+	}
+	totalDuration := time.Duration(0)
+	for _, report := range r.reports {
+		testCase := junitTestCase{
+			Name:      report.Path,
+			ClassName: "scenarios",
+			Time:      formatJUnitSeconds(report.Duration),
+		}
+		switch report.Status {
+		case ScenarioFailed:
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: report.Message,
+				Content: junitFailureContent(report),
+			}
+		case ScenarioSkipped:
+			suite.Skipped++
+			testCase.Skipped = &struct{}{}
+		}
+		totalDuration += report.Duration
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = formatJUnitSeconds(totalDuration)
+
+	output, err := xml.MarshalIndent(&junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.writer, "%s%s\n", xml.Header, output)
+	return err
+}
+
+func junitFailureContent(report *ScenarioReport) string {
+	if report.FailedStepIndex < 0 {
+		return report.Message
+	}
+	if len(report.FailedStepID) > 0 {
+		return fmt.Sprintf("step %d (id: %s): %s\n%s", report.FailedStepIndex, report.FailedStepID, report.Message, report.StepTrace)
+	}
+	return fmt.Sprintf("step %d: %s\n%s", report.FailedStepIndex, report.Message, report.StepTrace)
+}
+
+func formatJUnitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,161 @@
+package scenio
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	fr "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/fileresolver"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+const fileValuePrefix = "file:"
+const drtscValuePrefix = "drtsc:"
+const goldenValuePrefix = "golden:"
+
+// DependencyGraph records, for scenario and steps files, all the files they reference:
+// external steps, as well as "file:", "drtsc:" and "golden:" values.
+// All paths are kept absolute.
+type DependencyGraph struct {
+	fileResolver fr.FileResolver
+	references   map[string][]string
+}
+
+// NewDependencyGraph creates a new, empty DependencyGraph instance.
+// References are resolved using a clone of the given file resolver.
+func NewDependencyGraph(fileResolver fr.FileResolver) *DependencyGraph {
+	return &DependencyGraph{
+		fileResolver: fileResolver.Clone(),
+		references:   make(map[string][]string),
+	}
+}
+
+// Update (re)reads the references of a scenario or steps file.
+// External steps files that are not yet in the graph get added too.
+// If the file cannot be read or parsed, it stays in the graph without references, and the error is returned.
+func (g *DependencyGraph) Update(jsonFilePath string) error {
+	jsonFilePath = absolutePath(jsonFilePath)
+	g.references[jsonFilePath] = nil
+
+	contents, err := os.ReadFile(jsonFilePath)
+	if err != nil {
+		return err
+	}
+	jobj, err := oj.ParseOrderedJSON(contents)
+	if err != nil {
+		return err
+	}
+
+	g.fileResolver.SetContext(jsonFilePath)
+	var externalSteps []string
+	g.references[jsonFilePath] = g.collectReferences(jobj, &externalSteps, nil)
+
+	for _, externalStepsPath := range externalSteps {
+		if _, known := g.references[externalStepsPath]; known {
+			continue
+		}
+		// errors in external steps files show up when running the scenario, no need to stop here
+		_ = g.Update(externalStepsPath)
+	}
+
+	return nil
+}
+
+func (g *DependencyGraph) collectReferences(obj oj.OJsonObject, externalSteps *[]string, references []string) []string {
+	switch value := obj.(type) {
+	case *oj.OJsonMap:
+		if isExternalStepsMap(value) {
+			for _, kvp := range value.OrderedKV {
+				if pathOJ, isStr := kvp.Value.(*oj.OJsonString); isStr && kvp.Key == "path" {
+					externalStepsPath := absolutePath(g.fileResolver.ResolveAbsolutePath(pathOJ.Value))
+					*externalSteps = append(*externalSteps, externalStepsPath)
+					references = append(references, externalStepsPath)
+				}
+			}
+		}
+		for _, kvp := range value.OrderedKV {
+			references = g.collectReferences(kvp.Value, externalSteps, references)
+		}
+	case *oj.OJsonList:
+		for _, item := range value.AsList() {
+			references = g.collectReferences(item, externalSteps, references)
+		}
+	case *oj.OJsonString:
+		// file values can also be part of concatenations
+		for _, part := range strings.Split(value.Value, "|") {
+			for _, prefix := range []string{fileValuePrefix, drtscValuePrefix, goldenValuePrefix} {
+				if strings.HasPrefix(part, prefix) {
+					filePath := g.fileResolver.ResolveAbsolutePath(part[len(prefix):])
+					references = append(references, absolutePath(filePath))
+				}
+			}
+		}
+	}
+	return references
+}
+
+func isExternalStepsMap(stepMap *oj.OJsonMap) bool {
+	for _, kvp := range stepMap.OrderedKV {
+		if stepOJ, isStr := kvp.Value.(*oj.OJsonString); isStr && kvp.Key == "step" {
+			return stepOJ.Value == scenmodel.StepNameExternalSteps
+		}
+	}
+	return false
+}
+
+// Files yields all files in the graph, both the ones parsed and the ones referenced, sorted.
+func (g *DependencyGraph) Files() []string {
+	allFiles := make(map[string]bool)
+	for jsonFilePath, references := range g.references {
+		allFiles[jsonFilePath] = true
+		for _, reference := range references {
+			allFiles[reference] = true
+		}
+	}
+
+	result := make([]string, 0, len(allFiles))
+	for filePath := range allFiles {
+		result = append(result, filePath)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Contains checks whether a scenario or steps file has already been parsed into the graph.
+func (g *DependencyGraph) Contains(jsonFilePath string) bool {
+	_, contains := g.references[absolutePath(jsonFilePath)]
+	return contains
+}
+
+// DependsOn checks if a scenario is, or transitively references, any of the given files.
+// The changed files need to be given as absolute paths.
+func (g *DependencyGraph) DependsOn(scenarioPath string, changedFiles map[string]bool) bool {
+	visited := make(map[string]bool)
+	var visit func(filePath string) bool
+	visit = func(filePath string) bool {
+		if visited[filePath] {
+			return false
+		}
+		visited[filePath] = true
+		if changedFiles[filePath] {
+			return true
+		}
+		for _, reference := range g.references[filePath] {
+			if visit(reference) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(absolutePath(scenarioPath))
+}
+
+func absolutePath(filePath string) string {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.Clean(filePath)
+	}
+	return absPath
+}
@@ -1,18 +1,45 @@
 package scenio
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/TwiN/go-color"
 )
 
+// scenarioRunResult holds the outcome of running a single scenario file.
+type scenarioRunResult struct {
+	path     string
+	skipped  bool
+	duration time.Duration
+	err      error
+
+	// output is what the executor printed while running the scenario, when run in parallel.
+	output string
+}
+
+func (result *scenarioRunResult) toReport() *ScenarioReport {
+	if result.skipped {
+		return NewSkippedScenarioReport(result.path)
+	}
+	return NewScenarioReport(result.path, result.duration, result.err)
+}
+
 // RunAllJSONScenariosInDirectory walks directory, parses and prepares all json scenarios,
 // then calls ScenarioRunner for each of them.
+// If the directory cannot be walked, e.g. it does not exist, the error is returned and no scenario is run.
+//
+// If options.Jobs is greater than 1 and the controller has a NewExecutor factory,
+// the scenarios are run in parallel, each worker using its own executor.
+// Output is still printed in file order, including what the executors print while running each scenario.
 func (r *ScenarioController) RunAllJSONScenariosInDirectory(
 	generalTestPath string,
 	specificTestPath string,
@@ -21,33 +48,60 @@ func (r *ScenarioController) RunAllJSONScenariosInDirectory(
 	options *RunScenarioOptions) error {
 
 	mainDirPath := path.Join(generalTestPath, specificTestPath)
+	testFilePaths, err := FindScenarioFiles(mainDirPath, allowedSuffix)
+	if err != nil {
+		return err
+	}
+
+	return r.RunJSONScenarioFiles(generalTestPath, testFilePaths, excludedFilePatterns, options)
+}
+
+// RunJSONScenarioFiles runs the given scenario files, in order, and prints a summary at the end.
+// Exclusion patterns and printed paths are relative to generalTestPath.
+func (r *ScenarioController) RunJSONScenarioFiles(
+	generalTestPath string,
+	testFilePaths []string,
+	excludedFilePatterns []string,
+	options *RunScenarioOptions) error {
+
 	var nrPassed, nrFailed, nrSkipped int
+	var reportErr error
+	countResult := func(result *scenarioRunResult) {
+		switch {
+		case result.skipped:
+			nrSkipped++
+		case result.err == nil:
+			nrPassed++
+		default:
+			nrFailed++
+		}
+		if options.Reporter != nil && reportErr == nil {
+			reportErr = options.Reporter.ReportScenario(result.toReport())
+		}
+	}
 
-	err := filepath.Walk(mainDirPath, func(testFilePath string, info os.FileInfo, err error) error {
-		if strings.HasSuffix(testFilePath, allowedSuffix) {
+	if options.Jobs > 1 && r.NewExecutor != nil {
+		r.runScenariosInParallel(testFilePaths, generalTestPath, excludedFilePatterns, options, countResult)
+	} else {
+		for _, testFilePath := range testFilePaths {
 			fmt.Printf("Scenario: %s ... ", shortenTestPath(testFilePath, generalTestPath))
-			if isExcluded(excludedFilePatterns, testFilePath, generalTestPath) {
-				nrSkipped++
-				fmt.Printf("  %s\n", color.Ize(color.Yellow, "skip"))
-			} else {
-				r.Executor.Reset()
-				r.RunsNewTest = true
-				testErr := r.RunSingleJSONScenario(testFilePath, options)
-				if testErr == nil {
-					nrPassed++
-					fmt.Printf("  %s\n", color.Ize(color.Green, "ok"))
-				} else {
-					nrFailed++
-					fmt.Printf("  %s %s\n", color.Ize(color.Red, "FAIL:"), testErr.Error())
-				}
-			}
+			result := r.runScenarioFile(testFilePath, generalTestPath, excludedFilePatterns, options)
+			printScenarioResult(result)
+			countResult(result)
 		}
-		return nil
-	})
-	if err != nil {
-		return err
 	}
+
 	fmt.Printf("Done. Passed: %d. Failed: %d. Skipped: %d.\n", nrPassed, nrFailed, nrSkipped)
+	if options.Coverage != nil {
+		coverageReport, err := options.Coverage.Report(generalTestPath)
+		if err != nil {
+			return fmt.Errorf("could not write coverage report: %w", err)
+		}
+		fmt.Print(coverageReport)
+	}
+	if reportErr != nil {
+		return fmt.Errorf("could not write test report: %w", reportErr)
+	}
 	if nrFailed > 0 {
 		return errors.New("some tests failed")
 	}
@@ -55,18 +109,164 @@ func (r *ScenarioController) RunAllJSONScenariosInDirectory(
 	return nil
 }
 
-func isExcluded(excludedFilePatterns []string, testPath string, generalTestPath string) bool {
-	for _, et := range excludedFilePatterns {
-		excludedFullPath := path.Join(generalTestPath, et)
-		match, err := filepath.Match(excludedFullPath, testPath)
+// runScenariosInParallel distributes the scenario files to a pool of workers.
+// Each worker gets its own executor and parser, so no state is shared between them.
+// Results are printed and counted strictly in the order of the input file list.
+func (r *ScenarioController) runScenariosInParallel(
+	testFilePaths []string,
+	generalTestPath string,
+	excludedFilePatterns []string,
+	options *RunScenarioOptions,
+	countResult func(*scenarioRunResult)) {
+
+	nrJobs := options.Jobs
+	if nrJobs > len(testFilePaths) {
+		nrJobs = len(testFilePaths)
+	}
+
+	results := make([]*scenarioRunResult, len(testFilePaths))
+	done := make([]chan struct{}, len(testFilePaths))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	fileIndexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < nrJobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := r.newWorkerController()
+			defer closeScenarioRunner(worker.Executor)
+			outputSetter, canBufferOutput := worker.Executor.(interface{ SetOutput(io.Writer) })
+			for i := range fileIndexes {
+				var output bytes.Buffer
+				if canBufferOutput {
+					outputSetter.SetOutput(&output)
+				}
+				results[i] = worker.runScenarioFile(testFilePaths[i], generalTestPath, excludedFilePatterns, options)
+				results[i].output = output.String()
+				close(done[i])
+			}
+		}()
+	}
+
+	go func() {
+		for i := range testFilePaths {
+			fileIndexes <- i
+		}
+		close(fileIndexes)
+	}()
+
+	for i, testFilePath := range testFilePaths {
+		<-done[i]
+		fmt.Printf("Scenario: %s ... ", shortenTestPath(testFilePath, generalTestPath))
+		fmt.Print(results[i].output)
+		printScenarioResult(results[i])
+		countResult(results[i])
+	}
+
+	wg.Wait()
+}
+
+// newWorkerController creates a controller with a fresh executor and its own copy of the parser,
+// for use in a separate goroutine.
+func (r *ScenarioController) newWorkerController() *ScenarioController {
+	parser := r.Parser
+	if parser.ExprInterpreter.FileResolver != nil {
+		parser.ExprInterpreter.FileResolver = parser.ExprInterpreter.FileResolver.Clone()
+	}
+	return &ScenarioController{
+		Executor: r.NewExecutor(),
+		Parser:   parser,
+	}
+}
+
+func (r *ScenarioController) runScenarioFile(
+	testFilePath string,
+	generalTestPath string,
+	excludedFilePatterns []string,
+	options *RunScenarioOptions) *scenarioRunResult {
+
+	shortPath := shortenTestPath(testFilePath, generalTestPath)
+	excluded, err := isExcluded(excludedFilePatterns, testFilePath, generalTestPath)
+	if err == nil && !excluded {
+		var included bool
+		included, err = options.Filter.isIncludedFile(testFilePath, generalTestPath)
+		excluded = !included
+	}
+	if err != nil {
+		return &scenarioRunResult{path: shortPath, err: err}
+	}
+	if excluded {
+		return &scenarioRunResult{path: shortPath, skipped: true}
+	}
+
+	r.Executor.Reset()
+	r.RunsNewTest = true
+	startTime := time.Now()
+	skipped, err := r.runSingleJSONScenario(testFilePath, options)
+	return &scenarioRunResult{
+		path:     shortPath,
+		skipped:  skipped,
+		duration: time.Since(startTime),
+		err:      err,
+	}
+}
+
+func printScenarioResult(result *scenarioRunResult) {
+	switch {
+	case result.skipped:
+		fmt.Printf("  %s\n", color.Ize(color.Yellow, "skip"))
+	case result.err == nil:
+		fmt.Printf("  %s\n", color.Ize(color.Green, "ok"))
+	default:
+		fmt.Printf("  %s %s\n", color.Ize(color.Red, "FAIL:"), result.err.Error())
+		fmt.Print(FormatStepTrace(result.err))
+	}
+}
+
+// FindScenarioFiles walks a directory and yields all files having the given suffix.
+// Errors encountered while walking, such as a missing directory or an unreadable subdirectory, are returned,
+// instead of being reported as an empty test run.
+func FindScenarioFiles(mainDirPath string, allowedSuffix string) ([]string, error) {
+	var testFilePaths []string
+	err := filepath.Walk(mainDirPath, func(testFilePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(testFilePath, allowedSuffix) {
+			testFilePaths = append(testFilePaths, testFilePath)
+		}
+		return nil
+	})
+	return testFilePaths, err
+}
+
+func closeScenarioRunner(runner ScenarioRunner) {
+	if closer, ok := runner.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+func isExcluded(excludedFilePatterns []string, testPath string, generalTestPath string) (bool, error) {
+	return matchesAnyPattern(excludedFilePatterns, testPath, generalTestPath)
+}
+
+// matchesAnyPattern checks the path against globs relative to generalTestPath.
+// A malformed pattern yields an error wrapping filepath.ErrBadPattern.
+func matchesAnyPattern(patterns []string, testPath string, generalTestPath string) (bool, error) {
+	for _, pattern := range patterns {
+		patternFullPath := path.Join(generalTestPath, pattern)
+		match, err := filepath.Match(patternFullPath, testPath)
 		if err != nil {
-			panic(err)
+			return false, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
 		}
 		if match {
-			return true
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
 }
 
 func shortenTestPath(path string, generalTestPath string) string {
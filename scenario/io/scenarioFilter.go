@@ -0,0 +1,57 @@
+package scenio
+
+import (
+	"regexp"
+
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// ScenarioFilter selects which scenarios get run.
+// Scenarios that do not pass the filter are reported as skipped.
+type ScenarioFilter struct {
+	// IncludePatterns are globs, relative to the directory being run.
+	// If not empty, only the files matching at least one of them are run.
+	IncludePatterns []string
+
+	// NameRegex, if set, needs to match the scenario name.
+	NameRegex *regexp.Regexp
+
+	// Tags, if not empty, only allows scenarios having at least one of these tags.
+	Tags []string
+
+	// SkipTags excludes all scenarios having any of these tags.
+	SkipTags []string
+}
+
+// isIncludedFile checks the file path against the include patterns.
+func (f *ScenarioFilter) isIncludedFile(testPath string, generalTestPath string) (bool, error) {
+	if f == nil || len(f.IncludePatterns) == 0 {
+		return true, nil
+	}
+	return matchesAnyPattern(f.IncludePatterns, testPath, generalTestPath)
+}
+
+// acceptsScenario checks the name and tags of a parsed scenario.
+func (f *ScenarioFilter) acceptsScenario(scenario *scenmodel.Scenario) bool {
+	if f == nil {
+		return true
+	}
+	if f.NameRegex != nil && !f.NameRegex.MatchString(scenario.Name) {
+		return false
+	}
+	if len(f.Tags) > 0 && !hasAnyTag(scenario, f.Tags) {
+		return false
+	}
+	return !hasAnyTag(scenario, f.SkipTags)
+}
+
+func hasAnyTag(scenario *scenmodel.Scenario, tags []string) bool {
+	for _, scenarioTag := range scenario.Tags {
+		for _, tag := range tags {
+			if scenarioTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -39,7 +39,12 @@ func ParseScenariosScenario(parser scenjparse.Parser, scenFilePath string) (*sce
 	}
 
 	parser.ExprInterpreter.FileResolver.SetContext(scenFilePath)
-	return parser.ParseScenarioFile(byteValue)
+	scenario, err := parser.ParseScenarioFile(byteValue)
+	if err != nil {
+		return nil, err
+	}
+	scenario.Path = scenFilePath
+	return scenario, nil
 }
 
 // ParseScenariosScenarioDefaultParser reads and parses a Scenarios scenario from a JSON file.
@@ -1,12 +1,32 @@
 package scenio
 
 import (
+	"errors"
+
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 )
 
 // RunScenarioOptions defines the scenario options component
 type RunScenarioOptions struct {
 	ForceTraceGas bool
+
+	// Jobs is the number of scenarios to run in parallel, when running a whole directory.
+	// Values of 0 or 1 mean the scenarios are run sequentially.
+	Jobs int
+
+	// Reporter, if set, receives the outcome of every scenario run from a directory.
+	Reporter Reporter
+
+	// Filter, if set, restricts which scenarios are run.
+	Filter *ScenarioFilter
+
+	// Record causes scenario files to be rewritten after a successful run.
+	// It is meant to be used with an executor that records the actual results, instead of checking them.
+	Record bool
+
+	// Coverage, if set, gets printed after running a directory.
+	// It is meant to be shared with the executors, which record the contract endpoints called.
+	Coverage *CoverageCollector
 }
 
 func applyScenarioOptions(scenario *scenmodel.Scenario, options *RunScenarioOptions) {
@@ -19,15 +39,26 @@ func applyScenarioOptions(scenario *scenmodel.Scenario, options *RunScenarioOpti
 func DefaultRunScenarioOptions() *RunScenarioOptions {
 	return &RunScenarioOptions{
 		ForceTraceGas: false,
+		Jobs:          1,
 	}
 }
 
 // RunSingleJSONScenario parses and prepares test, then calls testCallback.
+// Scenarios rejected by the filter in the options are not run, and no error is returned.
 func (r *ScenarioController) RunSingleJSONScenario(contextPath string, options *RunScenarioOptions) error {
+	_, err := r.runSingleJSONScenario(contextPath, options)
+	return err
+}
+
+func (r *ScenarioController) runSingleJSONScenario(contextPath string, options *RunScenarioOptions) (skipped bool, err error) {
 	scenario, parseErr := ParseScenariosScenario(r.Parser, contextPath)
 
 	if parseErr != nil {
-		return parseErr
+		return false, parseErr
+	}
+
+	if !options.Filter.acceptsScenario(scenario) {
+		return true, nil
 	}
 
 	if r.RunsNewTest {
@@ -35,7 +66,19 @@ func (r *ScenarioController) RunSingleJSONScenario(contextPath string, options *
 		r.RunsNewTest = false
 	}
 
+	traceGas := scenario.TraceGas
 	applyScenarioOptions(scenario, options)
 
-	return r.Executor.RunScenario(scenario, r.Parser.ExprInterpreter.FileResolver)
+	err = r.Executor.RunScenario(scenario, r.Parser.ExprInterpreter.FileResolver)
+	var stepErr *StepError
+	if errors.As(err, &stepErr) && len(stepErr.Path) == 0 {
+		stepErr.Path = contextPath
+	}
+	if err != nil || !options.Record {
+		return false, err
+	}
+
+	// options only apply to this run, they are not recorded
+	scenario.TraceGas = traceGas
+	return false, WriteScenariosScenario(scenario, contextPath)
 }
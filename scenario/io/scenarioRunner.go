@@ -22,6 +22,10 @@ type ScenarioController struct {
 	Executor    ScenarioRunner
 	RunsNewTest bool
 	Parser      scenjparse.Parser
+
+	// NewExecutor creates additional executors, one for each worker, when running scenarios in parallel.
+	// If nil, scenarios are always run one after the other, on Executor.
+	NewExecutor func() ScenarioRunner
 }
 
 // NewScenarioController creates new ScenarioController instance.
@@ -0,0 +1,87 @@
+package scenio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// StepError is returned by the executor when a scenario step fails.
+// It keeps track of which step failed, without altering the original error message.
+type StepError struct {
+	StepIndex int
+	StepID    string
+	StepType  string
+
+	// Path is the scenario or external steps file containing the step.
+	// It is filled in by the ScenarioController, the executor does not know it.
+	Path string
+
+	// Line and Column locate the step in its file. They are 0 if the step was not parsed from JSON.
+	Line   int
+	Column int
+
+	Err error
+}
+
+// Error returns the message of the wrapped error.
+func (se *StepError) Error() string {
+	return se.Err.Error()
+}
+
+// Unwrap yields the wrapped error.
+func (se *StepError) Unwrap() error {
+	return se.Err
+}
+
+// Location formats the step position as "path:line:column", as understood by most editors.
+func (se *StepError) Location() string {
+	if se.Line == 0 {
+		return se.Path
+	}
+	return fmt.Sprintf("%s:%d:%d", se.Path, se.Line, se.Column)
+}
+
+// Describe prints the step index, type and id, for display.
+func (se *StepError) Describe() string {
+	if len(se.StepID) > 0 {
+		return fmt.Sprintf("step %d (%s %s)", se.StepIndex, se.StepType, se.StepID)
+	}
+	return fmt.Sprintf("step %d (%s)", se.StepIndex, se.StepType)
+}
+
+// StepErrorChain yields all StepErrors in an error chain, from the outermost to the innermost.
+// The outer ones are the externalSteps steps that led to the innermost, actually failed step.
+func StepErrorChain(err error) []*StepError {
+	var chain []*StepError
+	for {
+		var stepErr *StepError
+		if !errors.As(err, &stepErr) {
+			return chain
+		}
+		chain = append(chain, stepErr)
+		err = stepErr.Err
+	}
+}
+
+// InnermostStepError finds the deepest StepError in an error chain.
+// When steps fail inside external steps, the innermost one is the step that actually failed.
+// Returns nil if there is no StepError in the chain.
+func InnermostStepError(err error) *StepError {
+	chain := StepErrorChain(err)
+	if len(chain) == 0 {
+		return nil
+	}
+	return chain[len(chain)-1]
+}
+
+// FormatStepTrace describes where a step failed, one line per step, innermost first.
+// Returns an empty string if the error does not originate in a scenario step.
+func FormatStepTrace(err error) string {
+	chain := StepErrorChain(err)
+	var sb strings.Builder
+	for i := len(chain) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("    at %s: %s\n", chain[i].Location(), chain[i].Describe()))
+	}
+	return sb.String()
+}
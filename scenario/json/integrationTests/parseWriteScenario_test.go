@@ -2,12 +2,15 @@ package scenjsontest
 
 import (
 	"io"
+	"math/big"
 	"os"
 	"testing"
 
 	fr "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/fileresolver"
+	mei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
 	scenjparse "github.com/kalyan3104/k-chain-scenario-go/scenario/json/parse"
 	scenjwrite "github.com/kalyan3104/k-chain-scenario-go/scenario/json/write"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 
 	"github.com/stretchr/testify/require"
 )
@@ -15,6 +18,9 @@ import (
 // only for this test
 var vmType = []byte{'W', 'W'}
 
+// selfTestFolder holds the executor self-test scenarios, whose round trip is also checked here.
+const selfTestFolder = "../../executor/test/scenarios-self-test/"
+
 func loadExampleFile(path string) ([]byte, error) {
 	// Open our jsonFile
 	var jsonFile *os.File
@@ -53,3 +59,287 @@ func TestWriteScenario(t *testing.T) {
 
 	require.Equal(t, contents, []byte(serialized))
 }
+
+func TestWriteScenarioConstants(t *testing.T) {
+	contents, err := loadExampleFile("constants.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver().WithContext("constants.scen.json"), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+	require.Nil(t, p.ExprInterpreter.Constants)
+
+	ei := mei.ExprInterpreter{}
+	owner, _ := ei.InterpretString("address:owner")
+	amount, _ := ei.InterpretString("rewa:1.5")
+	payment, _ := ei.InterpretString("rewa:3")
+	require.Equal(t, owner, scenario.ConstantValues["OWNER"])
+	require.Equal(t, []byte("TOK-123456"), scenario.ConstantValues["TOKEN_ID"])
+	require.Equal(t, amount, scenario.ConstantValues["AMOUNT"])
+	require.Equal(t, payment, scenario.ConstantValues["PAYMENT"])
+	require.Equal(t, append([]byte("TOK-123456"), 0, 0, 0, 7), scenario.ConstantValues["ARGS"])
+
+	tx := scenario.Steps[0].(*scenmodel.TxStep).Tx
+	require.Equal(t, owner, tx.From.Value)
+	require.Equal(t, payment, tx.REWAValue.Value.Bytes())
+	require.Equal(t, []byte("TOK-123456"), tx.Arguments[0].Value)
+
+	// the constants and their references are written back as they were
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+}
+
+func TestParseScenarioConstantErrors(t *testing.T) {
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	_, err := p.ParseScenarioFile([]byte(`{"constants": {"A": "$B", "B": "1"}}`))
+	require.EqualError(t, err, `bad scenario constants: bad value of constant A: error at col 0 in "$B": unknown constant B`)
+
+	_, err = p.ParseScenarioFile([]byte(`{"constants": {"1A": "1"}}`))
+	require.EqualError(t, err, "bad scenario constants: invalid constant name: 1A")
+
+	_, err = p.ParseScenarioFile([]byte(`{"importConstants": ["missing.constants.json"]}`))
+	require.ErrorContains(t, err, "cannot import constants from missing.constants.json")
+}
+
+func TestWriteScenarioCapture(t *testing.T) {
+	contents, err := loadExampleFile(selfTestFolder + "capture/capture.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+
+	deployStep := scenario.Steps[1].(*scenmodel.TxStep)
+	require.Equal(t, []*scenmodel.Capture{{Source: scenmodel.CaptureAddress, Variable: "SC"}}, deployStep.Captures)
+
+	// the steps after the capture that reference the variables are deferred
+	for _, stepIndex := range []int{2, 3, 4} {
+		deferredStep, isDeferred := scenario.Steps[stepIndex].(*scenmodel.DeferredStep)
+		require.True(t, isDeferred)
+		require.Equal(t, scenario.Steps[stepIndex].StepTypeName(), deferredStep.StepType)
+	}
+
+	deferredStep := scenario.Steps[3].(*scenmodel.DeferredStep)
+	p.ExprInterpreter.Constants = map[string][]byte{"SC": []byte("sc-address______________________"), "NONCE": {7}}
+	step, err := p.ParseScenarioStepObject(deferredStep.JSON)
+	require.Nil(t, err)
+	require.Equal(t, []byte("sc-address______________________"), step.(*scenmodel.TxStep).Tx.To.Value)
+	require.Equal(t, []byte{8}, step.(*scenmodel.TxStep).Tx.Arguments[1].Value)
+
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+}
+
+func TestParseScenarioCaptureErrors(t *testing.T) {
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	_, err := p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {}, "capture": {"address": "$SC"}}]}`))
+	require.EqualError(t, err, "error processing steps: bad tx step capture: the address can only be captured from scDeploy steps")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {}, "capture": {"out": "$X"}}]}`))
+	require.EqualError(t, err, "error processing steps: bad tx step capture: unknown capture source: out")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {}, "capture": {"out[0]": "X"}}]}`))
+	require.EqualError(t, err, `error processing steps: bad tx step capture: out[0] should be captured into a variable, referenced as "$NAME": X`)
+
+	_, err = p.ParseScenarioFile([]byte(`{"constants": {"X": "1"}, "steps": [{"step": "scCall", "tx": {}, "capture": {"out[0]": "$X"}}]}`))
+	require.EqualError(t, err, "error processing steps: cannot capture into X, which is a constant")
+}
+
+func TestWriteScenarioABI(t *testing.T) {
+	contents, err := loadExampleFile(selfTestFolder + "abi/abi.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver().WithContext(selfTestFolder+"abi/abi.scen.json"), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+
+	deployTx := scenario.Steps[1].(*scenmodel.TxStep).Tx
+	require.Equal(t, "Echo", deployTx.ABI.Name)
+	require.Equal(t, []byte{0x0d, 0xe0, 0xb6, 0xb3, 0xa7, 0x64, 0x00, 0x00}, deployTx.Arguments[0].Value)
+
+	callStep := scenario.Steps[2].(*scenmodel.TxStep)
+	require.Len(t, callStep.Tx.Arguments, 4)
+	require.Equal(t, []byte{0, 0, 0, 1, 0, 0, 0, 5, 'f', 'i', 'r', 's', 't'}, callStep.Tx.Arguments[0].Value[:13])
+	require.Equal(t, []byte{5}, callStep.Tx.Arguments[2].Value)
+	require.Equal(t, []byte{6}, callStep.Tx.Arguments[3].Value)
+	require.True(t, callStep.ExpectedResult.Out.Values[2].IsStar)
+	require.Equal(t, callStep.Tx.Arguments[1].Value, callStep.ExpectedResult.Out.Values[1].Value)
+
+	noneStep := scenario.Steps[3].(*scenmodel.TxStep)
+	require.Equal(t, []byte{}, noneStep.Tx.Arguments[1].Value)
+
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+}
+
+func TestParseScenarioABIErrors(t *testing.T) {
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver().WithContext(selfTestFolder+"abi/abi.scen.json"), vmType)
+
+	_, err := p.ParseScenarioFile([]byte(`{"steps": [{"step": "transfer", "tx": {"abi": "file:echo.abi.json"}}]}`))
+	require.EqualError(t, err, "error processing steps: cannot parse tx step transaction: `abi` only allowed in smart contract transactions")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {"abi": "file:echo.abi.json", "function": "missing", "arguments": []}}]}`))
+	require.EqualError(t, err, "error processing steps: cannot parse tx step transaction: invalid transaction arguments: ABI has no endpoint missing")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {"abi": "file:echo.abi.json", "function": "echo", "arguments": [{"id": 1}, null]}}]}`))
+	require.EqualError(t, err, "error processing steps: cannot parse tx step transaction: invalid transaction arguments: argument 0: missing field name in Item")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {"abi": "file:echo.abi.json", "function": "echo", "arguments": []}}]}`))
+	require.EqualError(t, err, "error processing steps: cannot parse tx step transaction: invalid transaction arguments: missing value for item (Item)")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scQuery", "tx": {"abi": "file:echo.abi.json", "function": "echo", "arguments": [{"id": 1, "name": "", "owner": "sc:echo", "tags": []}, null]}, "expect": {"out": ["*", "Unknown"]}}]}`))
+	require.ErrorContains(t, err, "cannot parse tx expected result: invalid block result out: result 1: unknown variant Unknown of Action")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {"abi": "str:not an abi"}}]}`))
+	require.ErrorContains(t, err, "invalid transaction abi: invalid ABI")
+}
+
+func TestWriteScenarioCheckOperators(t *testing.T) {
+	contents, err := loadExampleFile("operators.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+
+	result := scenario.Steps[0].(*scenmodel.TxStep).ExpectedResult
+	require.Equal(t, scenmodel.CheckGreaterOrEqual, result.Out.Values[0].Operator.Kind)
+	require.True(t, result.Out.Values[0].Check([]byte{0x03, 0xe8}))
+	require.False(t, result.Out.Values[0].Check([]byte{0x03, 0xe7}))
+	require.True(t, result.Out.Values[1].Check([]byte{0x07, 0xd0}))
+	require.False(t, result.Out.Values[1].Check([]byte{0x07, 0xd1}))
+	require.True(t, result.Out.Values[2].Check(big.NewInt(995000000000000000).Bytes()))
+	require.False(t, result.Out.Values[2].Check(big.NewInt(994999999999999999).Bytes()))
+	require.False(t, result.Status.Check(big.NewInt(0)))
+	require.True(t, result.Message.Check([]byte("ERR_FAILED")))
+	require.False(t, result.Message.Check([]byte("OK")))
+	require.True(t, result.Gas.Check(999999))
+	require.False(t, result.Gas.Check(1000000))
+
+	account := scenario.Steps[1].(*scenmodel.CheckStateStep).CheckAccounts.Accounts[0]
+	require.True(t, account.Nonce.Check(5))
+	require.False(t, account.Nonce.Check(6))
+	require.True(t, account.Balance.Check(big.NewInt(1000000000000001000)))
+	require.False(t, account.Balance.Check(big.NewInt(1000000000000001001)))
+	require.True(t, account.CheckStorage[0].CheckValue.Check([]byte{1}))
+	require.False(t, account.CheckStorage[0].CheckValue.Check([]byte{}))
+
+	// the operators are written back as they were
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+}
+
+func TestParseScenarioCheckOperatorErrors(t *testing.T) {
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	_, err := p.ParseScenarioFile([]byte(`{"steps": [{"step": "checkState", "accounts": {"address:a": {"balance": ">="}}}]}`))
+	require.ErrorContains(t, err, `invalid check operator ">=": missing value`)
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "checkState", "accounts": {"address:a": {"balance": "20..10"}}}]}`))
+	require.ErrorContains(t, err, `invalid check operator "20..10": the lower bound is greater than the upper bound`)
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "checkState", "accounts": {"address:a": {"balance": "approx:1000"}}}]}`))
+	require.ErrorContains(t, err, `invalid check operator "approx:1000": missing tolerance, expected e.g. approx:1000±1%`)
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "checkState", "accounts": {"address:a": {"username": "regex:(ERR"}}}]}`))
+	require.ErrorContains(t, err, `invalid check operator "regex:(ERR": error parsing regexp`)
+}
+
+func TestWriteScenarioMatchers(t *testing.T) {
+	contents, err := loadExampleFile(selfTestFolder + "matchers/matchers.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+
+	result := scenario.Steps[1].(*scenmodel.TxStep).ExpectedResult
+	require.True(t, result.Out.MoreAllowed)
+	require.Len(t, result.Out.Values, 2)
+	require.Equal(t, scenmodel.CheckMask, result.Out.Values[1].Operator.Kind)
+
+	logEntry := scenario.Steps[2].(*scenmodel.TxStep).ExpectedResult.Logs.List[0]
+	require.True(t, logEntry.Topics.MoreAllowed)
+	require.Equal(t, scenmodel.CheckContains, logEntry.Data.Values[0].Operator.Kind)
+
+	// the matchers are written back as they were
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+}
+
+func TestWriteScenarioLogsUnordered(t *testing.T) {
+	contents, err := loadExampleFile(selfTestFolder + "logs/logs-unordered.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+
+	logs := scenario.Steps[1].(*scenmodel.TxStep).ExpectedResult.Logs
+	require.True(t, logs.Unordered)
+	require.Len(t, logs.List, 2)
+	require.True(t, logs.IsIgnored([]byte("DCDTTransfer")))
+	require.False(t, logs.IsIgnored([]byte("first")))
+
+	logs = scenario.Steps[3].(*scenmodel.TxStep).ExpectedResult.Logs
+	require.True(t, logs.MoreAllowedAtEnd)
+	require.Nil(t, logs.IgnoredIdentifiers)
+
+	// the logs options are written back as they were
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {}, "expect": {"logs": {"unordered": true}}}]}`))
+	require.ErrorContains(t, err, "missing logs list")
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {}, "expect": {"logs": {"sorted": true, "list": []}}}]}`))
+	require.ErrorContains(t, err, "unknown logs field: sorted")
+}
+
+func TestWriteScenarioOutputTransfers(t *testing.T) {
+	contents, err := loadExampleFile(selfTestFolder + "output-transfers/output-transfers.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+
+	transfers := scenario.Steps[1].(*scenmodel.TxStep).ExpectedResult.OutputTransfers
+	require.Len(t, transfers.List, 1)
+	require.Equal(t, "asynchronousCall", transfers.List[0].CallType)
+	require.Nil(t, transfers.List[0].DCDT)
+
+	transfers = scenario.Steps[2].(*scenmodel.TxStep).ExpectedResult.OutputTransfers
+	require.True(t, transfers.List[0].From.IsUnspecified())
+	require.Len(t, transfers.List[0].DCDT, 1)
+
+	// unspecified fields are left out when writing
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+
+	_, err = p.ParseScenarioFile([]byte(`{"steps": [{"step": "scCall", "tx": {}, "expect": {"outputTransfers": [{"callType": "syncCall"}]}}]}`))
+	require.ErrorContains(t, err, "unknown output transfer call type: syncCall")
+}
+
+func TestWriteScenarioStateUnchanged(t *testing.T) {
+	contents, err := loadExampleFile(selfTestFolder + "state-unchanged/state-unchanged.scen.json")
+	require.Nil(t, err)
+
+	p := scenjparse.NewParser(fr.NewDefaultFileResolver(), vmType)
+
+	scenario, parseErr := p.ParseScenarioFile(contents)
+	require.Nil(t, parseErr)
+	require.True(t, scenario.Steps[1].(*scenmodel.TxStep).ExpectedResult.StateUnchanged)
+
+	serialized := scenjwrite.ScenarioToJSONString(scenario)
+	require.Equal(t, string(contents), serialized)
+}
@@ -0,0 +1,126 @@
+package scenjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenabi "github.com/kalyan3104/k-chain-scenario-go/scenario/abi"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// processTxABI loads the contract ABI of a transaction, normally given as "file:....abi.json".
+func (p *Parser) processTxABI(abiRaw oj.OJsonObject) (scenmodel.JSONBytesFromString, *scenmodel.ABI, error) {
+	abiFile, err := p.processStringAsByteArray(abiRaw)
+	if err != nil {
+		return scenmodel.JSONBytesFromString{}, nil, err
+	}
+	abi, err := scenabi.ParseABI(abiFile.Value)
+	if err != nil {
+		return scenmodel.JSONBytesFromString{}, nil, err
+	}
+	return abiFile, abi, nil
+}
+
+func (p *Parser) txEndpoint(tx *scenmodel.Transaction) (*scenmodel.ABIEndpoint, error) {
+	endpoint := tx.ABI.EndpointFor(tx.Type, tx.Function)
+	if endpoint == nil {
+		if tx.Type == scenmodel.ScDeploy || tx.Type == scenmodel.ScUpgrade {
+			return nil, errors.New("ABI has no constructor")
+		}
+		return nil, fmt.Errorf("ABI has no endpoint %s", tx.Function)
+	}
+	return endpoint, nil
+}
+
+// processABIArguments encodes typed argument values, according to the inputs of the called endpoint.
+func (p *Parser) processABIArguments(tx *scenmodel.Transaction, argsRaw oj.OJsonObject) ([]scenmodel.JSONBytesFromTree, error) {
+	listRaw, isList := argsRaw.(*oj.OJsonList)
+	if !isList {
+		return nil, errors.New("not a JSON list")
+	}
+	endpoint, err := p.txEndpoint(tx)
+	if err != nil {
+		return nil, err
+	}
+	types, err := scenabi.ParamTypes(endpoint.Inputs, len(listRaw.AsList()))
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := &scenabi.Encoder{ABI: tx.ABI, Interpreter: &p.ExprInterpreter}
+	var result []scenmodel.JSONBytesFromTree
+	for i, argRaw := range listRaw.AsList() {
+		value, err := encoder.EncodeTopLevel(types[i], argRaw)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		result = append(result, scenmodel.JSONBytesFromTree{
+			Value:    value,
+			Original: argRaw,
+		})
+	}
+	return result, nil
+}
+
+// parseABICheckValueList encodes the typed values of the expected results, according to the outputs of the called endpoint.
+// Results can still be "*", to skip checking them, or check operators, which compare the raw results.
+func (p *Parser) parseABICheckValueList(tx *scenmodel.Transaction, obj oj.OJsonObject) (scenmodel.JSONCheckValueList, error) {
+	if IsStar(obj) {
+		return scenmodel.JSONCheckValueListStar(), nil
+	}
+	listRaw, isList := obj.(*oj.OJsonList)
+	if !isList {
+		return scenmodel.JSONCheckValueList{}, errors.New("not a JSON list")
+	}
+	elems, moreAllowed, err := splitCheckValueListMore(listRaw)
+	if err != nil {
+		return scenmodel.JSONCheckValueList{}, err
+	}
+	endpoint, err := p.txEndpoint(tx)
+	if err != nil {
+		return scenmodel.JSONCheckValueList{}, err
+	}
+	var types []string
+	if moreAllowed {
+		types, err = scenabi.LeadingParamTypes(endpoint.Outputs, len(elems))
+	} else {
+		types, err = scenabi.ParamTypes(endpoint.Outputs, len(elems))
+	}
+	if err != nil {
+		return scenmodel.JSONCheckValueList{}, err
+	}
+
+	encoder := &scenabi.Encoder{ABI: tx.ABI, Interpreter: &p.ExprInterpreter}
+	values := []scenmodel.JSONCheckBytes{}
+	for i, elemRaw := range elems {
+		if IsStar(elemRaw) {
+			values = append(values, scenmodel.JSONCheckBytesStar())
+			continue
+		}
+		op, err := p.parseCheckBytesOperator(elemRaw)
+		if err != nil {
+			return scenmodel.JSONCheckValueList{}, fmt.Errorf("result %d: %w", i, err)
+		}
+		if op != nil {
+			values = append(values, scenmodel.JSONCheckBytes{
+				Value:    []byte{},
+				Operator: op,
+				Original: elemRaw,
+			})
+			continue
+		}
+		value, err := encoder.EncodeTopLevel(types[i], elemRaw)
+		if err != nil {
+			return scenmodel.JSONCheckValueList{}, fmt.Errorf("result %d: %w", i, err)
+		}
+		values = append(values, scenmodel.JSONCheckBytes{
+			Value:    value,
+			Original: elemRaw,
+		})
+	}
+	return scenmodel.JSONCheckValueList{
+		Values:      values,
+		MoreAllowed: moreAllowed,
+	}, nil
+}
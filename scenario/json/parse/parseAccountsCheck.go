@@ -3,6 +3,7 @@ package scenjsonparse
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
@@ -44,12 +45,12 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*scenmodel.CheckAc
 		case "nonce":
 			acct.Nonce, err = p.processCheckUint64(kvp.Value)
 			if err != nil {
-				return nil, errors.New("invalid account nonce")
+				return nil, fmt.Errorf("invalid account nonce: %w", err)
 			}
 		case "balance":
 			acct.Balance, err = p.processCheckBigInt(kvp.Value, bigIntUnsignedBytes)
 			if err != nil {
-				return nil, errors.New("invalid account balance")
+				return nil, fmt.Errorf("invalid account balance: %w", err)
 			}
 		case "dcdt":
 			acct.IgnoreDCDT = IsStar(kvp.Value)
@@ -142,6 +143,26 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*scenmodel.CheckAc
 	return &acct, nil
 }
 
+// ParseCheckAccountsFile converts the contents of a golden file to the account checks it holds.
+// Golden files contain a single accounts map, in the same format as the checkState accounts.
+func (p *Parser) ParseCheckAccountsFile(jsonString []byte) (*scenmodel.CheckAccounts, error) {
+	jobj, err := oj.ParseOrderedJSON(jsonString)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.processCheckAccountMap(jobj)
+}
+
+// goldenAccountsPath yields the file path, if the checkState accounts are given as "golden:<path>".
+func goldenAccountsPath(accountsRaw oj.OJsonObject) (string, bool) {
+	str, isStr := accountsRaw.(*oj.OJsonString)
+	if !isStr || !strings.HasPrefix(str.Value, scenmodel.GoldenAccountsPrefix) {
+		return "", false
+	}
+	return str.Value[len(scenmodel.GoldenAccountsPrefix):], true
+}
+
 func (p *Parser) processCheckAccountMap(acctMapRaw oj.OJsonObject) (*scenmodel.CheckAccounts, error) {
 	var checkAccounts = &scenmodel.CheckAccounts{
 		Accounts:            nil,
@@ -0,0 +1,180 @@
+package scenjsonparse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// processCaptures reads a map of capture sources, "out[N]" or "address", to variable references, "$NAME".
+func (p *Parser) processCaptures(txType scenmodel.TransactionType, obj oj.OJsonObject) ([]*scenmodel.Capture, error) {
+	captureMap, isMap := obj.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("capture object is not a map")
+	}
+
+	var captures []*scenmodel.Capture
+	for _, kvp := range captureMap.OrderedKV {
+		reference, err := p.parseString(kvp.Value)
+		if err != nil {
+			return nil, fmt.Errorf("bad variable for %s: %w", kvp.Key, err)
+		}
+		variable, isReference := ei.ConstantReferenceName(reference)
+		if !isReference {
+			return nil, fmt.Errorf(`%s should be captured into a variable, referenced as "$NAME": %s`, kvp.Key, reference)
+		}
+
+		source, outIndex, err := parseCaptureSource(txType, kvp.Key)
+		if err != nil {
+			return nil, err
+		}
+		captures = append(captures, &scenmodel.Capture{
+			Source:   source,
+			OutIndex: outIndex,
+			Variable: variable,
+		})
+	}
+	return captures, nil
+}
+
+func parseCaptureSource(txType scenmodel.TransactionType, key string) (scenmodel.CaptureSource, int, error) {
+	if !txType.IsSmartContractTx() {
+		return scenmodel.CaptureOut, 0, errors.New("values can only be captured from smart contract transactions")
+	}
+
+	if key == scenmodel.CaptureAddressKey {
+		if txType != scenmodel.ScDeploy {
+			return scenmodel.CaptureOut, 0, fmt.Errorf("the address can only be captured from %s steps", scenmodel.StepNameScDeploy)
+		}
+		return scenmodel.CaptureAddress, 0, nil
+	}
+
+	outPrefix := scenmodel.CaptureOutPrefix + "["
+	if strings.HasPrefix(key, outPrefix) && strings.HasSuffix(key, "]") {
+		outIndex, err := strconv.Atoi(key[len(outPrefix) : len(key)-1])
+		if err == nil && outIndex >= 0 {
+			return scenmodel.CaptureOut, outIndex, nil
+		}
+	}
+
+	return scenmodel.CaptureOut, 0, fmt.Errorf("unknown capture source: %s", key)
+}
+
+// deferStep keeps the JSON of a step that references captured variables, to be parsed when executed.
+func (p *Parser) deferStep(stepObj oj.OJsonObject) (*scenmodel.DeferredStep, error) {
+	stepMap := stepObj.(*oj.OJsonMap)
+	step := &scenmodel.DeferredStep{
+		JSON:     stepObj,
+		Position: stepPosition(stepMap),
+	}
+	for _, kvp := range stepMap.OrderedKV {
+		if kvp.Key == "step" {
+			var err error
+			step.StepType, err = p.parseString(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("step type not a string: %w", err)
+			}
+		}
+	}
+	return step, nil
+}
+
+// declareCapturedVariables makes the variables captured by a step known to the steps after it.
+// The step is read from its JSON, because it might be deferred itself.
+func (p *Parser) declareCapturedVariables(stepObj oj.OJsonObject) error {
+	stepMap := stepObj.(*oj.OJsonMap)
+	for _, kvp := range stepMap.OrderedKV {
+		if kvp.Key != "step" {
+			continue
+		}
+		if stepType, err := p.parseString(kvp.Value); err == nil && stepType == scenmodel.StepNameExternalSteps {
+			return p.declareExternalCapturedVariables(stepMap)
+		}
+	}
+	for _, kvp := range stepMap.OrderedKV {
+		if kvp.Key != "capture" {
+			continue
+		}
+		captureMap, isMap := kvp.Value.(*oj.OJsonMap)
+		if !isMap {
+			continue
+		}
+		for _, captureKvp := range captureMap.OrderedKV {
+			reference, err := p.parseString(captureKvp.Value)
+			if err != nil {
+				continue
+			}
+			variable, isReference := ei.ConstantReferenceName(reference)
+			if !isReference {
+				continue
+			}
+			if _, isConstant := p.ExprInterpreter.Constants[variable]; isConstant {
+				return fmt.Errorf("cannot capture into %s, which is a constant", variable)
+			}
+			p.ExprInterpreter.Variables[variable] = true
+		}
+	}
+	return nil
+}
+
+// declareExternalCapturedVariables declares the variables captured in an external steps file,
+// since they are also available to the steps after the externalSteps step.
+// Files that cannot be read are skipped here, the error shows up when the step runs.
+func (p *Parser) declareExternalCapturedVariables(stepMap *oj.OJsonMap) error {
+	var path string
+	for _, kvp := range stepMap.OrderedKV {
+		if kvp.Key == "path" {
+			path, _ = p.parseString(kvp.Value)
+		}
+	}
+	if len(path) == 0 {
+		return nil
+	}
+
+	fileResolver := p.ExprInterpreter.FileResolver
+	contents, err := fileResolver.ResolveFileValue(path)
+	if err != nil {
+		return nil
+	}
+	externalObj, err := oj.ParseOrderedJSON(contents)
+	if err != nil {
+		return nil
+	}
+	externalMap, isMap := externalObj.(*oj.OJsonMap)
+	if !isMap {
+		return nil
+	}
+
+	// paths in the external file are relative to it
+	externalFileResolver := fileResolver.Clone()
+	externalFileResolver.SetContext(fileResolver.ResolveAbsolutePath(path))
+	p.ExprInterpreter.FileResolver = externalFileResolver
+	defer func() {
+		p.ExprInterpreter.FileResolver = fileResolver
+	}()
+
+	for _, kvp := range externalMap.OrderedKV {
+		if kvp.Key != "steps" {
+			continue
+		}
+		stepList, isList := kvp.Value.(*oj.OJsonList)
+		if !isList {
+			continue
+		}
+		for _, externalStep := range stepList.AsList() {
+			if _, isStepMap := externalStep.(*oj.OJsonMap); !isStepMap {
+				continue
+			}
+			err = p.declareCapturedVariables(externalStep)
+			if err != nil {
+				return fmt.Errorf("external steps %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
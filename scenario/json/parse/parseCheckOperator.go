@@ -0,0 +1,246 @@
+package scenjsonparse
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// comparisonOperators are the prefixes of the check operators that compare with a single bound.
+// Longer prefixes come first, so that ">=" is not taken for ">".
+var comparisonOperators = []struct {
+	prefix string
+	kind   scenmodel.CheckOperatorKind
+}{
+	{">=", scenmodel.CheckGreaterOrEqual},
+	{"<=", scenmodel.CheckLessOrEqual},
+	{"!=", scenmodel.CheckNotEqual},
+	{">", scenmodel.CheckGreater},
+	{"<", scenmodel.CheckLess},
+}
+
+// bytesMatchers are the prefixes of the partial matchers, which only apply to bytes.
+var bytesMatchers = []struct {
+	prefix string
+	kind   scenmodel.CheckOperatorKind
+}{
+	{"prefix:", scenmodel.CheckPrefix},
+	{"suffix:", scenmodel.CheckSuffix},
+	{"contains:", scenmodel.CheckContains},
+}
+
+const maskPrefix = "mask:"
+const maskAnyPrefix = "any:"
+const maskSeparator = "|"
+
+const approxPrefix = "approx:"
+const regexPrefix = "regex:"
+const rangeSeparator = ".."
+
+// Separators between the center and the tolerance of an approximation, the second one is easier to type.
+var toleranceSeparators = []string{"±", "+-"}
+
+// parseCheckOperator parses the operators that expected values can use instead of an exact value,
+// e.g. ">=1000", "!=0", "1000..2000", "approx:1000±1%" or "regex:^ERR_.*".
+// The bounds are value expressions, so ">=rewa:1" also works.
+// Yields nil if the value is not a check operator.
+func (p *Parser) parseCheckOperator(obj oj.OJsonObject, format bigIntParseFormat) (*scenmodel.CheckOperator, error) {
+	str, isStr := obj.(*oj.OJsonString)
+	if !isStr {
+		return nil, nil
+	}
+	op, err := p.parseCheckOperatorString(str.Value, format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid check operator \"%s\": %w", str.Value, err)
+	}
+	return op, nil
+}
+
+// parseCheckBytesOperator also parses the partial matchers, which only apply to bytes,
+// e.g. "prefix:str:ERR", "contains:u32:5" or "mask:u32:5|any:8|str:abc".
+// Yields nil if the value is not a check operator.
+func (p *Parser) parseCheckBytesOperator(obj oj.OJsonObject) (*scenmodel.CheckOperator, error) {
+	str, isStr := obj.(*oj.OJsonString)
+	if !isStr {
+		return nil, nil
+	}
+	op, err := p.parseBytesMatcher(str.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid check operator \"%s\": %w", str.Value, err)
+	}
+	if op != nil {
+		return op, nil
+	}
+	return p.parseCheckOperator(obj, bigIntUnsignedBytes)
+}
+
+func (p *Parser) parseBytesMatcher(strVal string) (*scenmodel.CheckOperator, error) {
+	for _, matcher := range bytesMatchers {
+		if strings.HasPrefix(strVal, matcher.prefix) {
+			pattern, err := p.ExprInterpreter.InterpretString(strVal[len(matcher.prefix):])
+			if err != nil {
+				return nil, err
+			}
+			return &scenmodel.CheckOperator{
+				Kind:    matcher.kind,
+				Pattern: pattern,
+			}, nil
+		}
+	}
+
+	if strings.HasPrefix(strVal, maskPrefix) {
+		return p.parseMask(strVal[len(maskPrefix):])
+	}
+
+	return nil, nil
+}
+
+// parseMask parses the parts of a mask, each either a value expression, or "any:N" for N bytes of any value.
+func (p *Parser) parseMask(strVal string) (*scenmodel.CheckOperator, error) {
+	op := &scenmodel.CheckOperator{
+		Kind:    scenmodel.CheckMask,
+		Pattern: []byte{},
+	}
+	for _, part := range strings.Split(strVal, maskSeparator) {
+		if anyLength, isAny := strings.CutPrefix(part, maskAnyPrefix); isAny {
+			length, err := strconv.Atoi(anyLength)
+			if err != nil || length <= 0 {
+				return nil, fmt.Errorf("invalid masked length %s", anyLength)
+			}
+			op.Pattern = append(op.Pattern, make([]byte, length)...)
+			op.Masked = append(op.Masked, repeatBool(true, length)...)
+			continue
+		}
+
+		value, err := p.ExprInterpreter.InterpretString(part)
+		if err != nil {
+			return nil, err
+		}
+		op.Pattern = append(op.Pattern, value...)
+		op.Masked = append(op.Masked, repeatBool(false, len(value))...)
+	}
+	return op, nil
+}
+
+func repeatBool(b bool, count int) []bool {
+	result := make([]bool, count)
+	for i := range result {
+		result[i] = b
+	}
+	return result
+}
+
+func (p *Parser) parseCheckOperatorString(strVal string, format bigIntParseFormat) (*scenmodel.CheckOperator, error) {
+	if strings.HasPrefix(strVal, regexPrefix) {
+		regex, err := regexp.Compile(strVal[len(regexPrefix):])
+		if err != nil {
+			return nil, err
+		}
+		return &scenmodel.CheckOperator{
+			Kind:  scenmodel.CheckRegex,
+			Regex: regex,
+		}, nil
+	}
+
+	if strings.HasPrefix(strVal, approxPrefix) {
+		return p.parseApprox(strVal[len(approxPrefix):], format)
+	}
+
+	for _, comparison := range comparisonOperators {
+		if strings.HasPrefix(strVal, comparison.prefix) {
+			bound, err := p.parseBound(strVal[len(comparison.prefix):], format)
+			if err != nil {
+				return nil, err
+			}
+			return &scenmodel.CheckOperator{
+				Kind:  comparison.kind,
+				Bound: bound,
+			}, nil
+		}
+	}
+
+	if isRange(strVal) {
+		separator := strings.Index(strVal, rangeSeparator)
+		lower, err := p.parseBound(strVal[:separator], format)
+		if err != nil {
+			return nil, err
+		}
+		upper, err := p.parseBound(strVal[separator+len(rangeSeparator):], format)
+		if err != nil {
+			return nil, err
+		}
+		if lower.Cmp(upper) > 0 {
+			return nil, errors.New("the lower bound is greater than the upper bound")
+		}
+		return &scenmodel.CheckOperator{
+			Kind:       scenmodel.CheckRange,
+			Bound:      lower,
+			UpperBound: upper,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// isRange tells ranges apart from values that contain ".." in text or file paths.
+func isRange(strVal string) bool {
+	return strings.Contains(strVal, rangeSeparator) &&
+		!strings.Contains(strVal, "str:") &&
+		!strings.Contains(strVal, "file:")
+}
+
+func (p *Parser) parseApprox(strVal string, format bigIntParseFormat) (*scenmodel.CheckOperator, error) {
+	for _, toleranceSeparator := range toleranceSeparators {
+		separator := strings.LastIndex(strVal, toleranceSeparator)
+		if separator < 0 {
+			continue
+		}
+		center, err := p.parseBound(strVal[:separator], format)
+		if err != nil {
+			return nil, err
+		}
+		tolerance, err := p.parseTolerance(strVal[separator+len(toleranceSeparator):], center)
+		if err != nil {
+			return nil, err
+		}
+		return &scenmodel.CheckOperator{
+			Kind:      scenmodel.CheckApprox,
+			Bound:     center,
+			Tolerance: tolerance,
+		}, nil
+	}
+	return nil, errors.New("missing tolerance, expected e.g. approx:1000±1%")
+}
+
+// parseTolerance parses either a percentage of the center, or an absolute tolerance.
+func (p *Parser) parseTolerance(strVal string, center *big.Int) (*big.Rat, error) {
+	strVal = strings.TrimSpace(strVal)
+	if percentage, isPercentage := strings.CutSuffix(strVal, "%"); isPercentage {
+		tolerance, isRat := big.NewRat(0, 1).SetString(strings.TrimSpace(percentage))
+		if !isRat || tolerance.Sign() < 0 {
+			return nil, fmt.Errorf("invalid tolerance %s", strVal)
+		}
+		tolerance.Mul(tolerance, big.NewRat(0, 1).SetInt(big.NewInt(0).Abs(center)))
+		return tolerance.Quo(tolerance, big.NewRat(100, 1)), nil
+	}
+
+	tolerance, err := p.parseBound(strVal, bigIntUnsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewRat(0, 1).SetInt(tolerance), nil
+}
+
+func (p *Parser) parseBound(strVal string, format bigIntParseFormat) (*big.Int, error) {
+	strVal = strings.TrimSpace(strVal)
+	if len(strVal) == 0 {
+		return nil, errors.New("missing value")
+	}
+	return p.parseBigInt(strVal, format)
+}
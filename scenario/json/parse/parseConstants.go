@@ -0,0 +1,113 @@
+package scenjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	ei "github.com/kalyan3104/k-chain-scenario-go/scenario/expression/interpreter"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// parseScenarioConstants defines the constants of a scenario before anything else in it is parsed,
+// so that they can be referenced anywhere in the file, regardless of the order of the fields.
+// The constants already known to the interpreter, i.e. inherited from the scenario running this one
+// as external steps, come first, then the imported ones, then the ones defined by the scenario itself.
+func (p *Parser) parseScenarioConstants(topMap *oj.OJsonMap, scenario *scenmodel.Scenario) error {
+	scenario.ConstantValues = make(map[string][]byte, len(p.ExprInterpreter.Constants))
+	for name, value := range p.ExprInterpreter.Constants {
+		scenario.ConstantValues[name] = value
+	}
+	p.ExprInterpreter.Constants = scenario.ConstantValues
+	scenario.NegativeConstants = make(map[string]bool, len(p.ExprInterpreter.NegativeConstants))
+	for name, negative := range p.ExprInterpreter.NegativeConstants {
+		scenario.NegativeConstants[name] = negative
+	}
+	p.ExprInterpreter.NegativeConstants = scenario.NegativeConstants
+
+	var err error
+	for _, kvp := range topMap.OrderedKV {
+		if kvp.Key == "importConstants" {
+			scenario.ConstantImports, err = p.processStringList(kvp.Value)
+			if err != nil {
+				return fmt.Errorf("bad scenario importConstants: %w", err)
+			}
+			for _, importPath := range scenario.ConstantImports {
+				err = p.importConstants(importPath)
+				if err != nil {
+					return fmt.Errorf("cannot import constants from %s: %w", importPath, err)
+				}
+			}
+		}
+	}
+
+	for _, kvp := range topMap.OrderedKV {
+		if kvp.Key == "constants" {
+			scenario.Constants, err = p.processConstants(kvp.Value)
+			if err != nil {
+				return fmt.Errorf("bad scenario constants: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// importConstants defines the constants of another scenario file.
+// Only the constants the file defines itself are imported, not the ones it imports in turn.
+func (p *Parser) importConstants(importPath string) error {
+	contents, err := p.ExprInterpreter.FileResolver.ResolveFileValue(importPath)
+	if err != nil {
+		return err
+	}
+
+	jobj, err := oj.ParseOrderedJSON(contents)
+	if err != nil {
+		return err
+	}
+	topMap, isMap := jobj.(*oj.OJsonMap)
+	if !isMap {
+		return errors.New("top level object is not a map")
+	}
+
+	for _, kvp := range topMap.OrderedKV {
+		if kvp.Key == "constants" {
+			_, err = p.processConstants(kvp.Value)
+			return err
+		}
+	}
+	return errors.New("no constants defined")
+}
+
+// processConstants defines constants in order, so each of them can reference the ones before it.
+func (p *Parser) processConstants(obj oj.OJsonObject) ([]*scenmodel.Constant, error) {
+	constantsMap, isMap := obj.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("constants object is not a map")
+	}
+
+	var constants []*scenmodel.Constant
+	for _, kvp := range constantsMap.OrderedKV {
+		if !ei.IsConstantName(kvp.Key) {
+			return nil, fmt.Errorf("invalid constant name: %s", kvp.Key)
+		}
+
+		value, err := p.processSubTreeAsByteArray(kvp.Value)
+		if err != nil {
+			return nil, fmt.Errorf("bad value of constant %s: %w", kvp.Key, err)
+		}
+		p.ExprInterpreter.Constants[kvp.Key] = value.Value
+		p.ExprInterpreter.NegativeConstants[kvp.Key] = p.isNegativeNumber(kvp.Value)
+		constants = append(constants, &scenmodel.Constant{
+			Name:  kvp.Key,
+			Value: value,
+		})
+	}
+	return constants, nil
+}
+
+// isNegativeNumber checks whether the value of a constant is a negative number, which arithmetic decodes as signed.
+func (p *Parser) isNegativeNumber(obj oj.OJsonObject) bool {
+	str, isStr := obj.(*oj.OJsonString)
+	return isStr && p.ExprInterpreter.IsNegativeNumber(str.Value)
+}
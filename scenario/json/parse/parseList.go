@@ -2,6 +2,7 @@ package scenjsonparse
 
 import (
 	"errors"
+	"fmt"
 
 	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
@@ -89,8 +90,12 @@ func (p *Parser) parseCheckValueList(obj oj.OJsonObject) (scenmodel.JSONCheckVal
 }
 
 func (p *Parser) parseCheckValueJSONList(listRaw *oj.OJsonList) (scenmodel.JSONCheckValueList, error) {
+	elems, moreAllowed, err := splitCheckValueListMore(listRaw)
+	if err != nil {
+		return scenmodel.JSONCheckValueList{}, err
+	}
 	var values []scenmodel.JSONCheckBytes
-	for _, elemRaw := range listRaw.AsList() {
+	for _, elemRaw := range elems {
 		checkBytes, err := p.parseCheckBytes(elemRaw)
 		if err != nil {
 			return scenmodel.JSONCheckValueList{}, err
@@ -98,6 +103,23 @@ func (p *Parser) parseCheckValueJSONList(listRaw *oj.OJsonList) (scenmodel.JSONC
 		values = append(values, checkBytes)
 	}
 	return scenmodel.JSONCheckValueList{
-		Values: values,
+		Values:      values,
+		MoreAllowed: moreAllowed,
 	}, nil
 }
+
+// splitCheckValueListMore separates the "..." last element, which allows any number of further values.
+func splitCheckValueListMore(listRaw *oj.OJsonList) ([]oj.OJsonObject, bool, error) {
+	elems := listRaw.AsList()
+	for i, elemRaw := range elems {
+		str, isStr := elemRaw.(*oj.OJsonString)
+		if !isStr || str.Value != scenmodel.JSONCheckValueListMore {
+			continue
+		}
+		if i != len(elems)-1 {
+			return nil, false, fmt.Errorf("\"%s\" is only allowed as the last value", scenmodel.JSONCheckValueListMore)
+		}
+		return elems[:i], true, nil
+	}
+	return elems, false, nil
+}
@@ -16,10 +16,67 @@ func (p *Parser) processLogList(logsRaw oj.OJsonObject) (scenmodel.LogList, erro
 		}, nil
 	}
 
+	if logsMap, isMap := logsRaw.(*oj.OJsonMap); isMap {
+		return p.processLogListWithOptions(logsMap)
+	}
+
 	logList, isList := logsRaw.(*oj.OJsonList)
 	if !isList {
 		return scenmodel.LogList{}, errors.New("unmarshalled logs list is not a list")
 	}
+	return p.processLogListItems(logList)
+}
+
+// processLogListWithOptions parses logs written as an object, e.g.
+// {"unordered": true, "ignore": ["str:DCDTTransfer"], "list": [...]}.
+func (p *Parser) processLogListWithOptions(logsMap *oj.OJsonMap) (scenmodel.LogList, error) {
+	result := scenmodel.LogList{}
+	var listRaw *oj.OJsonList
+	var err error
+	for _, kvp := range logsMap.OrderedKV {
+		switch kvp.Key {
+		case "unordered":
+			result.Unordered, err = p.parseBool(kvp.Value)
+			if err != nil {
+				return scenmodel.LogList{}, fmt.Errorf("invalid logs unordered flag: %w", err)
+			}
+		case "ignore":
+			ignoreRaw, isList := kvp.Value.(*oj.OJsonList)
+			if !isList {
+				return scenmodel.LogList{}, errors.New("ignored log identifiers are not a list")
+			}
+			result.IgnoredIdentifiers = []scenmodel.JSONCheckBytes{}
+			for _, identifierRaw := range ignoreRaw.AsList() {
+				identifier, err := p.parseCheckBytes(identifierRaw)
+				if err != nil {
+					return scenmodel.LogList{}, fmt.Errorf("invalid ignored log identifier: %w", err)
+				}
+				result.IgnoredIdentifiers = append(result.IgnoredIdentifiers, identifier)
+			}
+		case "list":
+			var isList bool
+			listRaw, isList = kvp.Value.(*oj.OJsonList)
+			if !isList {
+				return scenmodel.LogList{}, errors.New("unmarshalled logs list is not a list")
+			}
+		default:
+			return scenmodel.LogList{}, fmt.Errorf("unknown logs field: %s", kvp.Key)
+		}
+	}
+	if listRaw == nil {
+		return scenmodel.LogList{}, errors.New("missing logs list")
+	}
+
+	items, err := p.processLogListItems(listRaw)
+	if err != nil {
+		return scenmodel.LogList{}, err
+	}
+	result.MoreAllowedAtEnd = items.MoreAllowedAtEnd
+	result.List = items.List
+	return result, nil
+}
+
+func (p *Parser) processLogListItems(logList *oj.OJsonList) (scenmodel.LogList, error) {
 	result := scenmodel.LogList{
 		IsUnspecified:    false,
 		IsStar:           false,
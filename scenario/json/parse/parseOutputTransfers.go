@@ -0,0 +1,151 @@
+package scenjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+)
+
+func (p *Parser) processOutputTransferList(transfersRaw oj.OJsonObject) (scenmodel.OutputTransferList, error) {
+	if IsStar(transfersRaw) {
+		return scenmodel.OutputTransferList{IsStar: true}, nil
+	}
+
+	transferList, isList := transfersRaw.(*oj.OJsonList)
+	if !isList {
+		return scenmodel.OutputTransferList{}, errors.New("unmarshalled output transfers list is not a list")
+	}
+
+	result := scenmodel.OutputTransferList{}
+	for _, transferRaw := range transferList.AsList() {
+		switch transferItem := transferRaw.(type) {
+		case *oj.OJsonString:
+			if transferItem.Value != "+" {
+				return scenmodel.OutputTransferList{}, errors.New("unmarshalled output transfer is an invalid string")
+			}
+			result.MoreAllowedAtEnd = true
+		case *oj.OJsonMap:
+			if result.MoreAllowedAtEnd {
+				return scenmodel.OutputTransferList{}, errors.New(`"+" is only allowed at the end of the output transfers`)
+			}
+			transfer, err := p.processOutputTransfer(transferItem)
+			if err != nil {
+				return scenmodel.OutputTransferList{}, err
+			}
+			result.List = append(result.List, transfer)
+		default:
+			return scenmodel.OutputTransferList{}, errors.New("output transfer should be either string or object")
+		}
+	}
+
+	return result, nil
+}
+
+func (p *Parser) processOutputTransfer(transferMap *oj.OJsonMap) (*scenmodel.CheckOutputTransfer, error) {
+	transfer := scenmodel.NewCheckOutputTransfer()
+	var err error
+	for _, kvp := range transferMap.OrderedKV {
+		switch kvp.Key {
+		case "from":
+			transfer.From, err = p.parseCheckBytes(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid output transfer sender: %w", err)
+			}
+		case "to":
+			transfer.To, err = p.parseCheckBytes(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid output transfer recipient: %w", err)
+			}
+		case "value":
+			transfer.Value, err = p.processCheckBigInt(kvp.Value, bigIntUnsignedBytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid output transfer value: %w", err)
+			}
+		case "dcdt":
+			transfer.DCDT, err = p.processCheckDCDTTransfers(kvp.Value)
+			if err != nil {
+				return nil, err
+			}
+		case "data":
+			transfer.Data, err = p.parseCheckBytes(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid output transfer data: %w", err)
+			}
+		case "gasLimit":
+			transfer.GasLimit, err = p.processCheckUint64(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid output transfer gas limit: %w", err)
+			}
+		case "callType":
+			transfer.CallType, err = p.processCallType(kvp.Value)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown output transfer field: %s", kvp.Key)
+		}
+	}
+	return transfer, nil
+}
+
+func (p *Parser) processCheckDCDTTransfers(dcdtRaw oj.OJsonObject) ([]*scenmodel.CheckDCDTTransfer, error) {
+	dcdtList, isList := dcdtRaw.(*oj.OJsonList)
+	if !isList {
+		return nil, errors.New("output transfer DCDT is not a list")
+	}
+
+	transfers := make([]*scenmodel.CheckDCDTTransfer, 0)
+	for _, dcdtItemRaw := range dcdtList.AsList() {
+		dcdtMap, isMap := dcdtItemRaw.(*oj.OJsonMap)
+		if !isMap {
+			return nil, errors.New("output transfer DCDT entry is not a map")
+		}
+		dcdtTransfer := &scenmodel.CheckDCDTTransfer{
+			TokenIdentifier: scenmodel.JSONCheckBytesUnspecified(),
+			Nonce:           scenmodel.JSONCheckUint64Unspecified(),
+			Value:           scenmodel.JSONCheckBigIntUnspecified(),
+		}
+		var err error
+		for _, kvp := range dcdtMap.OrderedKV {
+			switch kvp.Key {
+			case "tokenIdentifier":
+				dcdtTransfer.TokenIdentifier, err = p.parseCheckBytes(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid output transfer DCDT token identifier: %w", err)
+				}
+			case "nonce":
+				dcdtTransfer.Nonce, err = p.processCheckUint64(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid output transfer DCDT nonce: %w", err)
+				}
+			case "value":
+				dcdtTransfer.Value, err = p.processCheckBigInt(kvp.Value, bigIntUnsignedBytes)
+				if err != nil {
+					return nil, fmt.Errorf("invalid output transfer DCDT value: %w", err)
+				}
+			default:
+				return nil, fmt.Errorf("unknown output transfer DCDT field: %s", kvp.Key)
+			}
+		}
+		transfers = append(transfers, dcdtTransfer)
+	}
+	return transfers, nil
+}
+
+// processCallType accepts the call type names used by the VM, e.g. "asynchronousCall".
+func (p *Parser) processCallType(callTypeRaw oj.OJsonObject) (string, error) {
+	callType, err := p.parseString(callTypeRaw)
+	if err != nil {
+		return "", fmt.Errorf("invalid output transfer call type: %w", err)
+	}
+	for ct := vm.DirectCall; ct <= vm.ExecOnDestByCaller; ct++ {
+		if ct.ToString() == callType {
+			return callType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output transfer call type: %s", callType)
+}
@@ -3,6 +3,7 @@ package scenjsonparse
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
@@ -26,6 +27,20 @@ func (p *Parser) ParseScenarioFile(jsonString []byte) (*scenmodel.Scenario, erro
 		GasSchedule: scenmodel.GasScheduleDefault,
 	}
 
+	constantsBackup := p.ExprInterpreter.Constants
+	negativeConstantsBackup := p.ExprInterpreter.NegativeConstants
+	variablesBackup := p.ExprInterpreter.Variables
+	defer func() {
+		p.ExprInterpreter.Constants = constantsBackup
+		p.ExprInterpreter.NegativeConstants = negativeConstantsBackup
+		p.ExprInterpreter.Variables = variablesBackup
+	}()
+	p.ExprInterpreter.Variables = make(map[string]bool)
+	err = p.parseScenarioConstants(topMap, scenario)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, kvp := range topMap.OrderedKV {
 		switch kvp.Key {
 		case "name":
@@ -38,6 +53,11 @@ func (p *Parser) ParseScenarioFile(jsonString []byte) (*scenmodel.Scenario, erro
 			if err != nil {
 				return nil, fmt.Errorf("bad scenario comment: %w", err)
 			}
+		case "tags":
+			scenario.Tags, err = p.processStringList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad scenario tags: %w", err)
+			}
 		case "checkGas":
 			checkGasOJ, isBool := kvp.Value.(*oj.OJsonBool)
 			if !isBool {
@@ -51,10 +71,17 @@ func (p *Parser) ParseScenarioFile(jsonString []byte) (*scenmodel.Scenario, erro
 			}
 			scenario.TraceGas = bool(*traceGasOJ)
 		case "gasSchedule":
-			scenario.GasSchedule, err = p.parseGasSchedule(kvp.Value)
+			scenario.GasSchedule, scenario.GasSchedulePath, err = p.parseGasSchedule(kvp.Value)
 			if err != nil {
 				return nil, fmt.Errorf("bad scenario gasSchedule: %w", err)
 			}
+		case "gasScheduleOverrides":
+			scenario.GasScheduleOverrides, err = p.parseGasScheduleOverrides(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad scenario gasScheduleOverrides: %w", err)
+			}
+		case "importConstants", "constants":
+			// already processed, before everything else
 		case "steps":
 			scenario.Steps, err = p.processScenarioStepList(kvp.Value)
 			if err != nil {
@@ -67,25 +94,65 @@ func (p *Parser) ParseScenarioFile(jsonString []byte) (*scenmodel.Scenario, erro
 	return scenario, nil
 }
 
-func (p *Parser) parseGasSchedule(value oj.OJsonObject) (scenmodel.GasSchedule, error) {
+// parseGasSchedule yields the gas schedule, and the path of the gas schedule file, if it is loaded from one.
+func (p *Parser) parseGasSchedule(value oj.OJsonObject) (scenmodel.GasSchedule, string, error) {
 	gasScheduleStr, err := p.parseString(value)
 	if err != nil {
-		return scenmodel.GasScheduleDummy, fmt.Errorf("gasSchedule type not a string: %w", err)
+		return scenmodel.GasScheduleDummy, "", fmt.Errorf("gasSchedule type not a string: %w", err)
+	}
+	if strings.HasPrefix(gasScheduleStr, scenmodel.GasScheduleFilePrefix) {
+		gasSchedulePath := gasScheduleStr[len(scenmodel.GasScheduleFilePrefix):]
+		if len(gasSchedulePath) == 0 {
+			return scenmodel.GasScheduleDummy, "", errors.New("missing gasSchedule file path")
+		}
+		return scenmodel.GasScheduleFile, gasSchedulePath, nil
 	}
 	switch gasScheduleStr {
 	case "default":
-		return scenmodel.GasScheduleDefault, nil
+		return scenmodel.GasScheduleDefault, "", nil
 	case "dummy":
-		return scenmodel.GasScheduleDummy, nil
+		return scenmodel.GasScheduleDummy, "", nil
 	case "v3":
-		return scenmodel.GasScheduleV3, nil
+		return scenmodel.GasScheduleV3, "", nil
 	case "v4":
-		return scenmodel.GasScheduleV4, nil
+		return scenmodel.GasScheduleV4, "", nil
 	default:
-		return scenmodel.GasScheduleDummy, fmt.Errorf("invalid gasSchedule: %s", gasScheduleStr)
+		return scenmodel.GasScheduleDummy, "", fmt.Errorf("invalid gasSchedule: %s", gasScheduleStr)
+	}
+}
+
+// parseGasScheduleOverrides reads a map of gas schedule sections, each a map of entries to override.
+func (p *Parser) parseGasScheduleOverrides(value oj.OJsonObject) ([]*scenmodel.GasScheduleOverride, error) {
+	sectionsMap, isMap := value.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("gasScheduleOverrides is not a map")
+	}
+
+	var overrides []*scenmodel.GasScheduleOverride
+	for _, sectionKvp := range sectionsMap.OrderedKV {
+		entriesMap, isMap := sectionKvp.Value.(*oj.OJsonMap)
+		if !isMap {
+			return nil, fmt.Errorf("gas schedule section %s is not a map", sectionKvp.Key)
+		}
+		for _, entryKvp := range entriesMap.OrderedKV {
+			entryValue, err := p.processUint64(entryKvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid gas schedule value for %s.%s: %w", sectionKvp.Key, entryKvp.Key, err)
+			}
+			overrides = append(overrides, &scenmodel.GasScheduleOverride{
+				Section: sectionKvp.Key,
+				Name:    entryKvp.Key,
+				Value:   entryValue,
+			})
+		}
 	}
+	return overrides, nil
 }
 
+// processScenarioStepList parses the steps of a scenario, in order.
+// Steps that reference variables captured by the steps before them are deferred,
+// and parsing errors in them only show up once they are executed,
+// since their values cannot be checked before that.
 func (p *Parser) processScenarioStepList(obj interface{}) ([]scenmodel.Step, error) {
 	listRaw, listOk := obj.(*oj.OJsonList)
 	if !listOk {
@@ -93,7 +160,15 @@ func (p *Parser) processScenarioStepList(obj interface{}) ([]scenmodel.Step, err
 	}
 	var stepList []scenmodel.Step
 	for _, elemRaw := range listRaw.AsList() {
+		p.ExprInterpreter.PendingVariableReferenced = false
 		step, err := p.processScenarioStep(elemRaw)
+		if p.ExprInterpreter.PendingVariableReferenced {
+			step, err = p.deferStep(elemRaw)
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = p.declareCapturedVariables(elemRaw)
 		if err != nil {
 			return nil, err
 		}
@@ -113,6 +188,12 @@ func (p *Parser) ParseScenarioStep(jsonSnippet string) (scenmodel.Step, error) {
 	return p.processScenarioStep(jobj)
 }
 
+// ParseScenarioStepObject parses a single scenario step, from its JSON object.
+// Deferred steps are parsed again this way, once the variables they reference are known.
+func (p *Parser) ParseScenarioStepObject(stepObj oj.OJsonObject) (scenmodel.Step, error) {
+	return p.processScenarioStep(stepObj)
+}
+
 func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, error) {
 	stepMap, isStepMap := stepObj.(*oj.OJsonMap)
 	if !isStepMap {
@@ -120,6 +201,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 	}
 
 	var err error
+	position := stepPosition(stepMap)
 	stepTypeStr := ""
 	for _, kvp := range stepMap.OrderedKV {
 		if kvp.Key == "step" {
@@ -135,7 +217,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 		return nil, errors.New("no step type field provided")
 	case scenmodel.StepNameExternalSteps:
 		traceGasStatus := scenmodel.Undefined
-		step := &scenmodel.ExternalStepsStep{TraceGas: traceGasStatus}
+		step := &scenmodel.ExternalStepsStep{TraceGas: traceGasStatus, Position: position}
 		for _, kvp := range stepMap.OrderedKV {
 			switch kvp.Key {
 			case "step":
@@ -165,7 +247,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 		}
 		return step, nil
 	case scenmodel.StepNameSetState:
-		step := &scenmodel.SetStateStep{}
+		step := &scenmodel.SetStateStep{Position: position}
 		for _, kvp := range stepMap.OrderedKV {
 			switch kvp.Key {
 			case "step":
@@ -210,7 +292,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 		}
 		return step, nil
 	case scenmodel.StepNameCheckState:
-		step := &scenmodel.CheckStateStep{}
+		step := &scenmodel.CheckStateStep{Position: position}
 		for _, kvp := range stepMap.OrderedKV {
 			switch kvp.Key {
 			case "step":
@@ -225,6 +307,10 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 					return nil, fmt.Errorf("bad check state step comment: %w", err)
 				}
 			case "accounts":
+				if goldenPath, isGolden := goldenAccountsPath(kvp.Value); isGolden {
+					step.GoldenPath = goldenPath
+					continue
+				}
 				step.CheckAccounts, err = p.processCheckAccountMap(kvp.Value)
 				if err != nil {
 					return nil, fmt.Errorf("cannot parse check state step: %w", err)
@@ -235,7 +321,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 		}
 		return step, nil
 	case scenmodel.StepNameDumpState:
-		step := &scenmodel.DumpStateStep{}
+		step := &scenmodel.DumpStateStep{Position: position}
 		for _, kvp := range stepMap.OrderedKV {
 			switch kvp.Key {
 			case "step":
@@ -249,6 +335,36 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 			}
 		}
 		return step, nil
+	case scenmodel.StepNameSetGasSchedule:
+		step := &scenmodel.SetGasScheduleStep{Position: position}
+		hasGasSchedule := false
+		for _, kvp := range stepMap.OrderedKV {
+			switch kvp.Key {
+			case "step":
+			case "comment":
+				step.Comment, err = p.parseString(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad set gas schedule step comment: %w", err)
+				}
+			case "gasSchedule":
+				step.GasSchedule, step.GasSchedulePath, err = p.parseGasSchedule(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad set gas schedule step gasSchedule: %w", err)
+				}
+				hasGasSchedule = true
+			case "gasScheduleOverrides":
+				step.GasScheduleOverrides, err = p.parseGasScheduleOverrides(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad set gas schedule step gasScheduleOverrides: %w", err)
+				}
+			default:
+				return nil, fmt.Errorf("invalid set gas schedule field: %s", kvp.Key)
+			}
+		}
+		if !hasGasSchedule {
+			return nil, errors.New("missing gasSchedule in set gas schedule step")
+		}
+		return step, nil
 	case scenmodel.StepNameScCall:
 		return p.parseTxStep(scenmodel.ScCall, stepMap)
 	case scenmodel.StepNameScDeploy:
@@ -267,7 +383,7 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (scenmodel.Step, er
 }
 
 func (p *Parser) parseTxStep(txType scenmodel.TransactionType, stepMap *oj.OJsonMap) (*scenmodel.TxStep, error) {
-	step := &scenmodel.TxStep{}
+	step := &scenmodel.TxStep{Position: stepPosition(stepMap)}
 	var err error
 	for _, kvp := range stepMap.OrderedKV {
 		switch kvp.Key {
@@ -298,13 +414,25 @@ func (p *Parser) parseTxStep(txType scenmodel.TransactionType, stepMap *oj.OJson
 			if !step.Tx.Type.IsSmartContractTx() {
 				return nil, fmt.Errorf("no expected result allowed for step of type %s", step.StepTypeName())
 			}
-			step.ExpectedResult, err = p.processTxExpectedResult(kvp.Value)
+			step.ExpectedResult, err = p.processTxExpectedResult(step.Tx, kvp.Value)
 			if err != nil {
 				return nil, fmt.Errorf("cannot parse tx expected result: %w", err)
 			}
+		case "capture":
+			step.Captures, err = p.processCaptures(txType, kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad tx step capture: %w", err)
+			}
 		default:
 			return nil, fmt.Errorf("invalid tx step field: %s", kvp.Key)
 		}
 	}
 	return step, nil
 }
+
+func stepPosition(stepMap *oj.OJsonMap) scenmodel.SourcePosition {
+	return scenmodel.SourcePosition{
+		Line:   stepMap.Position.Line,
+		Column: stepMap.Position.Column,
+	}
+}
@@ -55,3 +55,63 @@ func TestParseScenario(t *testing.T) {
 	require.Equal(t, "scCall", step.StepTypeName())
 	require.Equal(t, true, step.(*scenmodel.TxStep).DisplayLogs)
 }
+
+func TestParseScenarioStepPosition(t *testing.T) {
+	contents := "{\n" +
+		"    \"steps\": [\n" +
+		"        {\n" +
+		"            \"step\": \"checkState\",\n" +
+		"            \"id\": \"check-1\"\n" +
+		"        },   {\"step\": \"dumpState\"}\n" +
+		"    ]\n" +
+		"}"
+
+	p := Parser{}
+	scenario, parseErr := p.ParseScenarioFile([]byte(contents))
+	require.Nil(t, parseErr)
+	require.Len(t, scenario.Steps, 2)
+	require.Equal(t, scenmodel.SourcePosition{Line: 3, Column: 9}, scenario.Steps[0].(*scenmodel.CheckStateStep).Position)
+	require.Equal(t, scenmodel.SourcePosition{Line: 6, Column: 14}, scenario.Steps[1].(*scenmodel.DumpStateStep).Position)
+}
+
+func TestParseCheckStateGolden(t *testing.T) {
+	snippet := `
+	{
+		"step": "checkState",
+		"accounts": "golden:state/after-deploy.json"
+	}`
+
+	p := Parser{}
+	step, parseErr := p.ParseScenarioStep(snippet)
+	require.Nil(t, parseErr)
+	checkStateStep := step.(*scenmodel.CheckStateStep)
+	require.Equal(t, "state/after-deploy.json", checkStateStep.GoldenPath)
+	require.Nil(t, checkStateStep.CheckAccounts)
+}
+
+func TestParseSetGasSchedule(t *testing.T) {
+	snippet := `
+	{
+		"step": "setGasSchedule",
+		"gasSchedule": "file:gasScheduleV8.toml",
+		"gasScheduleOverrides": {
+			"BuiltInCost": {
+				"DCDTTransfer": "500"
+			}
+		}
+	}`
+
+	p := Parser{}
+	step, parseErr := p.ParseScenarioStep(snippet)
+	require.Nil(t, parseErr)
+	setGasScheduleStep := step.(*scenmodel.SetGasScheduleStep)
+	require.Equal(t, scenmodel.GasScheduleFile, setGasScheduleStep.GasSchedule)
+	require.Equal(t, "gasScheduleV8.toml", setGasScheduleStep.GasSchedulePath)
+	require.Len(t, setGasScheduleStep.GasScheduleOverrides, 1)
+	require.Equal(t, "BuiltInCost", setGasScheduleStep.GasScheduleOverrides[0].Section)
+	require.Equal(t, "DCDTTransfer", setGasScheduleStep.GasScheduleOverrides[0].Name)
+	require.Equal(t, uint64(500), setGasScheduleStep.GasScheduleOverrides[0].Value.Value)
+
+	_, parseErr = p.ParseScenarioStep(`{"step": "setGasSchedule"}`)
+	require.EqualError(t, parseErr, "missing gasSchedule in set gas schedule step")
+}
@@ -28,6 +28,19 @@ func (p *Parser) processTx(txType scenmodel.TransactionType, blrRaw oj.OJsonObje
 	}
 
 	var err error
+	var abiArguments oj.OJsonObject
+	for _, kvp := range bltMap.OrderedKV {
+		if kvp.Key == "abi" {
+			if !txType.IsSmartContractTx() {
+				return nil, errors.New("`abi` only allowed in smart contract transactions")
+			}
+			blt.ABIFile, blt.ABI, err = p.processTxABI(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid transaction abi: %w", err)
+			}
+		}
+	}
+
 	for _, kvp := range bltMap.OrderedKV {
 
 		switch kvp.Key {
@@ -95,7 +108,13 @@ func (p *Parser) processTx(txType scenmodel.TransactionType, blrRaw oj.OJsonObje
 			if err != nil {
 				return nil, fmt.Errorf("invalid transaction dcdtValue: %w", err)
 			}
+		case "abi":
 		case "arguments":
+			if blt.ABI != nil {
+				// encoded once the function is known
+				abiArguments = kvp.Value
+				continue
+			}
 			blt.Arguments, err = p.parseSubTreeList(kvp.Value)
 			if err != nil {
 				return nil, fmt.Errorf("invalid transaction arguments: %w", err)
@@ -140,5 +159,12 @@ func (p *Parser) processTx(txType scenmodel.TransactionType, blrRaw oj.OJsonObje
 		}
 	}
 
+	if abiArguments != nil {
+		blt.Arguments, err = p.processABIArguments(&blt, abiArguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction arguments: %w", err)
+		}
+	}
+
 	return &blt, nil
 }
@@ -8,24 +8,29 @@ import (
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 )
 
-func (p *Parser) processTxExpectedResult(blrRaw oj.OJsonObject) (*scenmodel.TransactionResult, error) {
+func (p *Parser) processTxExpectedResult(tx *scenmodel.Transaction, blrRaw oj.OJsonObject) (*scenmodel.TransactionResult, error) {
 	blrMap, isMap := blrRaw.(*oj.OJsonMap)
 	if !isMap {
 		return nil, errors.New("unmarshalled block result is not a map")
 	}
 
 	blr := scenmodel.TransactionResult{
-		Status:  scenmodel.JSONCheckBigIntUnspecified(),
-		Message: scenmodel.JSONCheckBytesUnspecified(),
-		Gas:     scenmodel.JSONCheckUint64Unspecified(),
-		Refund:  scenmodel.JSONCheckBigIntUnspecified(),
-		Logs:    scenmodel.LogList{IsUnspecified: true, IsStar: true},
+		Status:          scenmodel.JSONCheckBigIntUnspecified(),
+		Message:         scenmodel.JSONCheckBytesUnspecified(),
+		Gas:             scenmodel.JSONCheckUint64Unspecified(),
+		Refund:          scenmodel.JSONCheckBigIntUnspecified(),
+		Logs:            scenmodel.LogList{IsUnspecified: true, IsStar: true},
+		OutputTransfers: scenmodel.OutputTransferList{IsUnspecified: true, IsStar: true},
 	}
 	var err error
 	for _, kvp := range blrMap.OrderedKV {
 		switch kvp.Key {
 		case "out":
-			blr.Out, err = p.parseCheckValueList(kvp.Value)
+			if tx.ABI != nil {
+				blr.Out, err = p.parseABICheckValueList(tx, kvp.Value)
+			} else {
+				blr.Out, err = p.parseCheckValueList(kvp.Value)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("invalid block result out: %w", err)
 			}
@@ -44,6 +49,16 @@ func (p *Parser) processTxExpectedResult(blrRaw oj.OJsonObject) (*scenmodel.Tran
 			if err != nil {
 				return nil, err
 			}
+		case "outputTransfers":
+			blr.OutputTransfers, err = p.processOutputTransferList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid block result output transfers: %w", err)
+			}
+		case "stateUnchanged":
+			blr.StateUnchanged, err = p.parseBool(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid block result stateUnchanged flag: %w", err)
+			}
 		case "gas":
 			blr.Gas, err = p.processCheckUint64(kvp.Value)
 			if err != nil {
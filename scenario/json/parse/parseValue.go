@@ -25,6 +25,16 @@ func (p *Parser) processCheckBigInt(obj oj.OJsonObject, format bigIntParseFormat
 			Original: "*"}, nil
 	}
 
+	op, err := p.parseCheckOperator(obj, format)
+	if err != nil {
+		return scenmodel.JSONCheckBigInt{}, err
+	}
+	if op != nil {
+		return scenmodel.JSONCheckBigInt{
+			Operator: op,
+			Original: obj.(*oj.OJsonString).Value}, nil
+	}
+
 	jbi, err := p.processBigInt(obj, format)
 	if err != nil {
 		return scenmodel.JSONCheckBigInt{}, err
@@ -73,6 +83,16 @@ func (p *Parser) processCheckUint64(obj oj.OJsonObject) (scenmodel.JSONCheckUint
 			Original: "*"}, nil
 	}
 
+	op, err := p.parseCheckOperator(obj, bigIntUnsignedBytes)
+	if err != nil {
+		return scenmodel.JSONCheckUint64{}, err
+	}
+	if op != nil {
+		return scenmodel.JSONCheckUint64{
+			Operator: op,
+			Original: obj.(*oj.OJsonString).Value}, nil
+	}
+
 	ju, err := p.processUint64(obj)
 	if err != nil {
 		return scenmodel.JSONCheckUint64{}, err
@@ -105,6 +125,18 @@ func (p *Parser) parseCheckBytes(obj oj.OJsonObject) (scenmodel.JSONCheckBytes,
 		return scenmodel.JSONCheckBytesStar(), nil
 	}
 
+	op, err := p.parseCheckBytesOperator(obj)
+	if err != nil {
+		return scenmodel.JSONCheckBytes{}, err
+	}
+	if op != nil {
+		return scenmodel.JSONCheckBytes{
+			Value:    []byte{},
+			Operator: op,
+			Original: obj,
+		}, nil
+	}
+
 	jb, err := p.processSubTreeAsByteArray(obj)
 	if err != nil {
 		return scenmodel.JSONCheckBytes{}, err
@@ -59,3 +59,120 @@ func TestParseBool(t *testing.T) {
 	_, err = p.parseBool(nil)
 	require.NotNil(t, err)
 }
+
+func TestCheckBigIntArithmetic(t *testing.T) {
+	p := Parser{}
+	result, err := p.processCheckBigInt(&oj.OJsonString{Value: "rewa:5 - 1000"}, bigIntUnsignedBytes)
+	require.Nil(t, err)
+	require.Equal(t, "rewa:5 - 1000", result.Original)
+	require.Equal(t, big.NewInt(4999999999999999000), result.Value)
+
+	result, err = p.processCheckBigInt(&oj.OJsonString{Value: "*"}, bigIntUnsignedBytes)
+	require.Nil(t, err)
+	require.True(t, result.IsStar)
+}
+
+func TestCheckOperators(t *testing.T) {
+	p := Parser{}
+
+	testCases := []struct {
+		check string
+		holds []int64
+		fails []int64
+	}{
+		{">=1000", []int64{1000, 1001}, []int64{999}},
+		{">1000", []int64{1001}, []int64{1000}},
+		{"<=5", []int64{5, 0}, []int64{6}},
+		{"<5", []int64{4}, []int64{5}},
+		{"!=0", []int64{1}, []int64{0}},
+		{"1000..2000", []int64{1000, 1500, 2000}, []int64{999, 2001}},
+		{"1000 .. 1000 + 1", []int64{1000, 1001}, []int64{1002}},
+		{"approx:1000±1%", []int64{990, 1010}, []int64{989, 1011}},
+		{"approx:1000+-5", []int64{995, 1005}, []int64{994, 1006}},
+		{"approx:1000±0.5%", []int64{995}, []int64{994}},
+		{"<-1", []int64{-2}, []int64{-1}},
+		{"regex:^1[0-9]$", []int64{10, 19}, []int64{1, 20}},
+	}
+	for _, testCase := range testCases {
+		check, err := p.processCheckBigInt(&oj.OJsonString{Value: testCase.check}, bigIntSignedBytes)
+		require.Nil(t, err, testCase.check)
+		require.NotNil(t, check.Operator, testCase.check)
+		require.Equal(t, testCase.check, check.Original)
+		for _, value := range testCase.holds {
+			require.True(t, check.Check(big.NewInt(value)), "%s %d", testCase.check, value)
+		}
+		for _, value := range testCase.fails {
+			require.False(t, check.Check(big.NewInt(value)), "%s %d", testCase.check, value)
+		}
+	}
+
+	// bytes are compared as unsigned numbers, or matched as text
+	checkBytes, err := p.parseCheckBytes(&oj.OJsonString{Value: ">0x7f"})
+	require.Nil(t, err)
+	require.True(t, checkBytes.Check([]byte{0x80}))
+	checkBytes, err = p.parseCheckBytes(&oj.OJsonString{Value: "regex:^ERR_.*"})
+	require.Nil(t, err)
+	require.True(t, checkBytes.Check([]byte("ERR_FAILED")))
+	require.False(t, checkBytes.Check([]byte("OK")))
+
+	// ".." in text or paths is not a range
+	checkBytes, err = p.parseCheckBytes(&oj.OJsonString{Value: "str:a..b"})
+	require.Nil(t, err)
+	require.Nil(t, checkBytes.Operator)
+	require.True(t, checkBytes.Check([]byte("a..b")))
+
+	checkUint64, err := p.processCheckUint64(&oj.OJsonString{Value: "1..3"})
+	require.Nil(t, err)
+	require.True(t, checkUint64.Check(3))
+	require.False(t, checkUint64.Check(4))
+	require.True(t, checkUint64.CheckBool(true))
+}
+
+func TestPartialMatchers(t *testing.T) {
+	p := Parser{}
+
+	testCases := []struct {
+		check string
+		holds []string
+		fails []string
+	}{
+		{"prefix:str:ERR_", []string{"ERR_", "ERR_X"}, []string{"ERR", "X_ERR_"}},
+		{"suffix:u16:5", []string{"\x00\x05", "a\x00\x05"}, []string{"\x05", "\x00\x05a"}},
+		{"contains:str:b", []string{"abc", "b"}, []string{"", "ac"}},
+		{"mask:str:a|any:2|str:d", []string{"abcd", "a\x00\x00d"}, []string{"abd", "abcde", "bbcd", "abce"}},
+	}
+	for _, testCase := range testCases {
+		check, err := p.parseCheckBytes(&oj.OJsonString{Value: testCase.check})
+		require.Nil(t, err, testCase.check)
+		require.NotNil(t, check.Operator, testCase.check)
+		for _, value := range testCase.holds {
+			require.True(t, check.Check([]byte(value)), "%s %q", testCase.check, value)
+		}
+		for _, value := range testCase.fails {
+			require.False(t, check.Check([]byte(value)), "%s %q", testCase.check, value)
+		}
+	}
+
+	_, err := p.parseCheckBytes(&oj.OJsonString{Value: "mask:str:a|any:x"})
+	require.EqualError(t, err, `invalid check operator "mask:str:a|any:x": invalid masked length x`)
+
+	// partial matchers only apply to bytes
+	_, err = p.processCheckBigInt(&oj.OJsonString{Value: "prefix:1"}, bigIntUnsignedBytes)
+	require.NotNil(t, err)
+}
+
+func TestCheckValueListMore(t *testing.T) {
+	p := Parser{}
+	list := oj.OJsonList{Items: []oj.OJsonObject{&oj.OJsonString{Value: "1"}, &oj.OJsonString{Value: "..."}}}
+	checkList, err := p.parseCheckValueList(&list)
+	require.Nil(t, err)
+	require.True(t, checkList.MoreAllowed)
+	require.True(t, checkList.CheckList([][]byte{{1}}))
+	require.True(t, checkList.CheckList([][]byte{{1}, {2}, {3}}))
+	require.False(t, checkList.CheckList([][]byte{}))
+	require.False(t, checkList.CheckList([][]byte{{2}, {2}}))
+
+	list = oj.OJsonList{Items: []oj.OJsonObject{&oj.OJsonString{Value: "..."}, &oj.OJsonString{Value: "1"}}}
+	_, err = p.parseCheckValueList(&list)
+	require.EqualError(t, err, `"..." is only allowed as the last value`)
+}
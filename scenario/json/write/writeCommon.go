@@ -26,12 +26,18 @@ func resultToOJ(res *scenmodel.TransactionResult) oj.OJsonObject {
 
 		}
 	}
+	if !res.OutputTransfers.IsUnspecified {
+		resultOJ.Put("outputTransfers", outputTransfersToOJ(res.OutputTransfers))
+	}
 	if !res.Gas.IsUnspecified() {
 		resultOJ.Put("gas", checkUint64ToOJ(res.Gas))
 	}
 	if !res.Refund.IsUnspecified() {
 		resultOJ.Put("refund", checkBigIntToOJ(res.Refund))
 	}
+	if res.StateUnchanged {
+		resultOJ.Put("stateUnchanged", boolToOJ(true))
+	}
 
 	return resultOJ
 }
@@ -61,8 +67,25 @@ func logsToOJ(logEntries scenmodel.LogList) oj.OJsonObject {
 	if logEntries.MoreAllowedAtEnd {
 		logList = append(logList, stringToOJ("+"))
 	}
-	logOJList := oj.OJsonList(logList)
-	return &logOJList
+	logOJList := oj.OJsonList{Items: logList}
+	if !logEntries.HasOptions() {
+		return &logOJList
+	}
+
+	logsOJ := oj.NewMap()
+	if logEntries.Unordered {
+		logsOJ.Put("unordered", boolToOJ(true))
+	}
+	if logEntries.IgnoredIdentifiers != nil {
+		var ignoredList []oj.OJsonObject
+		for _, identifier := range logEntries.IgnoredIdentifiers {
+			ignoredList = append(ignoredList, checkBytesToOJ(identifier))
+		}
+		ignoredOJList := oj.OJsonList{Items: ignoredList}
+		logsOJ.Put("ignore", &ignoredOJList)
+	}
+	logsOJ.Put("list", &logOJList)
+	return logsOJ
 }
 
 func bigIntToOJ(i scenmodel.JSONBigInt) oj.OJsonObject {
@@ -103,7 +126,7 @@ func valueListToOJ(jsonBytesList scenmodel.JSONValueList) oj.OJsonObject {
 	for _, blh := range jsonBytesList.Values {
 		valuesList = append(valuesList, bytesFromStringToOJ(blh))
 	}
-	ojList := oj.OJsonList(valuesList)
+	ojList := oj.OJsonList{Items: valuesList}
 	return &ojList
 }
 
@@ -116,7 +139,10 @@ func checkValueListToOJ(jcbl scenmodel.JSONCheckValueList) oj.OJsonObject {
 	for _, jcb := range jcbl.Values {
 		valuesList = append(valuesList, checkBytesToOJ(jcb))
 	}
-	ojList := oj.OJsonList(valuesList)
+	if jcbl.MoreAllowed {
+		valuesList = append(valuesList, &oj.OJsonString{Value: scenmodel.JSONCheckValueListMore})
+	}
+	ojList := oj.OJsonList{Items: valuesList}
 	return &ojList
 }
 
@@ -132,6 +158,15 @@ func stringToOJ(str string) oj.OJsonObject {
 	return &oj.OJsonString{Value: str}
 }
 
+func stringListToOJ(strs []string) oj.OJsonObject {
+	var strList []oj.OJsonObject
+	for _, str := range strs {
+		strList = append(strList, stringToOJ(str))
+	}
+	ojList := oj.OJsonList{Items: strList}
+	return &ojList
+}
+
 func boolToOJ(val bool) oj.OJsonObject {
 	obj := oj.OJsonBool(val)
 	return &obj
@@ -9,7 +9,7 @@ func dcdtTxDataToOJ(dcdtItems []*scenmodel.DCDTTxData) oj.OJsonObject {
 	dcdtItemList := oj.OJsonList{}
 	for _, dcdtItemRaw := range dcdtItems {
 		dcdtItemOJ := dcdtTxRawEntryToOJ(dcdtItemRaw)
-		dcdtItemList = append(dcdtItemList, dcdtItemOJ)
+		dcdtItemList.Items = append(dcdtItemList.Items, dcdtItemOJ)
 	}
 
 	return &dcdtItemList
@@ -55,7 +55,7 @@ func dcdtItemToOJ(dcdtItem *scenmodel.DCDTData) oj.OJsonObject {
 			appendDCDTInstanceToOJ(dcdtInstance, dcdtInstanceOJ)
 			convertedList = append(convertedList, dcdtInstanceOJ)
 		}
-		instancesOJList := oj.OJsonList(convertedList)
+		instancesOJList := oj.OJsonList{Items: convertedList}
 		dcdtItemOJ.Put("instances", &instancesOJList)
 	}
 
@@ -69,7 +69,7 @@ func dcdtItemToOJ(dcdtItem *scenmodel.DCDTData) oj.OJsonObject {
 		for _, roleStr := range dcdtItem.Roles {
 			convertedList = append(convertedList, &oj.OJsonString{Value: roleStr})
 		}
-		rolesOJList := oj.OJsonList(convertedList)
+		rolesOJList := oj.OJsonList{Items: convertedList}
 		dcdtItemOJ.Put("roles", &rolesOJList)
 	}
 	if len(dcdtItem.Frozen.Original) > 0 {
@@ -31,7 +31,7 @@ func checkDCDTItemToOJ(dcdtItem *scenmodel.CheckDCDTData) oj.OJsonObject {
 			appendCheckDCDTInstanceToOJ(dcdtInstance, dcdtInstanceOJ)
 			convertedList = append(convertedList, dcdtInstanceOJ)
 		}
-		instancesOJList := oj.OJsonList(convertedList)
+		instancesOJList := oj.OJsonList{Items: convertedList}
 		dcdtItemOJ.Put("instances", &instancesOJList)
 	}
 
@@ -45,7 +45,7 @@ func checkDCDTItemToOJ(dcdtItem *scenmodel.CheckDCDTData) oj.OJsonObject {
 		for _, roleStr := range dcdtItem.Roles {
 			convertedList = append(convertedList, &oj.OJsonString{Value: roleStr})
 		}
-		rolesOJList := oj.OJsonList(convertedList)
+		rolesOJList := oj.OJsonList{Items: convertedList}
 		dcdtItemOJ.Put("roles", &rolesOJList)
 	}
 	if len(dcdtItem.Frozen.Original) > 0 {
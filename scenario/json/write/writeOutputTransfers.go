@@ -0,0 +1,68 @@
+package scenjsonwrite
+
+import (
+	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
+)
+
+// OutputTransferToString returns a json representation of an output transfer check, used in error messages.
+func OutputTransferToString(transfer *scenmodel.CheckOutputTransfer) string {
+	return oj.JSONString(outputTransferToOJ(transfer))
+}
+
+func outputTransfersToOJ(transfers scenmodel.OutputTransferList) oj.OJsonObject {
+	if transfers.IsStar {
+		return stringToOJ("*")
+	}
+
+	var transferList []oj.OJsonObject
+	for _, transfer := range transfers.List {
+		transferList = append(transferList, outputTransferToOJ(transfer))
+	}
+	if transfers.MoreAllowedAtEnd {
+		transferList = append(transferList, stringToOJ("+"))
+	}
+	transferOJList := oj.OJsonList{Items: transferList}
+	return &transferOJList
+}
+
+func outputTransferToOJ(transfer *scenmodel.CheckOutputTransfer) oj.OJsonObject {
+	transferOJ := oj.NewMap()
+	if !transfer.From.IsUnspecified() {
+		transferOJ.Put("from", checkBytesToOJ(transfer.From))
+	}
+	if !transfer.To.IsUnspecified() {
+		transferOJ.Put("to", checkBytesToOJ(transfer.To))
+	}
+	if !transfer.Value.IsUnspecified() {
+		transferOJ.Put("value", checkBigIntToOJ(transfer.Value))
+	}
+	if transfer.DCDT != nil {
+		var dcdtList []oj.OJsonObject
+		for _, dcdtTransfer := range transfer.DCDT {
+			dcdtOJ := oj.NewMap()
+			if !dcdtTransfer.TokenIdentifier.IsUnspecified() {
+				dcdtOJ.Put("tokenIdentifier", checkBytesToOJ(dcdtTransfer.TokenIdentifier))
+			}
+			if !dcdtTransfer.Nonce.IsUnspecified() {
+				dcdtOJ.Put("nonce", checkUint64ToOJ(dcdtTransfer.Nonce))
+			}
+			if !dcdtTransfer.Value.IsUnspecified() {
+				dcdtOJ.Put("value", checkBigIntToOJ(dcdtTransfer.Value))
+			}
+			dcdtList = append(dcdtList, dcdtOJ)
+		}
+		dcdtOJList := oj.OJsonList{Items: dcdtList}
+		transferOJ.Put("dcdt", &dcdtOJList)
+	}
+	if !transfer.Data.IsUnspecified() {
+		transferOJ.Put("data", checkBytesToOJ(transfer.Data))
+	}
+	if !transfer.GasLimit.IsUnspecified() {
+		transferOJ.Put("gasLimit", checkUint64ToOJ(transfer.GasLimit))
+	}
+	if len(transfer.CallType) > 0 {
+		transferOJ.Put("callType", stringToOJ(transfer.CallType))
+	}
+	return transferOJ
+}
@@ -1,6 +1,8 @@
 package scenjsonwrite
 
 import (
+	"fmt"
+
 	oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
 	scenmodel "github.com/kalyan3104/k-chain-scenario-go/scenario/model"
 )
@@ -23,6 +25,10 @@ func ScenarioToOrderedJSON(scenario *scenmodel.Scenario) oj.OJsonObject {
 		scenarioOJ.Put("comment", stringToOJ(scenario.Comment))
 	}
 
+	if len(scenario.Tags) > 0 {
+		scenarioOJ.Put("tags", stringListToOJ(scenario.Tags))
+	}
+
 	if !scenario.CheckGas {
 		ojFalse := oj.OJsonBool(false)
 		scenarioOJ.Put("checkGas", &ojFalse)
@@ -34,12 +40,29 @@ func ScenarioToOrderedJSON(scenario *scenmodel.Scenario) oj.OJsonObject {
 	}
 
 	if scenario.GasSchedule != scenmodel.GasScheduleDefault {
-		scenarioOJ.Put("gasSchedule", gasScheduleToOJ(scenario.GasSchedule))
+		scenarioOJ.Put("gasSchedule", gasScheduleToOJ(scenario.GasSchedule, scenario.GasSchedulePath))
+	}
+
+	if len(scenario.GasScheduleOverrides) > 0 {
+		scenarioOJ.Put("gasScheduleOverrides", gasScheduleOverridesToOJ(scenario.GasScheduleOverrides))
+	}
+
+	if len(scenario.ConstantImports) > 0 {
+		scenarioOJ.Put("importConstants", stringListToOJ(scenario.ConstantImports))
+	}
+
+	if len(scenario.Constants) > 0 {
+		scenarioOJ.Put("constants", constantsToOJ(scenario.Constants))
 	}
 
 	var stepOJList []oj.OJsonObject
 
 	for _, generalStep := range scenario.Steps {
+		if deferredStep, isDeferred := generalStep.(*scenmodel.DeferredStep); isDeferred {
+			stepOJList = append(stepOJList, deferredStep.JSON)
+			continue
+		}
+
 		stepOJ := oj.NewMap()
 		stepOJ.Put("step", stringToOJ(generalStep.StepTypeName()))
 		switch step := generalStep.(type) {
@@ -77,11 +100,23 @@ func ScenarioToOrderedJSON(scenario *scenmodel.Scenario) oj.OJsonObject {
 			if len(step.Comment) > 0 {
 				stepOJ.Put("comment", stringToOJ(step.Comment))
 			}
-			stepOJ.Put("accounts", checkAccountsToOJ(step.CheckAccounts))
+			if len(step.GoldenPath) > 0 {
+				stepOJ.Put("accounts", stringToOJ(scenmodel.GoldenAccountsPrefix+step.GoldenPath))
+			} else {
+				stepOJ.Put("accounts", checkAccountsToOJ(step.CheckAccounts))
+			}
 		case *scenmodel.DumpStateStep:
 			if len(step.Comment) > 0 {
 				stepOJ.Put("comment", stringToOJ(step.Comment))
 			}
+		case *scenmodel.SetGasScheduleStep:
+			if len(step.Comment) > 0 {
+				stepOJ.Put("comment", stringToOJ(step.Comment))
+			}
+			stepOJ.Put("gasSchedule", gasScheduleToOJ(step.GasSchedule, step.GasSchedulePath))
+			if len(step.GasScheduleOverrides) > 0 {
+				stepOJ.Put("gasScheduleOverrides", gasScheduleOverridesToOJ(step.GasScheduleOverrides))
+			}
 		case *scenmodel.TxStep:
 			if len(step.TxIdent) > 0 {
 				stepOJ.Put("id", stringToOJ(step.TxIdent))
@@ -96,12 +131,15 @@ func ScenarioToOrderedJSON(scenario *scenmodel.Scenario) oj.OJsonObject {
 			if step.Tx.Type.IsSmartContractTx() && step.ExpectedResult != nil {
 				stepOJ.Put("expect", resultToOJ(step.ExpectedResult))
 			}
+			if len(step.Captures) > 0 {
+				stepOJ.Put("capture", capturesToOJ(step.Captures))
+			}
 		}
 
 		stepOJList = append(stepOJList, stepOJ)
 	}
 
-	stepsOJ := oj.OJsonList(stepOJList)
+	stepsOJ := oj.OJsonList{Items: stepOJList}
 	scenarioOJ.Put("steps", &stepsOJ)
 
 	return scenarioOJ
@@ -128,13 +166,16 @@ func transactionToScenarioOJ(tx *scenmodel.Transaction) oj.OJsonObject {
 	if tx.Type == scenmodel.ScDeploy || tx.Type == scenmodel.ScUpgrade {
 		transactionOJ.Put("contractCode", bytesFromStringToOJ(tx.Code))
 	}
+	if tx.ABI != nil {
+		transactionOJ.Put("abi", bytesFromStringToOJ(tx.ABIFile))
+	}
 
 	if tx.Type.HasFunction() || tx.Type == scenmodel.ScDeploy {
 		var argList []oj.OJsonObject
 		for _, arg := range tx.Arguments {
 			argList = append(argList, bytesFromTreeToOJ(arg))
 		}
-		argOJ := oj.OJsonList(argList)
+		argOJ := oj.OJsonList{Items: argList}
 		transactionOJ.Put("arguments", &argOJ)
 	}
 
@@ -158,7 +199,7 @@ func newAddressMocksToOJ(newAddressMocks []*scenmodel.NewAddressMock) oj.OJsonOb
 		namOJ.Put("newAddress", bytesFromStringToOJ(namEntry.NewAddress))
 		namList = append(namList, namOJ)
 	}
-	namOJList := oj.OJsonList(namList)
+	namOJList := oj.OJsonList{Items: namList}
 	return &namOJList
 }
 
@@ -183,7 +224,7 @@ func blockInfoToOJ(blockInfo *scenmodel.BlockInfo) oj.OJsonObject {
 	return blockInfoOJ
 }
 
-func gasScheduleToOJ(gasSchedule scenmodel.GasSchedule) oj.OJsonObject {
+func gasScheduleToOJ(gasSchedule scenmodel.GasSchedule, gasSchedulePath string) oj.OJsonObject {
 	switch gasSchedule {
 	case scenmodel.GasScheduleDefault:
 		return stringToOJ("default")
@@ -193,7 +234,44 @@ func gasScheduleToOJ(gasSchedule scenmodel.GasSchedule) oj.OJsonObject {
 		return stringToOJ("v3")
 	case scenmodel.GasScheduleV4:
 		return stringToOJ("v4")
+	case scenmodel.GasScheduleFile:
+		return stringToOJ(scenmodel.GasScheduleFilePrefix + gasSchedulePath)
 	default:
 		return stringToOJ("")
 	}
 }
+
+func gasScheduleOverridesToOJ(overrides []*scenmodel.GasScheduleOverride) oj.OJsonObject {
+	sectionsOJ := oj.NewMap()
+	sectionOJs := make(map[string]*oj.OJsonMap)
+	for _, override := range overrides {
+		sectionOJ, found := sectionOJs[override.Section]
+		if !found {
+			sectionOJ = oj.NewMap()
+			sectionOJs[override.Section] = sectionOJ
+			sectionsOJ.Put(override.Section, sectionOJ)
+		}
+		sectionOJ.Put(override.Name, uint64ToOJ(override.Value))
+	}
+	return sectionsOJ
+}
+
+func capturesToOJ(captures []*scenmodel.Capture) oj.OJsonObject {
+	capturesOJ := oj.NewMap()
+	for _, capture := range captures {
+		source := scenmodel.CaptureAddressKey
+		if capture.Source == scenmodel.CaptureOut {
+			source = fmt.Sprintf("%s[%d]", scenmodel.CaptureOutPrefix, capture.OutIndex)
+		}
+		capturesOJ.Put(source, stringToOJ("$"+capture.Variable))
+	}
+	return capturesOJ
+}
+
+func constantsToOJ(constants []*scenmodel.Constant) oj.OJsonObject {
+	constantsOJ := oj.NewMap()
+	for _, constant := range constants {
+		constantsOJ.Put(constant.Name, bytesFromTreeToOJ(constant.Value))
+	}
+	return constantsOJ
+}
@@ -0,0 +1,93 @@
+package scenmodel
+
+// ABI describes the endpoints of a contract, and the types of their arguments and results.
+// It is loaded from the contract's .abi.json file.
+type ABI struct {
+	Name               string                         `json:"name"`
+	Constructor        *ABIEndpoint                   `json:"constructor"`
+	UpgradeConstructor *ABIEndpoint                   `json:"upgradeConstructor"`
+	Endpoints          []*ABIEndpoint                 `json:"endpoints"`
+	Events             []*ABIEvent                    `json:"events"`
+	Types              map[string]*ABITypeDescription `json:"types"`
+
+	// Storage annotates the storage of the contract, with the mappers that use each storage key name,
+	// e.g. "SingleValueMapper<BigUint>" or "VecMapper<Item>". Not part of the generated ABI, it is added by hand.
+	Storage map[string]string `json:"storage"`
+}
+
+// ABIEndpoint is a contract endpoint, or constructor, in the ABI.
+type ABIEndpoint struct {
+	Name    string      `json:"name"`
+	Inputs  []*ABIParam `json:"inputs"`
+	Outputs []*ABIParam `json:"outputs"`
+}
+
+// ABIParam is an endpoint argument or result.
+// MultiArg marks variadic and optional arguments, which take up any number of raw arguments.
+type ABIParam struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	MultiArg bool   `json:"multi_arg"`
+}
+
+// ABIEvent is an event that the contract logs.
+// The identifier is the first topic, followed by the indexed inputs, the other inputs are in the log data.
+type ABIEvent struct {
+	Identifier string           `json:"identifier"`
+	Inputs     []*ABIEventInput `json:"inputs"`
+}
+
+// ABIEventInput is one of the values logged by an event.
+type ABIEventInput struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed"`
+}
+
+// ABITypeDescription describes a custom type, either a struct or an enum.
+type ABITypeDescription struct {
+	Type     string            `json:"type"`
+	Fields   []*ABIField       `json:"fields"`
+	Variants []*ABIEnumVariant `json:"variants"`
+}
+
+// ABIField is a struct field, or an enum variant field.
+type ABIField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ABIEnumVariant is one of the variants of an enum.
+type ABIEnumVariant struct {
+	Name         string      `json:"name"`
+	Discriminant int         `json:"discriminant"`
+	Fields       []*ABIField `json:"fields"`
+}
+
+// ABIStructType and ABIEnumType are the kinds of custom types in the ABI.
+const (
+	ABIStructType = "struct"
+	ABIEnumType   = "enum"
+)
+
+// EndpointFor yields the endpoint a transaction calls:
+// the constructor for deploys, the upgrade constructor for upgrades (or the constructor if there is none),
+// and the endpoint with the given function name otherwise.
+// Returns nil if the ABI does not describe it.
+func (abi *ABI) EndpointFor(txType TransactionType, function string) *ABIEndpoint {
+	switch txType {
+	case ScDeploy:
+		return abi.Constructor
+	case ScUpgrade:
+		if abi.UpgradeConstructor != nil {
+			return abi.UpgradeConstructor
+		}
+		return abi.Constructor
+	}
+	for _, endpoint := range abi.Endpoints {
+		if endpoint.Name == function {
+			return endpoint
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package scenmodel
+
+// CaptureSource is the part of the output of a transaction that a value is captured from.
+type CaptureSource int
+
+const (
+	// CaptureOut is one of the values returned by the transaction, "out[N]".
+	CaptureOut CaptureSource = iota
+
+	// CaptureAddress is the address of the contract deployed by the transaction, "address".
+	CaptureAddress
+)
+
+// CaptureAddressKey and CaptureOutPrefix are how capture sources appear in scenarios.
+const (
+	CaptureAddressKey = "address"
+	CaptureOutPrefix  = "out"
+)
+
+// Capture saves a value from the output of a transaction into a variable,
+// which the following steps reference like a constant, as "$NAME" or "${NAME}".
+type Capture struct {
+	Source   CaptureSource
+	OutIndex int
+	Variable string
+}
@@ -0,0 +1,126 @@
+package scenmodel
+
+import (
+	"bytes"
+	"math/big"
+	"regexp"
+)
+
+// CheckOperatorKind is the kind of condition that a check operator expresses.
+type CheckOperatorKind int
+
+const (
+	// CheckGreaterOrEqual is written ">=1000".
+	CheckGreaterOrEqual CheckOperatorKind = iota
+
+	// CheckGreater is written ">1000".
+	CheckGreater
+
+	// CheckLessOrEqual is written "<=1000".
+	CheckLessOrEqual
+
+	// CheckLess is written "<1000".
+	CheckLess
+
+	// CheckNotEqual is written "!=1000".
+	CheckNotEqual
+
+	// CheckRange is written "1000..2000", both bounds are included.
+	CheckRange
+
+	// CheckApprox is written "approx:1000±1%", or "approx:1000±10" for an absolute tolerance.
+	CheckApprox
+
+	// CheckRegex is written "regex:^ERR_.*".
+	CheckRegex
+
+	// CheckPrefix is written "prefix:str:ERR", it only applies to bytes.
+	CheckPrefix
+
+	// CheckSuffix is written "suffix:u32:5", it only applies to bytes.
+	CheckSuffix
+
+	// CheckContains is written "contains:str:abc", it only applies to bytes.
+	CheckContains
+
+	// CheckMask is written "mask:u32:5|any:8|nested:str:abc", it only applies to bytes.
+	// The value has the length of the pattern, and equals it except for the "any:N" bytes.
+	CheckMask
+)
+
+// CheckOperator is a condition on a checked value, other than equality.
+// Numeric conditions compare the value as a number, bytes are taken as an unsigned big endian number.
+// The regex condition matches bytes as text, and numbers in decimal.
+// The partial matchers compare bytes with a pattern.
+type CheckOperator struct {
+	Kind CheckOperatorKind
+
+	// Bound is the compared value, the lower bound of a range, or the center of an approximation.
+	Bound *big.Int
+
+	// UpperBound is the upper bound of a range.
+	UpperBound *big.Int
+
+	// Tolerance is the largest accepted distance from the center of an approximation.
+	Tolerance *big.Rat
+
+	Regex *regexp.Regexp
+
+	// Pattern is the value that partial matchers look for.
+	Pattern []byte
+
+	// Masked marks the bytes of a mask pattern that can have any value.
+	Masked []bool
+}
+
+// CheckBigInt returns true if the condition holds for a number.
+func (op *CheckOperator) CheckBigInt(value *big.Int) bool {
+	switch op.Kind {
+	case CheckGreaterOrEqual:
+		return value.Cmp(op.Bound) >= 0
+	case CheckGreater:
+		return value.Cmp(op.Bound) > 0
+	case CheckLessOrEqual:
+		return value.Cmp(op.Bound) <= 0
+	case CheckLess:
+		return value.Cmp(op.Bound) < 0
+	case CheckNotEqual:
+		return value.Cmp(op.Bound) != 0
+	case CheckRange:
+		return value.Cmp(op.Bound) >= 0 && value.Cmp(op.UpperBound) <= 0
+	case CheckApprox:
+		distance := big.NewInt(0).Sub(value, op.Bound)
+		distance.Abs(distance)
+		return big.NewRat(0, 1).SetInt(distance).Cmp(op.Tolerance) <= 0
+	case CheckRegex:
+		return op.Regex.MatchString(value.String())
+	default:
+		return false
+	}
+}
+
+// CheckBytes returns true if the condition holds for a byte slice.
+func (op *CheckOperator) CheckBytes(value []byte) bool {
+	switch op.Kind {
+	case CheckRegex:
+		return op.Regex.Match(value)
+	case CheckPrefix:
+		return bytes.HasPrefix(value, op.Pattern)
+	case CheckSuffix:
+		return bytes.HasSuffix(value, op.Pattern)
+	case CheckContains:
+		return bytes.Contains(value, op.Pattern)
+	case CheckMask:
+		if len(value) != len(op.Pattern) {
+			return false
+		}
+		for i, b := range value {
+			if !op.Masked[i] && b != op.Pattern[i] {
+				return false
+			}
+		}
+		return true
+	default:
+		return op.CheckBigInt(big.NewInt(0).SetBytes(value))
+	}
+}
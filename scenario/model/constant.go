@@ -0,0 +1,8 @@
+package scenmodel
+
+// Constant is a named value, defined at the top of a scenario,
+// which any value expression can reference as "$NAME" or "${NAME}".
+type Constant struct {
+	Name  string
+	Value JSONBytesFromTree
+}
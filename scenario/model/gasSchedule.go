@@ -16,4 +16,17 @@ const (
 
 	// GasScheduleV4 is currently used on mainnet.
 	GasScheduleV4
+
+	// GasScheduleFile is loaded from a TOML or JSON file, given in the scenario.
+	GasScheduleFile
 )
+
+// GasScheduleFilePrefix marks a gasSchedule value that is a path to a gas schedule file.
+const GasScheduleFilePrefix = "file:"
+
+// GasScheduleOverride replaces a single entry of the gas schedule, e.g. BuiltInCost.DCDTTransfer.
+type GasScheduleOverride struct {
+	Section string
+	Name    string
+	Value   JSONUint64
+}
@@ -0,0 +1,41 @@
+package scenmodel
+
+// OutputTransferList holds the expected transfers and calls sent out by contracts during a transaction,
+// e.g. async calls, callbacks and transfers to other addresses.
+type OutputTransferList struct {
+	IsUnspecified    bool
+	IsStar           bool
+	MoreAllowedAtEnd bool
+	List             []*CheckOutputTransfer
+}
+
+// CheckOutputTransfer checks a transfer or call sent out by a contract.
+// Unspecified fields are not checked.
+// The DCDT transfers are decoded from the call data, as are the actual recipients of NFT and multi-transfers.
+type CheckOutputTransfer struct {
+	From     JSONCheckBytes
+	To       JSONCheckBytes
+	Value    JSONCheckBigInt
+	DCDT     []*CheckDCDTTransfer
+	Data     JSONCheckBytes
+	GasLimit JSONCheckUint64
+	CallType string
+}
+
+// NewCheckOutputTransfer creates an output transfer check with all fields unspecified.
+func NewCheckOutputTransfer() *CheckOutputTransfer {
+	return &CheckOutputTransfer{
+		From:     JSONCheckBytesUnspecified(),
+		To:       JSONCheckBytesUnspecified(),
+		Value:    JSONCheckBigIntUnspecified(),
+		Data:     JSONCheckBytesUnspecified(),
+		GasLimit: JSONCheckUint64Unspecified(),
+	}
+}
+
+// CheckDCDTTransfer checks one of the DCDT tokens sent in an output transfer.
+type CheckDCDTTransfer struct {
+	TokenIdentifier JSONCheckBytes
+	Nonce           JSONCheckUint64
+	Value           JSONCheckBigInt
+}
@@ -1,14 +1,39 @@
 package scenmodel
 
+import oj "github.com/kalyan3104/k-chain-scenario-go/orderedjson"
+
 // Scenario is a json object representing a test scenario with steps.
 type Scenario struct {
 	Name        string
 	Comment     string
+	Tags        []string
 	CheckGas    bool
 	TraceGas    bool
 	IsNewTest   bool
 	GasSchedule GasSchedule
 	Steps       []Step
+
+	// GasSchedulePath is the file the gas schedule is loaded from, for GasScheduleFile.
+	GasSchedulePath string
+
+	// GasScheduleOverrides replace entries of the gas schedule, whichever it is.
+	GasScheduleOverrides []*GasScheduleOverride
+
+	// ConstantImports are the files whose constants are imported, before the scenario defines its own.
+	ConstantImports []string
+
+	// Constants are the constants defined by the scenario, in order.
+	Constants []*Constant
+
+	// ConstantValues are the values of all the constants visible in the scenario, by name:
+	// inherited from the scenario running it as external steps, imported, and its own.
+	ConstantValues map[string][]byte
+
+	// NegativeConstants are the names of the constants in ConstantValues whose value is a negative number.
+	NegativeConstants map[string]bool
+
+	// Path is the absolute path of the file the scenario was read from, if any.
+	Path string
 }
 
 // Step is the basic block of a scenario.
@@ -16,6 +41,13 @@ type Step interface {
 	StepTypeName() string
 }
 
+// SourcePosition is the line and column where a step starts in its JSON file.
+// It is zero for steps that were not parsed from a file.
+type SourcePosition struct {
+	Line   int
+	Column int
+}
+
 // NewAddressMock allows tests to specify what new addresses to generate
 type NewAddressMock struct {
 	CreatorAddress JSONBytesFromString
@@ -52,6 +84,7 @@ type ExternalStepsStep struct {
 	Comment  string
 	TraceGas TraceGasStatus
 	Path     string
+	Position SourcePosition
 }
 
 // SetStateStep is a step where data is saved to the blockchain mock.
@@ -63,18 +96,34 @@ type SetStateStep struct {
 	CurrentBlockInfo  *BlockInfo
 	BlockHashes       JSONValueList
 	NewAddressMocks   []*NewAddressMock
+	Position          SourcePosition
 }
 
 // CheckStateStep is a step where the state of the blockchain mock is verified.
+// If GoldenPath is set, the expected accounts are loaded from that file when the step is executed,
+// and CheckAccounts is nil.
 type CheckStateStep struct {
 	CheckStateIdent string
 	Comment         string
 	CheckAccounts   *CheckAccounts
+	GoldenPath      string
+	Position        SourcePosition
 }
 
 // DumpStateStep is a step that simply prints the entire state to console. Useful for debugging.
 type DumpStateStep struct {
-	Comment string
+	Comment  string
+	Position SourcePosition
+}
+
+// SetGasScheduleStep is a step that switches the VM and the builtin functions to another gas schedule,
+// for the rest of the scenario.
+type SetGasScheduleStep struct {
+	Comment              string
+	GasSchedule          GasSchedule
+	GasSchedulePath      string
+	GasScheduleOverrides []*GasScheduleOverride
+	Position             SourcePosition
 }
 
 // TxStep is a step where a transaction is executed.
@@ -84,13 +133,25 @@ type TxStep struct {
 	DisplayLogs    bool
 	Tx             *Transaction
 	ExpectedResult *TransactionResult
+	Captures       []*Capture
+	Position       SourcePosition
+}
+
+// DeferredStep is a step that references variables captured by the steps before it.
+// It is kept as JSON, and only parsed when executed, once the values of the variables are known.
+type DeferredStep struct {
+	StepType string
+	JSON     oj.OJsonObject
+	Position SourcePosition
 }
 
 var _ Step = (*ExternalStepsStep)(nil)
 var _ Step = (*SetStateStep)(nil)
 var _ Step = (*CheckStateStep)(nil)
 var _ Step = (*DumpStateStep)(nil)
+var _ Step = (*SetGasScheduleStep)(nil)
 var _ Step = (*TxStep)(nil)
+var _ Step = (*DeferredStep)(nil)
 
 // StepNameExternalSteps is a json step type name.
 const StepNameExternalSteps = "externalSteps"
@@ -111,6 +172,9 @@ func (*SetStateStep) StepTypeName() string {
 // StepNameCheckState is a json step type name.
 const StepNameCheckState = "checkState"
 
+// GoldenAccountsPrefix marks checkState accounts that are stored in a separate golden file.
+const GoldenAccountsPrefix = "golden:"
+
 // StepTypeName type as string
 func (*CheckStateStep) StepTypeName() string {
 	return StepNameCheckState
@@ -124,6 +188,14 @@ func (*DumpStateStep) StepTypeName() string {
 	return StepNameDumpState
 }
 
+// StepNameSetGasSchedule is a json step type name.
+const StepNameSetGasSchedule = "setGasSchedule"
+
+// StepTypeName type as string
+func (*SetGasScheduleStep) StepTypeName() string {
+	return StepNameSetGasSchedule
+}
+
 // StepNameScCall is a json step type name.
 const StepNameScCall = "scCall"
 
@@ -161,3 +233,8 @@ func (t *TxStep) StepTypeName() string {
 		panic("unknown TransactionType")
 	}
 }
+
+// StepTypeName type as string, as given in the JSON of the step
+func (s *DeferredStep) StepTypeName() string {
+	return s.StepType
+}
@@ -79,24 +79,52 @@ type Transaction struct {
 	Arguments    []JSONBytesFromTree
 	GasPrice     JSONUint64
 	GasLimit     JSONUint64
+
+	// ABIFile is the contract ABI, normally loaded from a file, as "file:....abi.json".
+	// When present, the arguments and expected results are typed values, encoded according to ABI,
+	// the description of the called endpoint.
+	ABIFile JSONBytesFromString
+	ABI     *ABI
 }
 
 // TransactionResult is a json object representing an expected transaction result.
+// StateUnchanged requires the tx to leave all accounts as they were, except for the sender nonce and gas fee.
 type TransactionResult struct {
-	Out     JSONCheckValueList
-	Status  JSONCheckBigInt
-	Message JSONCheckBytes
-	Gas     JSONCheckUint64
-	Refund  JSONCheckBigInt
-	Logs    LogList
+	Out             JSONCheckValueList
+	Status          JSONCheckBigInt
+	Message         JSONCheckBytes
+	Gas             JSONCheckUint64
+	Refund          JSONCheckBigInt
+	Logs            LogList
+	OutputTransfers OutputTransferList
+	StateUnchanged  bool
 }
 
 // LogList is a container struct that holds log information
+// Unordered logs can match the actual logs in any order.
+// Actual logs with an identifier that matches one of the ignored identifiers are not checked.
 type LogList struct {
-	IsUnspecified    bool
-	IsStar           bool
-	MoreAllowedAtEnd bool
-	List             []*LogEntry
+	IsUnspecified      bool
+	IsStar             bool
+	MoreAllowedAtEnd   bool
+	Unordered          bool
+	IgnoredIdentifiers []JSONCheckBytes
+	List               []*LogEntry
+}
+
+// HasOptions yields true if the logs need to be written as an object, with options besides the list.
+func (ll LogList) HasOptions() bool {
+	return ll.Unordered || ll.IgnoredIdentifiers != nil
+}
+
+// IsIgnored yields true if the actual log identifier is one that is not checked.
+func (ll LogList) IsIgnored(identifier []byte) bool {
+	for _, ignored := range ll.IgnoredIdentifiers {
+		if ignored.Check(identifier) {
+			return true
+		}
+	}
+	return false
 }
 
 // LogEntry is a json object representing an expected transaction result log entry.
@@ -8,11 +8,12 @@ import (
 )
 
 // JSONCheckBytes holds a byte slice condition.
-// Values are checked for equality.
+// Values are checked for equality, unless a check operator is given.
 // "*" allows all values.
 type JSONCheckBytes struct {
 	Value       []byte
 	IsStar      bool
+	Operator    *CheckOperator
 	Original    oj.OJsonObject
 	Unspecified bool
 }
@@ -66,15 +67,19 @@ func (jcbytes JSONCheckBytes) Check(other []byte) bool {
 	if jcbytes.IsStar {
 		return true
 	}
+	if jcbytes.Operator != nil {
+		return jcbytes.Operator.CheckBytes(other)
+	}
 	return bytes.Equal(jcbytes.Value, other)
 }
 
 // JSONCheckBigInt holds a big int condition.
-// Values are checked for equality.
+// Values are checked for equality, unless a check operator is given.
 // "*" allows all values.
 type JSONCheckBigInt struct {
 	Value       *big.Int
 	IsStar      bool
+	Operator    *CheckOperator
 	Original    string
 	Unspecified bool
 }
@@ -100,15 +105,19 @@ func (jcbi JSONCheckBigInt) Check(other *big.Int) bool {
 	if jcbi.IsStar {
 		return true
 	}
+	if jcbi.Operator != nil {
+		return jcbi.Operator.CheckBigInt(other)
+	}
 	return jcbi.Value.Cmp(other) == 0
 }
 
 // JSONCheckUint64 holds a uint64 condition.
-// Values are checked for equality.
+// Values are checked for equality, unless a check operator is given.
 // "*" allows all values.
 type JSONCheckUint64 struct {
 	Value       uint64
 	IsStar      bool
+	Operator    *CheckOperator
 	Original    string
 	Unspecified bool
 }
@@ -134,6 +143,9 @@ func (jcu JSONCheckUint64) Check(other uint64) bool {
 	if jcu.IsStar {
 		return true
 	}
+	if jcu.Operator != nil {
+		return jcu.Operator.CheckBigInt(big.NewInt(0).SetUint64(other))
+	}
 	return jcu.Value == other
 }
 
@@ -143,14 +155,25 @@ func (jcu JSONCheckUint64) CheckBool(other bool) bool {
 	if jcu.IsStar {
 		return true
 	}
+	if jcu.Operator != nil {
+		if other {
+			return jcu.Check(1)
+		}
+		return jcu.Check(0)
+	}
 	return jcu.Value > 0 == other
 }
 
+// JSONCheckValueListMore is the last element of a list of value checks that allows any number of further values.
+const JSONCheckValueListMore = "..."
+
 // JSONCheckValueList represents a list of value checks, as expressed in JSON.
+// MoreAllowed is set by a "..." last element, then only the first values are checked.
 // TODO: add star for all values
 type JSONCheckValueList struct {
 	Values      []JSONCheckBytes
 	IsStar      bool
+	MoreAllowed bool
 	Unspecified bool
 }
 
@@ -182,7 +205,11 @@ func (jcbl JSONCheckValueList) CheckList(other [][]byte) bool {
 	if jcbl.IsStar {
 		return true
 	}
-	if len(jcbl.Values) != len(other) {
+	if jcbl.MoreAllowed {
+		if len(other) < len(jcbl.Values) {
+			return false
+		}
+	} else if len(jcbl.Values) != len(other) {
 		return false
 	}
 	for i, expected := range jcbl.Values {
@@ -16,6 +16,11 @@ var WorldMarshalizer = &marshal.GogoProtoMarshalizer{}
 // GasScheduleMap (alias) is the map for gas schedule
 type GasScheduleMap = map[string]map[string]uint64
 
+// gasScheduleChangeHandler updates the gas costs of the builtin functions it created.
+type gasScheduleChangeHandler interface {
+	GasScheduleChange(gasSchedule map[string]map[string]uint64)
+}
+
 // BuiltinFunctionsWrapper manages and initializes a BuiltInFunctionContainer
 // along with its dependencies
 type BuiltinFunctionsWrapper struct {
@@ -23,6 +28,8 @@ type BuiltinFunctionsWrapper struct {
 	MapDNSAddresses map[string]struct{}
 	World           *MockWorld
 	Marshalizer     vmcommon.Marshalizer
+
+	gasScheduleChanger gasScheduleChangeHandler
 }
 
 // NewBuiltinFunctionsWrapper creates a new BuiltinFunctionsWrapper with
@@ -65,11 +72,18 @@ func NewBuiltinFunctionsWrapper(
 		Container:       builtinFuncFactory.BuiltInFunctionContainer(),
 		MapDNSAddresses: argsBuiltIn.MapDNSAddresses,
 		World:           world,
+
+		gasScheduleChanger: builtinFuncFactory,
 	}
 
 	return builtinFuncsWrapper, nil
 }
 
+// GasScheduleChange switches the builtin functions in the container to a new gas schedule.
+func (bf *BuiltinFunctionsWrapper) GasScheduleChange(gasMap GasScheduleMap) {
+	bf.gasScheduleChanger.GasScheduleChange(gasMap)
+}
+
 // ProcessBuiltInFunction delegates the execution of a real builtin function to
 // the inner BuiltInFunctionContainer.
 func (bf *BuiltinFunctionsWrapper) ProcessBuiltInFunction(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, error) {